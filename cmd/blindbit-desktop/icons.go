@@ -0,0 +1,21 @@
+package main
+
+import (
+	_ "embed"
+
+	"fyne.io/fyne/v2"
+)
+
+// icon.png is also referenced by FyneApp.toml for packaging.
+// icon_syncing.png is the same icon with a badge, shown in the tray while scanning.
+
+//go:embed icon.png
+var iconPNG []byte
+
+//go:embed icon_syncing.png
+var iconSyncingPNG []byte
+
+var (
+	appIcon     = fyne.NewStaticResource("icon.png", iconPNG)
+	syncingIcon = fyne.NewStaticResource("icon_syncing.png", iconSyncingPNG)
+)
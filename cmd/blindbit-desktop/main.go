@@ -2,32 +2,60 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
 
-	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/app"
 	"fyne.io/fyne/v2/dialog"
-	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/widget"
 	"github.com/rs/zerolog"
 
 	"github.com/setavenger/blindbit-desktop/internal/configs"
 	"github.com/setavenger/blindbit-desktop/internal/controller"
 	"github.com/setavenger/blindbit-desktop/internal/gui"
+	"github.com/setavenger/blindbit-desktop/internal/logbuffer"
 	"github.com/setavenger/blindbit-desktop/internal/setup"
 	"github.com/setavenger/blindbit-desktop/internal/storage"
 	"github.com/setavenger/blindbit-lib/logging"
+	"github.com/setavenger/blindbit-lib/types"
 	"github.com/setavenger/blindbit-lib/utils"
 	"github.com/spf13/pflag"
 )
 
-var dataDir string
+// appID has to match the ID in FyneApp.toml
+const appID = "com.setavenger.blindbit"
+
+var (
+	dataDir string
+	// debug is set via --debug and overrides the stored log level
+	debug bool
+	// network is empty unless set via --network
+	network types.Network
+)
 
 func init() {
-	var debug bool
+	var networkName string
 	pflag.BoolVar(&debug, "debug", false, "enable debug logging")
 	pflag.StringVar(&dataDir, "datadir", "", "path to data directory for BlindBit Desktop")
+	pflag.StringVar(
+		&networkName,
+		"network",
+		"",
+		"network to use: mainnet, testnet, signet or regtest (a wallet created for another network is refused)",
+	)
 	pflag.Parse()
 
+	if networkName != "" {
+		var err error
+		network, err = configs.ParseNetwork(networkName)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "invalid --network:", err)
+			os.Exit(2)
+		}
+	}
+
+	gui.DebugLogging = debug
 	if debug {
 		logging.SetLogLevel(zerolog.TraceLevel)
 	} else {
@@ -36,26 +64,44 @@ func init() {
 }
 
 func main() {
-	// Create a new Fyne application
-	myApp := app.New()
+	// Create a new Fyne application, the ID (same as in FyneApp.toml) scopes the preferences
+	myApp := app.NewWithID(appID)
 
-	myApp.SetIcon(myApp.Icon()) // You can replace this with a custom icon
+	myApp.SetIcon(appIcon)
+	gui.ApplyTheme(myApp)
 
 	// Create the main window
 	mainWindow := myApp.NewWindow("BlindBit Desktop")
+	mainWindow.SetIcon(appIcon)
 
-	mainWindow.Resize(fyne.NewSize(1000, 750))
-	mainWindow.CenterOnScreen()
+	// restores the size of the last run, the tab is restored by the main GUI
+	gui.RestoreWindowSize(myApp, mainWindow)
 
-	// Try to load existing wallet manager
-	walletManager, exists, err := setup.NewManagerWithDataDir(dataDir)
-	if err != nil {
-		logging.L.Err(err).Msg("Failed to load existing wallet manager")
-		// Show error dialog and exit
-		dialog.ShowError(fmt.Errorf("failed to load wallet: %v", err), mainWindow)
-		return
+	// mainGUI refreshes its tabs until Cleanup, it is rebuilt when the window comes back from the tray
+	var mainGUI *gui.MainGUI
+	myApp.Lifecycle().SetOnStopped(func() {
+		gui.SaveWindowSize(myApp, mainWindow)
+		// keeps the scroll positions for the next run
+		if mainGUI != nil {
+			mainGUI.Cleanup()
+		}
+	})
+
+	// Tray shows balance and sync status, the wallet is attached once loaded
+	tray := gui.NewTray(myApp, mainWindow, nil)
+	if tray != nil {
+		tray.SetIcons(appIcon, syncingIcon)
+		tray.Start()
+	}
+
+	// the default data directory may have been moved
+	if dataDir == "" {
+		dataDir = gui.DefaultDataDir(myApp)
 	}
 
+	// Try to load existing wallet manager
+	walletManager, exists, loadErr := setup.NewManagerWithDataDir(dataDir)
+
 	// Get the resolved data directory for consistency
 	resolvedDataDir := dataDir
 	if resolvedDataDir == "" {
@@ -64,100 +110,223 @@ func main() {
 		resolvedDataDir = utils.ResolvePath(resolvedDataDir)
 	}
 
-	if err = logging.EnableFileLogging(resolvedDataDir, "debug.log"); err != nil {
-		fmt.Println("base_dir:", resolvedDataDir)
-		logging.L.Fatal().Err(err).Msg("error setting log file")
+	if err := configs.RotateLogFile(resolvedDataDir); err != nil {
+		logging.L.Err(err).Msg("failed to rotate log file")
 	}
+	if err := logging.EnableFileLogging(resolvedDataDir, configs.LogFilename); err != nil {
+		logging.L.Fatal().Err(err).Str("datadir", resolvedDataDir).Msg("error setting log file")
+	}
+	// keeps recent lines for the Logs tab
+	logbuffer.Attach()
 
-	if !exists {
-		// No wallet exists, show setup wizard
+	// a printable seed backup of the last run may still be in the data dir
+	gui.RemovePrintPages(resolvedDataDir)
+
+	gui.RememberProfile(myApp, resolvedDataDir)
+
+	showMainGUI := func(manager *controller.Manager) {
+		if mainGUI != nil {
+			mainGUI.Cleanup()
+		}
+		mainGUI = gui.NewMainGUI(myApp, mainWindow, manager)
+		mainWindow.SetContent(mainGUI.GetContent())
+		mainGUI.RestoreScrollPositions()
+	}
+
+	// showSetup runs the setup wizard, the main GUI is shown once it completes
+	showSetup := func() {
 		setupWizard := gui.NewSetupWizard(
 			myApp, mainWindow, resolvedDataDir, func(manager *controller.Manager) {
 				// Initialize scanner before showing main GUI
-				if err := manager.ConstructScanner(context.TODO()); err != nil {
+				if err := manager.ConstructScanner(context.TODO()); errors.Is(err, controller.ErrOracleNetworkMismatch) {
+					// scanning stays off, the oracle can be changed in settings
+					dialog.ShowError(fmt.Errorf("scanning disabled: %v", err), mainWindow)
+				} else if err != nil {
 					logging.L.Err(err).Msg("failed to construct scanner after setup")
 					dialog.ShowError(fmt.Errorf("failed to construct scanner: %v", err), mainWindow)
 					return
 				}
 
 				// Start channel handling and background scanning
-				manager.StartChannelHandling(context.TODO(), func() error {
+				manager.StartChannelHandling(manager.Context(), func() error {
 					return storage.SavePlain(manager.DataDir, manager)
 				})
 
-				go func() {
-					watchStartHeight := manager.Wallet.LastScanHeight
-					if watchStartHeight == 0 {
-						watchStartHeight = manager.Wallet.BirthHeight
-					}
-					if err := manager.Scanner.Watch(context.TODO(), uint32(watchStartHeight)); err != nil {
-						logging.L.Err(err).Msg("failed to watch scanner")
-						dialog.ShowError(fmt.Errorf("failed to watch scanner: %v", err), mainWindow)
-						return
-					}
-				}()
+				watchStartHeight := manager.ScanHeight()
+				if watchStartHeight == 0 {
+					watchStartHeight = manager.Wallet.BirthHeight
+				}
+				manager.StartWatching(manager.Context(), uint32(watchStartHeight), func(err error) {
+					dialog.ShowError(fmt.Errorf("failed to watch scanner: %v", err), mainWindow)
+				})
 
 				walletManager = manager
+				if tray != nil {
+					tray.SetManager(manager)
+				}
 				// Setup completed, show main GUI
-				mainGUI := gui.NewMainGUI(myApp, mainWindow, manager)
-				mainWindow.SetContent(mainGUI.GetContent())
+				showMainGUI(manager)
 			},
 		)
+		if network != "" {
+			setupWizard.SetNetwork(network)
+		}
 		setupWizard.Show()
-	} else {
+	}
+
+	switch {
+	case errors.Is(loadErr, storage.ErrWalletCorrupt):
+		logging.L.Err(loadErr).Msg("wallet file and its backup are unreadable")
+		dialog.ShowCustomConfirm(
+			"Wallet File Corrupt",
+			"Restore From Seed",
+			"Quit",
+			widget.NewLabel(
+				"Neither the wallet file nor its backup could be read.\n"+
+					"You can restore the wallet from your seed phrase. The damaged file is kept in the data directory.",
+			),
+			func(restore bool) {
+				if !restore {
+					myApp.Quit()
+					return
+				}
+				path, err := storage.SetAsideCorruptWallet(resolvedDataDir)
+				if err != nil {
+					logging.L.Err(err).Msg("failed to move corrupt wallet file")
+					dialog.ShowError(fmt.Errorf("failed to move corrupt wallet file: %v", err), mainWindow)
+					return
+				}
+				logging.L.Warn().Str("path", path).Msg("moved corrupt wallet file aside")
+				showSetup()
+			},
+			mainWindow,
+		)
+	case loadErr != nil:
+		logging.L.Err(loadErr).Msg("Failed to load existing wallet manager")
+		dialog.ShowError(fmt.Errorf("failed to load wallet: %v", loadErr), mainWindow)
+	case !exists:
+		// No wallet exists, show setup wizard
+		showSetup()
+	default:
 		// Set the DataDir on the loaded manager
 		walletManager.DataDir = resolvedDataDir
 
+		if walletManager.RestoredFromBackup {
+			dialog.ShowInformation(
+				"Wallet Restored From Backup",
+				"The wallet file could not be read, the backup of the previous save was loaded instead.\n"+
+					"Recent scan progress may be repeated.",
+				mainWindow,
+			)
+		}
+
+		if network != "" {
+			if err := walletManager.CheckNetwork(network); err != nil {
+				logging.L.Err(err).Msg("refusing to open wallet")
+				// nothing was started, there is nothing to stop or save on exit
+				walletManager = nil
+				dialog.ShowError(fmt.Errorf(
+					"%v\n\nOpen a data directory created for %s or start without --network", err, network,
+				), mainWindow)
+				break
+			}
+		}
+
+		if !debug {
+			if level, err := configs.ParseLogLevel(walletManager.LogLevel); err == nil {
+				logging.SetLogLevel(level)
+			} else {
+				logging.L.Warn().Err(err).Msg("ignoring stored log level")
+			}
+		}
+		if !walletManager.LogToFile {
+			err := logging.DisableFileLogging()
+			logbuffer.Attach()
+			if err != nil {
+				logging.L.Err(err).Msg("failed to disable file logging")
+			}
+		}
+
+		walletManager.ApplyCPULimit()
+
 		// Initialize scanner before showing main GUI
-		if err := walletManager.ConstructScanner(context.TODO()); err != nil {
+		if err := walletManager.ConstructScanner(context.TODO()); errors.Is(err, controller.ErrOracleNetworkMismatch) {
+			// scanning stays off, the oracle can be changed in settings
+			dialog.ShowError(fmt.Errorf("scanning disabled: %v", err), mainWindow)
+		} else if err != nil {
 			logging.L.Err(err).Msg("failed to construct scanner")
 			dialog.ShowError(fmt.Errorf("failed to construct scanner: %v", err), mainWindow)
 			return
 		}
 
 		// Start channel handling and background scanning
-		walletManager.StartChannelHandling(context.TODO(), func() error {
+		walletManager.StartChannelHandling(walletManager.Context(), func() error {
 			return storage.SavePlain(walletManager.DataDir, walletManager)
 		})
 
-		go func() {
-			err := walletManager.Scanner.Watch(
-				context.TODO(), uint32(walletManager.Wallet.LastScanHeight),
-			)
-			if err != nil {
-				logging.L.Err(err).Msg("failed to watch scanner")
+		walletManager.StartWatching(
+			walletManager.Context(),
+			uint32(walletManager.ScanHeight()),
+			func(err error) {
 				dialog.ShowError(fmt.Errorf("failed to watch scanner: %v", err), mainWindow)
-				return
-			}
-		}()
+			},
+		)
+
+		if tray != nil {
+			tray.SetManager(walletManager)
+		}
 
 		// Wallet loaded successfully, show main GUI
-		mainGUI := gui.NewMainGUI(myApp, mainWindow, walletManager)
-		mainWindow.SetContent(mainGUI.GetContent())
+		showMainGUI(walletManager)
 	}
 
-	if walletManager != nil {
-		defer storage.SavePlain(walletManager.DataDir, walletManager)
-	}
+	// walletManager is only set after the setup wizard completes, so check on exit.
+	// Runs once the app quits: stops scanning before the final save.
+	defer func() {
+		if mainGUI != nil {
+			mainGUI.Cleanup()
+		}
+		gui.RemovePrintPages(resolvedDataDir)
+		if walletManager == nil {
+			return
+		}
+		if walletManager.DataDir != resolvedDataDir {
+			// another profile or a moved data dir
+			gui.RemovePrintPages(walletManager.DataDir)
+		}
+		err := walletManager.Shutdown(controller.DefaultShutdownTimeout, func() error {
+			return storage.SavePlain(walletManager.DataDir, walletManager)
+		})
+		if err != nil {
+			logging.L.Err(err).Msg("unclean shutdown")
+		}
+	}()
 
-	// Tray settings
-	if desk, ok := myApp.(desktop.App); ok {
-		logging.L.Debug().Msg("Is desktop app")
-		m := fyne.NewMenu("BlindBit",
-			fyne.NewMenuItem("Show", func() {
-				mainWindow.Show()
-			}))
-		desk.SetSystemTrayMenu(m)
-		// desk.SetSystemTrayIcon(fyne.CurrentApp().Icon())
-		if myApp.Metadata().Icon != nil {
-			logging.L.Trace().Msg("have metadata icon")
-			desk.SetSystemTrayIcon(myApp.Metadata().Icon) // tray
-		} else {
-			logging.L.Trace().Msg("failed to find metadata icon")
+	mainWindow.SetCloseIntercept(func() {
+		keepRunning := walletManager != nil && walletManager.KeepRunningInTray
+		if !keepRunning || tray == nil {
+			// without a tray there is no way to bring the window back
+			if mainGUI != nil {
+				mainGUI.Cleanup()
+			}
+			myApp.Quit()
+			return
 		}
-	}
+		tray.HideWindow()
+	})
 
-	mainWindow.SetCloseIntercept(func() { mainWindow.Hide() })
+	if tray != nil {
+		// a hidden window needs no refreshes, scanning continues in the manager
+		tray.SetOnVisibilityChanged(func(visible bool) {
+			switch {
+			case mainGUI == nil:
+			case !visible:
+				mainGUI.Cleanup()
+			case !mainGUI.Running():
+				showMainGUI(walletManager)
+			}
+		})
+	}
 
 	// Show and run the application
 	mainWindow.ShowAndRun()
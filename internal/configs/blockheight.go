@@ -0,0 +1,96 @@
+package configs
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/setavenger/blindbit-lib/types"
+)
+
+const (
+	// targetBlockTime is the average block interval used to estimate heights
+	targetBlockTime = 10 * time.Minute
+	// blockHeightSafetyMargin moves estimates about two weeks earlier,
+	// scanning a few extra blocks is better than missing the first payment
+	blockHeightSafetyMargin = 2016
+)
+
+// blockAnchor is a known block used as reference point for height estimates
+type blockAnchor struct {
+	height    uint64
+	timestamp int64
+}
+
+var blockAnchors = map[types.Network]blockAnchor{
+	// the 2024 halving block
+	types.NetworkMainnet: {height: 840_000, timestamp: 1713571767},
+	// genesis, signet blocks are mined close to the target interval
+	types.NetworkSignet: {height: 0, timestamp: 1598918400},
+}
+
+// GetBlockHeightByDate looks up the last block mined before date via mempool.space.
+// Falls back to EstimateBlockHeightByDate if the lookup fails.
+func GetBlockHeightByDate(network types.Network, date time.Time) (uint64, error) {
+	height, err := fetchBlockHeightByTimestamp(network, date)
+	if err == nil {
+		return height, nil
+	}
+
+	estimate, estimateErr := EstimateBlockHeightByDate(network, date)
+	if estimateErr != nil {
+		return 0, fmt.Errorf("failed to look up block height: %w", err)
+	}
+	return estimate, nil
+}
+
+// EstimateBlockHeightByDate approximates the block height at date from a known anchor block
+// and the target block interval. The result errs on the early side.
+// Testnet and regtest are not supported, their block times are too irregular.
+func EstimateBlockHeightByDate(network types.Network, date time.Time) (uint64, error) {
+	anchor, ok := blockAnchors[network]
+	if !ok {
+		return 0, fmt.Errorf("no block height estimate available for %s", network)
+	}
+
+	blocks := date.Unix() - anchor.timestamp
+	estimate := int64(anchor.height) + blocks/int64(targetBlockTime.Seconds()) - blockHeightSafetyMargin
+	if estimate < 0 {
+		return 0, nil
+	}
+	return uint64(estimate), nil
+}
+
+func fetchBlockHeightByTimestamp(network types.Network, date time.Time) (uint64, error) {
+	if network == types.NetworkRegtest {
+		return 0, fmt.Errorf("unsupported network")
+	}
+
+	url := fmt.Sprintf(
+		"%s/api/v1/mining/blocks/timestamp/%d", GetMempoolSpaceURL(network), date.Unix(),
+	)
+
+	client := &http.Client{
+		Timeout: 10 * time.Second,
+	}
+
+	resp, err := client.Get(url)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch block by timestamp: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("received non-200 status code: %d", resp.StatusCode)
+	}
+
+	var block struct {
+		Height uint64 `json:"height"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&block); err != nil {
+		return 0, fmt.Errorf("failed to decode block: %w", err)
+	}
+
+	return block.Height, nil
+}
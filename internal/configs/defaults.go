@@ -33,6 +33,36 @@ const (
 	DefaultNetwork              = "signet"
 	DefaultMinimumAmount        = 546
 	DefaultLabelCount           = 0
+	// MaxLabelCount bounds the label count, every label adds work to each scanned block
+	MaxLabelCount = 1000
+	// DefaultConfirmationTarget is the number of confirmations after which
+	// a transaction counts as fully confirmed
+	DefaultConfirmationTarget = 6
+	// DefaultMinConfirmations is the number of confirmations a UTXO needs before coin selection uses it
+	DefaultMinConfirmations = 1
+	// DefaultSaveEveryBlocks and DefaultSaveIntervalSeconds control how often
+	// the wallet is written to disk while scanning
+	DefaultSaveEveryBlocks     = 100
+	DefaultSaveIntervalSeconds = 15
+	// DefaultChainTipRefreshSeconds and DefaultUTXORefreshSeconds control how often
+	// the GUI polls the chain tip and reloads the UTXOs
+	DefaultChainTipRefreshSeconds = 10
+	DefaultUTXORefreshSeconds     = 60
+	// MinRefreshSeconds keeps the GUI from hammering the oracle
+	MinRefreshSeconds = 5
+	// DefaultMinFeeRate is the lowest fee rate in sat/vB a transaction is built with,
+	// the default minimum relay fee of Bitcoin Core
+	DefaultMinFeeRate = 1
+	// HighFeeRate in sat/vB and above needs a confirmation before a transaction is built
+	HighFeeRate = 1000
+	// DefaultTipPollsPerSecond is a polite chain tip polling rate for public oracles,
+	// an oracle on localhost gets DefaultLocalTipPollsPerSecond
+	DefaultTipPollsPerSecond      = 2
+	DefaultLocalTipPollsPerSecond = 20
+	// TipPollBurst is the number of polls sent at once before the rate applies
+	TipPollBurst = 5
+	// DefaultCPULimitPercent is the share of the CPU cores the wallet may use
+	DefaultCPULimitPercent = 100
 )
 
 // DefaultOracleAddressForNetwork returns the default oracle address for a given network.
@@ -47,6 +77,17 @@ func DefaultOracleAddressForNetwork(n types.Network) string {
 	}
 }
 
+// ParseNetwork validates a network name as given on the command line
+func ParseNetwork(name string) (types.Network, error) {
+	network := types.Network(name)
+	switch network {
+	case types.NetworkMainnet, types.NetworkTestnet, types.NetworkSignet, types.NetworkRegtest:
+		return network, nil
+	default:
+		return "", fmt.Errorf("unknown network %q, expected mainnet, testnet, signet or regtest", name)
+	}
+}
+
 // GetCurrentBlockHeight fetches the current block height for a given network from mempool.space.
 // Returns 0 if the request fails or the height cannot be parsed.
 func GetCurrentBlockHeight(network types.Network) (uint64, error) {
@@ -0,0 +1,17 @@
+package configs
+
+import "github.com/setavenger/blindbit-lib/types"
+
+// DefaultElectrumAddressForNetwork returns a public Electrum server for the given network.
+// Returns an empty string for networks without a known public server (e.g. regtest).
+func DefaultElectrumAddressForNetwork(network types.Network) string {
+	switch network {
+	case types.NetworkMainnet:
+		return "ssl://electrum.blockstream.info:50002"
+	case types.NetworkTestnet:
+		return "ssl://electrum.blockstream.info:60002"
+	case types.NetworkSignet:
+		return "ssl://mempool.space:60602"
+	}
+	return ""
+}
@@ -0,0 +1,70 @@
+package configs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/rs/zerolog"
+)
+
+// LogFilename is the log file in the data directory
+const LogFilename = "debug.log"
+
+// DefaultLogLevel is used when no log level is stored
+const DefaultLogLevel = "info"
+
+// maxLogFileSize is the size after which the log file is rotated on startup.
+// logFileGenerations old files are kept as debug.log.1, debug.log.2, ...
+const (
+	maxLogFileSize     = 10 << 20
+	logFileGenerations = 3
+)
+
+// LogLevels are the levels that can be picked in Settings, most verbose first
+var LogLevels = []string{"trace", "debug", "info", "warn", "error"}
+
+// ParseLogLevel turns a stored level name into a zerolog level, empty means DefaultLogLevel
+func ParseLogLevel(name string) (zerolog.Level, error) {
+	if name == "" {
+		name = DefaultLogLevel
+	}
+	for _, level := range LogLevels {
+		if level == name {
+			return zerolog.ParseLevel(name)
+		}
+	}
+	return zerolog.NoLevel, fmt.Errorf("unknown log level %q", name)
+}
+
+// LogFilePath returns the path of the log file in dataDir
+func LogFilePath(dataDir string) string {
+	return filepath.Join(dataDir, LogFilename)
+}
+
+// RotateLogFile moves the log file aside once it has grown past maxLogFileSize.
+// Call it before file logging is enabled, the oldest generation is dropped.
+func RotateLogFile(dataDir string) error {
+	path := LogFilePath(dataDir)
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if info.Size() < maxLogFileSize {
+		return nil
+	}
+
+	for i := logFileGenerations - 1; i > 0; i-- {
+		older := fmt.Sprintf("%s.%d", path, i)
+		if err := os.Rename(older, fmt.Sprintf("%s.%d", path, i+1)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to rotate log file: %w", err)
+		}
+	}
+	if err := os.Rename(path, path+".1"); err != nil {
+		return fmt.Errorf("failed to rotate log file: %w", err)
+	}
+	return nil
+}
@@ -1,6 +1,13 @@
 package configs
 
-import "github.com/setavenger/blindbit-lib/types"
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/setavenger/blindbit-lib/types"
+)
 
 func GetMempoolSpaceURL(network types.Network) string {
 	switch network {
@@ -13,3 +20,64 @@ func GetMempoolSpaceURL(network types.Network) string {
 	}
 	return "https://mempool.space"
 }
+
+// Placeholders in a custom explorer URL template
+const (
+	ExplorerTxIDPlaceholder = "{txid}"
+	ExplorerVoutPlaceholder = "{vout}"
+)
+
+// HasPublicExplorer reports whether transactions on network can be looked up.
+// Regtest chains are local only unless a custom explorer is set.
+func HasPublicExplorer(network types.Network, customURL string) bool {
+	return customURL != "" || network != types.NetworkRegtest
+}
+
+// explorerTemplate returns the tx page template for network.
+// A custom URL without {txid} is taken as base of a mempool or esplora instance.
+func explorerTemplate(network types.Network, customURL string) string {
+	if customURL == "" {
+		return GetMempoolSpaceURL(network) + "/tx/" + ExplorerTxIDPlaceholder
+	}
+	if !strings.Contains(customURL, ExplorerTxIDPlaceholder) {
+		return strings.TrimRight(customURL, "/") + "/tx/" + ExplorerTxIDPlaceholder
+	}
+	return customURL
+}
+
+// GetExplorerTxURL returns the explorer page of a transaction,
+// customURL overrides mempool.space if set
+func GetExplorerTxURL(network types.Network, customURL, txid string) string {
+	template := explorerTemplate(network, customURL)
+	template = strings.ReplaceAll(template, ExplorerVoutPlaceholder, "")
+	return strings.ReplaceAll(template, ExplorerTxIDPlaceholder, txid)
+}
+
+// GetExplorerOutputURL returns the explorer page of a transaction with output vout highlighted.
+// Templates without {vout} get the mempool.space #vout=N anchor.
+func GetExplorerOutputURL(network types.Network, customURL, txid string, vout uint32) string {
+	template := explorerTemplate(network, customURL)
+	if !strings.Contains(template, ExplorerVoutPlaceholder) {
+		template += "#vout=" + ExplorerVoutPlaceholder
+	}
+	template = strings.ReplaceAll(template, ExplorerVoutPlaceholder, strconv.FormatUint(uint64(vout), 10))
+	return strings.ReplaceAll(template, ExplorerTxIDPlaceholder, txid)
+}
+
+// ValidateExplorerURL checks a custom explorer URL or template, empty is valid and means default
+func ValidateExplorerURL(customURL string) error {
+	if customURL == "" {
+		return nil
+	}
+	u, err := url.Parse(GetExplorerTxURL(types.NetworkMainnet, customURL, "txid"))
+	if err != nil {
+		return fmt.Errorf("failed to parse explorer URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("explorer URL must start with http:// or https://")
+	}
+	if u.Host == "" {
+		return fmt.Errorf("explorer URL has no host")
+	}
+	return nil
+}
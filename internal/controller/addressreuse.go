@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/setavenger/blindbit-lib/types"
+	"github.com/setavenger/go-bip352"
+)
+
+// AddressReuse describes earlier payments to a recipient address
+type AddressReuse struct {
+	// Payments counts the sent transactions which paid the address
+	Payments int
+	// LastTxID is the most recent of those transactions
+	LastTxID string
+	// LastHeight is its confirmation height, 0 while pending
+	LastHeight int
+}
+
+// PreviousPayments looks through the sent transactions in the history for outputs to address.
+// Silent payment addresses never produce the same output twice, they always report nil.
+func (m *Manager) PreviousPayments(address string) (*AddressReuse, error) {
+	if bip352.IsSilentPaymentAddress(address) {
+		return nil, nil
+	}
+
+	params, ok := types.NetworkParams[m.GetNetwork()]
+	if !ok {
+		return nil, fmt.Errorf("unsupported network: %s", m.GetNetwork())
+	}
+	decoded, err := btcutil.DecodeAddress(address, params)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address: %w", err)
+	}
+	pkScript, err := txscript.PayToAddrScript(decoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address: %w", err)
+	}
+
+	m.historyMu.RLock()
+	defer m.historyMu.RUnlock()
+
+	var reuse AddressReuse
+	for _, item := range m.TransactionHistory {
+		view, err := txItemJSON(item)
+		if err != nil {
+			return nil, err
+		}
+		if len(view.TxIns) == 0 {
+			// nothing of ours was spent, not a payment we made
+			continue
+		}
+		for _, out := range view.TxOuts {
+			if out.Self || !bytes.Equal(out.Pubkey, pkScript) {
+				continue
+			}
+			reuse.Payments++
+			if reuse.LastTxID == "" || isLaterHeight(view.ConfirmHeight, reuse.LastHeight) {
+				reuse.LastTxID = view.TxID
+				reuse.LastHeight = view.ConfirmHeight
+			}
+			break
+		}
+	}
+
+	if reuse.Payments == 0 {
+		return nil, nil
+	}
+	return &reuse, nil
+}
+
+// isLaterHeight orders confirmation heights with pending (0) after any confirmed one
+func isLaterHeight(height, than int) bool {
+	if than == 0 {
+		return false
+	}
+	return height == 0 || height > than
+}
@@ -0,0 +1,76 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/setavenger/blindbit-desktop/internal/power"
+	"github.com/setavenger/blindbit-lib/logging"
+)
+
+// batteryCheckInterval is how often the power source is read while PauseScanningOnBattery is set
+const batteryCheckInterval = time.Minute
+
+// startBatteryMonitor pauses the watcher while the computer runs on battery
+// and PauseScanningOnBattery is set, until ctx is done.
+// The first check happens right away, so a wallet opened on battery does not start scanning.
+func (m *Manager) startBatteryMonitor(ctx context.Context) {
+	m.checkPowerSource(ctx)
+
+	m.workers.Add(1)
+	go func() {
+		defer m.workers.Done()
+
+		ticker := time.NewTicker(batteryCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				m.checkPowerSource(ctx)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// CheckPowerSource applies a changed PauseScanningOnBattery right away instead of on the next check
+func (m *Manager) CheckPowerSource() {
+	m.checkPowerSource(m.Context())
+}
+
+// ScanningPausedOnBattery reports whether the watcher is stopped because the computer runs on battery
+func (m *Manager) ScanningPausedOnBattery() bool {
+	m.lifecycleMu.Lock()
+	defer m.lifecycleMu.Unlock()
+	return m.batteryPaused
+}
+
+// checkPowerSource pauses or resumes the watcher for the current power source.
+// Where the power source can't be read scanning is never paused.
+func (m *Manager) checkPowerSource(ctx context.Context) {
+	var onBattery bool
+	if m.PauseScanningOnBattery {
+		var err error
+		onBattery, err = power.OnBattery()
+		if err != nil && !errors.Is(err, power.ErrUnsupported) {
+			logging.L.Warn().Err(err).Msg("failed to read power source")
+		}
+	}
+
+	m.lifecycleMu.Lock()
+	paused := m.batteryPaused
+	m.batteryPaused = onBattery
+	m.lifecycleMu.Unlock()
+
+	switch {
+	case onBattery && !paused:
+		m.stopWatching()
+		logging.L.Info().Uint64("scan_height", m.ScanHeight()).Msg("on battery, scanning paused")
+	case !onBattery && paused:
+		logging.L.Info().Msg("on AC power, scanning resumed")
+		m.restartWatching(ctx)
+	}
+}
@@ -0,0 +1,91 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/setavenger/blindbit-desktop/internal/configs"
+	"github.com/setavenger/blindbit-lib/logging"
+	"github.com/setavenger/blindbit-lib/types"
+)
+
+// ErrBirthHeightAboveTip is returned for a birth height the chain has not reached yet.
+// Scanning would skip every block until then.
+var ErrBirthHeightAboveTip = errors.New("birth height is above the current chain tip")
+
+// minBirthHeights is the lowest sensible birth height per network.
+// Silent payments only pay to taproot outputs, nothing before taproot activation is relevant.
+var minBirthHeights = map[types.Network]uint64{
+	types.NetworkMainnet: 709_632,
+}
+
+// ValidateBirthHeight checks height before it is passed to SetBirthHeight.
+// Heights below taproot activation are clamped up, the returned height is the one to use.
+// Heights above the chain tip are rejected. The warning is set if the wallet already
+// has activity below height, the birth height would hide it on a rescan.
+func (m *Manager) ValidateBirthHeight(height uint64) (uint64, string, error) {
+	network := m.GetNetwork()
+
+	if minHeight := minBirthHeights[network]; height < minHeight {
+		logging.L.Info().
+			Uint64("height", height).
+			Uint64("min_height", minHeight).
+			Msg("birth height below taproot activation, clamping")
+		height = minHeight
+	}
+
+	tip, err := m.chainTip()
+	if err != nil {
+		// not being able to check is no reason to block the user
+		logging.L.Warn().Err(err).Msg("could not check birth height against chain tip")
+	} else if height > tip {
+		return 0, "", fmt.Errorf("%w: %d > %d", ErrBirthHeightAboveTip, height, tip)
+	}
+
+	var warning string
+	if first := m.firstActivityHeight(); first > 0 && height > first {
+		warning = fmt.Sprintf(
+			"Birth height %d is after the wallet's first activity at height %d. "+
+				"Rescans from the birth height will not find those coins.",
+			height, first,
+		)
+	}
+
+	return height, warning, nil
+}
+
+// chainTip asks the oracle and falls back to mempool.space before the oracle is connected
+func (m *Manager) chainTip() (uint64, error) {
+	tip, err := m.GetCurrentHeight()
+	if err == nil {
+		return uint64(tip), nil
+	}
+	if errors.Is(err, ErrOffline) {
+		return 0, err
+	}
+	return configs.GetCurrentBlockHeight(m.GetNetwork())
+}
+
+// firstActivityHeight returns the lowest confirmation height of the wallet's UTXOs
+// and history, 0 if there is none
+func (m *Manager) firstActivityHeight() uint64 {
+	var first uint64
+	lower := func(height uint64) {
+		if height > 0 && (first == 0 || height < first) {
+			first = height
+		}
+	}
+
+	for _, utxo := range m.GetUTXOs() {
+		lower(uint64(utxo.Height))
+	}
+	m.historyMu.RLock()
+	for _, item := range m.TransactionHistory {
+		if item.ConfirmHeight > 0 {
+			lower(uint64(item.ConfirmHeight))
+		}
+	}
+	m.historyMu.RUnlock()
+
+	return first
+}
@@ -0,0 +1,251 @@
+package controller
+
+import (
+	"bytes"
+	"math"
+	"slices"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/setavenger/blindbit-lib/wallet"
+)
+
+// CoinSelectionStrategy decides in which order UTXOs are offered to the transaction builder.
+// The builder takes UTXOs in the given order until the target is covered,
+// so the ordering (and for branch-and-bound the subset) determines the inputs.
+type CoinSelectionStrategy string
+
+const (
+	// CoinSelectionLargestFirst spends as few inputs as possible
+	CoinSelectionLargestFirst CoinSelectionStrategy = "largest-first"
+	// CoinSelectionSmallestFirst sweeps small UTXOs first, useful to consolidate dust
+	CoinSelectionSmallestFirst CoinSelectionStrategy = "smallest-first"
+	// CoinSelectionBranchAndBound looks for an input set that needs no change output.
+	// Falls back to largest-first if no such set exists.
+	CoinSelectionBranchAndBound CoinSelectionStrategy = "branch-and-bound"
+)
+
+// CoinSelectionStrategies lists all strategies in the order they are shown in the UI
+var CoinSelectionStrategies = []CoinSelectionStrategy{
+	CoinSelectionLargestFirst,
+	CoinSelectionSmallestFirst,
+	CoinSelectionBranchAndBound,
+}
+
+// changeOutputVBytes is the taproot change output the builder always reserves room for,
+// even when the excess ends up in the fee
+const changeOutputVBytes = wallet.OutputValueLen + 1 + wallet.ScriptPubKeyTaprootLen
+
+// bnbMaxTries bounds the branch-and-bound search, same order of magnitude as Bitcoin Core
+const bnbMaxTries = 100_000
+
+// Confirmations returns the number of blocks confirming utxo, 0 while unconfirmed.
+// Counts against the cached chain tip, the scan height stands in until the tip is known.
+func (m *Manager) Confirmations(utxo *wallet.OwnedUTXO) uint32 {
+	if utxo.Height == 0 {
+		return 0
+	}
+	tip := m.CachedChainTip()
+	if tip == 0 {
+		tip = uint32(m.ScanHeight())
+	}
+	if tip < utxo.Height {
+		return 1
+	}
+	return tip - utxo.Height + 1
+}
+
+// IsImmature reports whether an unspent utxo has fewer than MinConfirmations confirmations
+func (m *Manager) IsImmature(utxo *wallet.OwnedUTXO) bool {
+	return utxo.State == wallet.StateUnspent && m.Confirmations(utxo) < uint32(max(m.MinConfirmations, 1))
+}
+
+// spendableUTXOs returns the unspent UTXOs coin selection may use.
+// Immature ones and those spent by a broadcast without a known outcome are left out.
+func (m *Manager) spendableUTXOs() []*wallet.OwnedUTXO {
+	pending := m.pendingBroadcastInputs()
+	var spendable []*wallet.OwnedUTXO
+	for _, utxo := range m.GetUTXOs(wallet.StateUnspent) {
+		if m.IsImmature(utxo) || pending[utxo.SerialiseToOutpoint()] {
+			continue
+		}
+		spendable = append(spendable, utxo)
+	}
+	return spendable
+}
+
+// orderUTXOs returns the spendable UTXOs ordered by strategy.
+// Ties are broken by outpoint so the same wallet state always yields the same inputs.
+// Returns the strategy which was actually applied.
+func orderUTXOs(
+	utxos []*wallet.OwnedUTXO,
+	strategy CoinSelectionStrategy,
+	recipients []wallet.Recipient,
+	feeRate uint32,
+	minChangeAmount uint64,
+) ([]*wallet.OwnedUTXO, CoinSelectionStrategy) {
+	var spendable []*wallet.OwnedUTXO
+	for _, utxo := range utxos {
+		if utxo.State == wallet.StateUnspent {
+			spendable = append(spendable, utxo)
+		}
+	}
+
+	largestFirst := func(a, b *wallet.OwnedUTXO) int {
+		if a.Amount != b.Amount {
+			if a.Amount > b.Amount {
+				return -1
+			}
+			return 1
+		}
+		return compareOutpoints(a, b)
+	}
+
+	switch strategy {
+	case CoinSelectionSmallestFirst:
+		slices.SortFunc(spendable, func(a, b *wallet.OwnedUTXO) int {
+			if a.Amount != b.Amount {
+				if a.Amount < b.Amount {
+					return -1
+				}
+				return 1
+			}
+			return compareOutpoints(a, b)
+		})
+		return spendable, CoinSelectionSmallestFirst
+
+	case CoinSelectionBranchAndBound:
+		slices.SortFunc(spendable, largestFirst)
+		if selected := branchAndBound(spendable, recipients, feeRate, minChangeAmount); selected != nil {
+			return selected, CoinSelectionBranchAndBound
+		}
+		return spendable, CoinSelectionLargestFirst
+
+	default:
+		slices.SortFunc(spendable, largestFirst)
+		return spendable, CoinSelectionLargestFirst
+	}
+}
+
+func compareOutpoints(a, b *wallet.OwnedUTXO) int {
+	aOutpoint, bOutpoint := a.SerialiseToOutpoint(), b.SerialiseToOutpoint()
+	return bytes.Compare(aOutpoint[:], bOutpoint[:])
+}
+
+// baseTxSize returns the vbytes of a transaction paying recipients without inputs and change:
+// version, marker, locktime, in/out counts and recipient outputs. Also returns the amount paid.
+// Silent payment recipients have no script yet and are counted as taproot outputs.
+func baseTxSize(recipients []wallet.Recipient) (float64, int64) {
+	vBytes := wallet.NTxVersionLen + wallet.SegWitMarkerLenAndSegWitFlagLen + wallet.NLockTimeLen
+	vBytes += wallet.NumInputsLen + wallet.WitnessCountLen/4
+	vBytes += float64(wire.VarIntSerializeSize(uint64(len(recipients))))
+
+	var amount int64
+	for _, recipient := range recipients {
+		scriptLen := len(recipient.GetPkScript())
+		if scriptLen == 0 {
+			scriptLen = wallet.ScriptPubKeyTaprootLen
+		}
+		vBytes += wallet.OutputValueLen + float64(wire.VarIntSerializeSize(uint64(scriptLen))) + float64(scriptLen)
+		amount += int64(recipient.GetAmount())
+	}
+	return vBytes, amount
+}
+
+// branchAndBound searches for a set of UTXOs (sorted largest first) whose effective value
+// covers the recipients and the base fee without leaving enough excess to justify change.
+// Returns nil if no such set is found.
+func branchAndBound(
+	utxos []*wallet.OwnedUTXO,
+	recipients []wallet.Recipient,
+	feeRate uint32,
+	minChangeAmount uint64,
+) []*wallet.OwnedUTXO {
+	if feeRate < 1 || len(utxos) == 0 {
+		return nil
+	}
+
+	inputVBytes := wallet.TrInputOutpointLen + wallet.TrWitnessDataLen
+
+	// the builder sizes every transaction with a change output, even if it ends up dropped
+	baseVBytes, target := baseTxSize(recipients)
+	target += int64(wallet.NeededFeeAbsolutSats(baseVBytes+changeOutputVBytes, feeRate))
+
+	// creating the change now and spending it later, anything below is better given to fees.
+	// The builder drops change below minChangeAmount anyway.
+	costOfChange := int64(math.Ceil((changeOutputVBytes + inputVBytes) * float64(feeRate)))
+	costOfChange = max(costOfChange, int64(minChangeAmount))
+
+	inputFee := int64(wallet.NeededFeeAbsolutSats(inputVBytes, feeRate))
+	var candidates []*wallet.OwnedUTXO
+	var effectiveValues []int64
+	var available int64
+	for _, utxo := range utxos {
+		effective := int64(utxo.Amount) - inputFee
+		if effective <= 0 {
+			continue
+		}
+		candidates = append(candidates, utxo)
+		effectiveValues = append(effectiveValues, effective)
+		available += effective
+	}
+	if available < target {
+		return nil
+	}
+
+	var (
+		current      = make([]bool, len(candidates))
+		best         []bool
+		bestExcess   int64 = math.MaxInt64
+		currentValue int64
+		remaining    = available
+		tries        int
+	)
+
+	var search func(depth int)
+	search = func(depth int) {
+		if tries >= bnbMaxTries {
+			return
+		}
+		tries++
+
+		if currentValue > target+costOfChange || currentValue+remaining < target {
+			return
+		}
+		if currentValue >= target {
+			if excess := currentValue - target; excess < bestExcess {
+				bestExcess = excess
+				best = slices.Clone(current)
+			}
+			return
+		}
+		if depth == len(candidates) {
+			return
+		}
+
+		remaining -= effectiveValues[depth]
+
+		// inclusion branch first, finds solutions with fewer inputs early
+		current[depth] = true
+		currentValue += effectiveValues[depth]
+		search(depth + 1)
+		currentValue -= effectiveValues[depth]
+		current[depth] = false
+
+		search(depth + 1)
+
+		remaining += effectiveValues[depth]
+	}
+	search(0)
+
+	if best == nil {
+		return nil
+	}
+
+	var selected []*wallet.OwnedUTXO
+	for i, include := range best {
+		if include {
+			selected = append(selected, candidates[i])
+		}
+	}
+	return selected
+}
@@ -0,0 +1,45 @@
+package controller
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/setavenger/blindbit-lib/wallet"
+)
+
+func testUTXO(txidByte byte, vout uint32, amount uint64) *wallet.OwnedUTXO {
+	utxo := &wallet.OwnedUTXO{Vout: vout, Amount: amount, State: wallet.StateUnspent}
+	utxo.Txid[0] = txidByte
+	return utxo
+}
+
+func taprootRecipient(amount uint64) wallet.Recipient {
+	pkScript := append([]byte{0x51, 0x20}, bytes.Repeat([]byte{0x01}, 32)...)
+	return &wallet.RecipientImpl{PkScript: pkScript, Amount: amount}
+}
+
+func TestBranchAndBoundCoversChangeOutput(t *testing.T) {
+	const feeRate = 10
+	recipients := []wallet.Recipient{taprootRecipient(100_000)}
+
+	// the first UTXO covers the send only if the change output the builder reserves is ignored
+	utxos := []*wallet.OwnedUTXO{
+		testUTXO(1, 0, 101_600),
+		testUTXO(2, 0, 101_121),
+	}
+
+	selected := branchAndBound(utxos, recipients, feeRate, 1000)
+	if len(selected) != 1 || selected[0].Amount != 101_600 {
+		t.Fatalf("expected the 101600 sats UTXO to be selected, got %v", selected)
+	}
+
+	selector := wallet.NewFeeRateCoinSelector(selected, 1000, recipients, &chaincfg.MainNetParams)
+	spent, _, err := selector.CoinSelect(feeRate)
+	if err != nil {
+		t.Fatalf("builder rejected the branch-and-bound selection: %v", err)
+	}
+	if len(spent) != len(selected) {
+		t.Fatalf("builder spent %d of %d selected UTXOs", len(spent), len(selected))
+	}
+}
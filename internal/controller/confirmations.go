@@ -0,0 +1,189 @@
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/setavenger/blindbit-desktop/internal/configs"
+	"github.com/setavenger/blindbit-desktop/internal/electrum"
+	"github.com/setavenger/blindbit-lib/logging"
+	"github.com/setavenger/blindbit-lib/types"
+	"github.com/setavenger/blindbit-lib/utils"
+	"github.com/setavenger/blindbit-lib/wallet"
+)
+
+// Sent transactions without change never produce an output the scanner finds,
+// so their history entry would stay pending until a reconcile.
+// The broadcast backend is asked for their confirmation instead.
+
+// pendingPollInterval is how often pending sent transactions are looked up
+const pendingPollInterval = 2 * time.Minute
+
+// pendingSent is a sent transaction waiting for its confirmation
+type pendingSent struct {
+	txid string
+	// an output script, Electrum looks transactions up by script
+	pkScript []byte
+}
+
+// startPendingConfirmationPolling looks up pending sent transactions every pendingPollInterval until ctx is done.
+// Nothing is requested while no sent transaction is pending or while offline.
+func (m *Manager) startPendingConfirmationPolling(ctx context.Context) {
+	m.workers.Add(1)
+	go func() {
+		defer m.workers.Done()
+
+		ticker := time.NewTicker(pendingPollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if m.IsOffline() {
+					continue
+				}
+				confirmed, err := m.RefreshPendingSent(ctx)
+				if err != nil && !errors.Is(err, context.Canceled) {
+					logging.L.Warn().Err(err).Msg("failed to check pending transactions")
+				}
+				if confirmed > 0 {
+					m.persistHistory()
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// RefreshPendingSent asks the broadcast backend whether pending sent transactions confirmed
+// and sets the confirmation height of those that did. Returns how many confirmed.
+func (m *Manager) RefreshPendingSent(ctx context.Context) (int, error) {
+	pending := m.pendingSentTransactions()
+	if len(pending) == 0 {
+		return 0, nil
+	}
+
+	var (
+		confirmed int
+		errs      []error
+	)
+	for _, tx := range pending {
+		height, err := m.fetchConfirmationHeight(ctx, tx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", tx.txid, err))
+			continue
+		}
+		if height == 0 {
+			continue
+		}
+		if m.setConfirmHeight(tx.txid, height) {
+			logging.L.Info().Str("txid", tx.txid).Int("height", height).Msg("sent transaction confirmed")
+			confirmed++
+		}
+	}
+	return confirmed, errors.Join(errs...)
+}
+
+// pendingSentTransactions lists pending history entries which spend wallet coins
+func (m *Manager) pendingSentTransactions() []pendingSent {
+	m.historyMu.RLock()
+	defer m.historyMu.RUnlock()
+
+	var pending []pendingSent
+	for _, item := range m.TransactionHistory {
+		if item.ConfirmHeight != wallet.TxPending {
+			continue
+		}
+		view, err := txItemJSON(item)
+		if err != nil {
+			logging.L.Err(err).Hex("txid", item.TxID[:]).Msg("failed to read history entry")
+			continue
+		}
+		if len(view.TxIns) == 0 || len(view.TxOuts) == 0 {
+			// incoming, the scanner confirms it
+			continue
+		}
+		pending = append(pending, pendingSent{txid: view.TxID, pkScript: view.TxOuts[0].Pubkey})
+	}
+	return pending
+}
+
+// setConfirmHeight confirms the still pending history entry of txid
+func (m *Manager) setConfirmHeight(txid string, height int) bool {
+	raw, err := hex.DecodeString(txid)
+	if err != nil || len(raw) != 32 {
+		return false
+	}
+
+	m.historyMu.Lock()
+	defer m.historyMu.Unlock()
+	item := m.TransactionHistory.FindTxItemByTxID([32]byte(raw))
+	if item == nil || item.ConfirmHeight != wallet.TxPending {
+		return false
+	}
+	item.ConfirmHeight = height
+	m.TransactionHistory.Sort()
+	return true
+}
+
+// fetchConfirmationHeight returns the block height tx confirmed in, 0 while unconfirmed
+func (m *Manager) fetchConfirmationHeight(ctx context.Context, tx pendingSent) (int, error) {
+	switch m.BroadcastBackend {
+	case BroadcastBackendElectrum:
+		return m.fetchConfirmationHeightElectrum(ctx, tx)
+	default:
+		return fetchConfirmationHeightMempoolSpace(ctx, tx.txid, m.GetNetwork())
+	}
+}
+
+func (m *Manager) fetchConfirmationHeightElectrum(ctx context.Context, tx pendingSent) (int, error) {
+	if m.ElectrumAddress == "" {
+		return 0, errors.New("electrum server address is not configured")
+	}
+
+	client, err := electrum.Dial(ctx, m.ElectrumAddress)
+	if err != nil {
+		return 0, err
+	}
+	defer client.Close()
+
+	scriptHash := sha256.Sum256(tx.pkScript)
+	history, err := client.GetScriptHashHistory(ctx, hex.EncodeToString(utils.ReverseBytesCopy(scriptHash[:])))
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch script history: %w", err)
+	}
+	for _, entry := range history {
+		if entry.TxHash == tx.txid && entry.Height > 0 {
+			return int(entry.Height), nil
+		}
+	}
+	return 0, nil
+}
+
+func fetchConfirmationHeightMempoolSpace(ctx context.Context, txid string, network types.Network) (int, error) {
+	if network == types.NetworkRegtest {
+		return 0, fmt.Errorf("mempool.space does not support %s, use an electrum server", network)
+	}
+
+	body, err := httpGet(ctx, configs.GetMempoolSpaceURL(network)+"/api/tx/"+txid+"/status")
+	if err != nil {
+		return 0, err
+	}
+	var status struct {
+		Confirmed   bool `json:"confirmed"`
+		BlockHeight int  `json:"block_height"`
+	}
+	if err = json.Unmarshal(body, &status); err != nil {
+		return 0, fmt.Errorf("failed to decode transaction status: %w", err)
+	}
+	if !status.Confirmed {
+		return 0, nil
+	}
+	return status.BlockHeight, nil
+}
@@ -0,0 +1,100 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/setavenger/blindbit-lib/logging"
+	"github.com/setavenger/blindbit-lib/wallet"
+)
+
+// ErrTooFewUTXOs is returned by PrepareConsolidation when fewer than two UTXOs qualify
+var ErrTooFewUTXOs = errors.New("need at least two unspent UTXOs to consolidate")
+
+// Consolidation is a prepared self-send merging many UTXOs into one
+type Consolidation struct {
+	TxMetadata *wallet.TxMetadata
+	Recipients []wallet.Recipient
+	InputCount int
+	InputSum   uint64
+	Fee        uint64
+}
+
+// SavingsAt estimates what spending the consolidated inputs separately would cost more
+// than spending the single consolidated output, at feeRate.
+func (c *Consolidation) SavingsAt(feeRate uint32) uint64 {
+	if c.InputCount < 2 {
+		return 0
+	}
+	inputVBytes := wallet.TrInputOutpointLen + wallet.TrWitnessDataLen
+	return wallet.NeededFeeAbsolutSats(float64(c.InputCount-1)*inputVBytes, feeRate)
+}
+
+// PrepareConsolidation builds a transaction sending all unspent UTXOs below threshold
+// to the wallet's own silent payment address without change.
+// A threshold of 0 includes every unspent UTXO.
+func (m *Manager) PrepareConsolidation(
+	ctx context.Context,
+	feeRate uint32,
+	threshold uint64,
+) (
+	*Consolidation, error,
+) {
+	if feeRate < 1 {
+		return nil, wallet.ErrInvalidFeeRate
+	}
+
+	var utxos []*wallet.OwnedUTXO
+	var inputSum uint64
+	for _, utxo := range m.spendableUTXOs() {
+		if threshold > 0 && utxo.Amount >= threshold {
+			continue
+		}
+		utxos = append(utxos, utxo)
+		inputSum += utxo.Amount
+	}
+	if len(utxos) < 2 {
+		return nil, ErrTooFewUTXOs
+	}
+
+	// all inputs go into one taproot output. The builder still sizes the fee with a change output,
+	// the change is below MinChangeAmount and dropped, so the fee has to cover it too.
+	utxos, _ = orderUTXOs(utxos, CoinSelectionSmallestFirst, nil, feeRate, 0)
+	vBytes := wallet.NTxVersionLen + wallet.SegWitMarkerLenAndSegWitFlagLen + wallet.NLockTimeLen
+	vBytes += wallet.NumInputsLen + wallet.WitnessCountLen/4 + 1 // 1 byte output count
+	vBytes += wallet.OutputValueLen + 1 + wallet.ScriptPubKeyTaprootLen
+	vBytes += changeOutputVBytes
+	vBytes += float64(len(utxos)) * (wallet.TrInputOutpointLen + wallet.TrWitnessDataLen)
+	fee := wallet.NeededFeeAbsolutSats(vBytes, feeRate)
+
+	// the builder needs inputs strictly above target+fee, the extra sat goes to the miner
+	if inputSum <= fee+1+uint64(m.DustLimit) {
+		return nil, fmt.Errorf("%w: consolidating %d sats would cost %d sats in fees", ErrInsufficientFunds, inputSum, fee)
+	}
+	amount := inputSum - fee - 1
+
+	recipients := []wallet.Recipient{
+		&wallet.RecipientImpl{
+			Address: m.GetSilentPaymentAddress(),
+			Amount:  amount,
+		},
+	}
+
+	txMetadata, err := m.prepareTransaction(recipients, utxos, feeRate)
+	if err != nil {
+		logging.L.Err(err).Int("inputs", len(utxos)).Msg("failed to prepare consolidation")
+		return nil, err
+	}
+	if len(txMetadata.Tx.TxIn) != len(utxos) {
+		return nil, fmt.Errorf("consolidation only spent %d of %d UTXOs", len(txMetadata.Tx.TxIn), len(utxos))
+	}
+
+	return &Consolidation{
+		TxMetadata: txMetadata,
+		Recipients: recipients,
+		InputCount: len(utxos),
+		InputSum:   inputSum,
+		Fee:        inputSum - amount,
+	}, nil
+}
@@ -0,0 +1,29 @@
+package controller
+
+import (
+	"runtime"
+
+	"github.com/setavenger/blindbit-desktop/internal/configs"
+	"github.com/setavenger/blindbit-lib/logging"
+)
+
+// CPULimitProcs is the number of cores CPULimitPercent allows, at least one
+func (m *Manager) CPULimitProcs() int {
+	percent := m.CPULimitPercent
+	if percent < 1 || percent > 100 {
+		percent = configs.DefaultCPULimitPercent
+	}
+	return max(runtime.NumCPU()*percent/100, 1)
+}
+
+// ApplyCPULimit limits the cores the process runs on to CPULimitProcs.
+// Takes effect right away, scanning included.
+func (m *Manager) ApplyCPULimit() {
+	procs := m.CPULimitProcs()
+	if previous := runtime.GOMAXPROCS(procs); previous != procs {
+		logging.L.Info().
+			Int("procs", procs).
+			Int("cpus", runtime.NumCPU()).
+			Msg("applied CPU limit")
+	}
+}
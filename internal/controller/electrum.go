@@ -0,0 +1,45 @@
+package controller
+
+import (
+	"context"
+	"errors"
+
+	"github.com/setavenger/blindbit-desktop/internal/electrum"
+	"github.com/setavenger/blindbit-lib/logging"
+)
+
+type BroadcastBackend string
+
+const (
+	BroadcastBackendMempoolSpace BroadcastBackend = "mempool.space"
+	BroadcastBackendElectrum     BroadcastBackend = "electrum"
+)
+
+// BroadcastViaElectrum pushes the raw transaction to the configured Electrum server.
+// Returns the txid as reported by the server.
+func (m *Manager) BroadcastViaElectrum(ctx context.Context, txHex string) (string, error) {
+	if m.ElectrumAddress == "" {
+		return "", errors.New("electrum server address is not configured")
+	}
+
+	client, err := electrum.Dial(ctx, m.ElectrumAddress)
+	if err != nil {
+		return "", err
+	}
+	defer client.Close()
+
+	txid, err := client.BroadcastTransaction(ctx, txHex)
+	if err != nil {
+		logging.L.Err(err).
+			Str("server", m.ElectrumAddress).
+			Msg("electrum broadcast failed")
+		return "", err
+	}
+
+	logging.L.Info().
+		Str("server", m.ElectrumAddress).
+		Str("txid", txid).
+		Msg("broadcast transaction via electrum")
+
+	return txid, nil
+}
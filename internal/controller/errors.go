@@ -0,0 +1,23 @@
+package controller
+
+import (
+	"errors"
+
+	"github.com/setavenger/blindbit-lib/wallet"
+)
+
+// Errors callers can react to with errors.Is, returned alone or wrapped with details.
+// Errors of a single feature live next to it, e.g. ErrOffline and ErrOracleNetworkMismatch.
+var (
+	// ErrNoWallet is returned before a wallet was created or restored
+	ErrNoWallet = errors.New("wallet not initialized")
+	// ErrScannerNotReady is returned while the scanner is not constructed yet
+	ErrScannerNotReady = errors.New("scanner not initialized")
+	// ErrOracleNotReady is returned while there is no oracle client
+	ErrOracleNotReady = errors.New("oracle client not initialized")
+	// ErrScannerBusy is returned when the watcher did not hand over the scanner in time
+	ErrScannerBusy = errors.New("scanner is busy following the chain tip")
+	// ErrInsufficientFunds is the error of blindbit-lib's transaction builder,
+	// InsufficientFundsError matches it as well
+	ErrInsufficientFunds = wallet.ErrInsufficientFunds
+)
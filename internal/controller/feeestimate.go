@@ -0,0 +1,163 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/setavenger/blindbit-lib/wallet"
+)
+
+// ErrFeeRateTooLow is returned by CheckFeeRate for fee rates below MinFeeRate
+var ErrFeeRateTooLow = errors.New("fee rate is below the minimum")
+
+// InsufficientFundsError explains a send the wallet cannot cover.
+// It matches wallet.ErrInsufficientFunds with errors.Is.
+type InsufficientFundsError struct {
+	// Spendable is the sum of the unspent UTXOs with enough confirmations
+	Spendable uint64
+	// Immature is the sum of the unspent UTXOs still below MinConfirmations
+	Immature uint64
+	// Requested is the sum of the recipient amounts
+	Requested uint64
+	// EstimatedFee is the fee for spending every UTXO worth more than its input fee, without change
+	EstimatedFee uint64
+	// MaxSendable is the most the last recipient could receive with the other amounts unchanged,
+	// 0 if not even that fits
+	MaxSendable uint64
+}
+
+func (e *InsufficientFundsError) Error() string {
+	msg := fmt.Sprintf(
+		"insufficient funds: %d sats requested plus about %d sats fee, %d sats spendable",
+		e.Requested, e.EstimatedFee, e.Spendable,
+	)
+	if e.Immature > 0 {
+		msg += fmt.Sprintf(", %d sats waiting for confirmations", e.Immature)
+	}
+	return msg
+}
+
+func (e *InsufficientFundsError) Unwrap() error {
+	return wallet.ErrInsufficientFunds
+}
+
+// CheckFeeRate rejects fee rates below MinFeeRate, which nodes would not relay.
+// MinFeeRate is never taken as less than 1 sat/vB.
+func (m *Manager) CheckFeeRate(feeRate uint64) error {
+	minFeeRate := uint64(max(m.MinFeeRate, 1))
+	if feeRate < minFeeRate {
+		return fmt.Errorf("%w: %d sat/vB, at least %d sat/vB", ErrFeeRateTooLow, feeRate, minFeeRate)
+	}
+	return nil
+}
+
+// insufficientFunds estimates what the wallet can send to recipients at feeRate.
+// The estimate spends all UTXOs which are worth more than their input fee and creates no change,
+// which is the largest amount the transaction builder can produce.
+// Like EstimateFee the fee includes the change output the builder always sizes the transaction with.
+func (m *Manager) insufficientFunds(recipients []wallet.Recipient, feeRate uint32) *InsufficientFundsError {
+	vBytes, requested := baseTxSize(recipients)
+	vBytes += changeOutputVBytes
+
+	inputVBytes := wallet.TrInputOutpointLen + wallet.TrWitnessDataLen
+	inputFee := wallet.NeededFeeAbsolutSats(inputVBytes, feeRate)
+
+	pending := m.pendingBroadcastInputs()
+	var spendable, usable, immature uint64
+	for _, utxo := range m.GetUTXOs(wallet.StateUnspent) {
+		if pending[utxo.SerialiseToOutpoint()] {
+			continue
+		}
+		if m.IsImmature(utxo) {
+			immature += utxo.Amount
+			continue
+		}
+		spendable += utxo.Amount
+		if utxo.Amount <= inputFee {
+			continue
+		}
+		usable += utxo.Amount
+		vBytes += inputVBytes
+	}
+	fee := wallet.NeededFeeAbsolutSats(vBytes, feeRate)
+
+	result := &InsufficientFundsError{
+		Spendable:    spendable,
+		Immature:     immature,
+		Requested:    uint64(requested),
+		EstimatedFee: fee,
+	}
+
+	// the builder needs inputs strictly above amounts plus fee
+	var others uint64
+	if len(recipients) > 0 {
+		others = uint64(requested) - recipients[len(recipients)-1].GetAmount()
+	}
+	if usable > others+fee+1 {
+		result.MaxSendable = usable - others - fee - 1
+	}
+	return result
+}
+
+// FeeEstimate is the fee PrepareTransaction is expected to pay
+type FeeEstimate struct {
+	Fee    uint64
+	Inputs int
+	// Change is 0 when the excess is below MinChangeAmount and goes to the fee
+	Change uint64
+}
+
+// EstimateFee predicts the inputs, fee and change of a send without building or signing it.
+// It follows the builder's selection on the UTXOs ordered by m.CoinSelectionStrategy:
+// the first prefix leaving at least MinChangeAmount as change, otherwise the first prefix covering
+// amounts and fee, with a too small excess added to the fee.
+// Like PrepareTransaction it falls back to largest-first if the strategy's selection falls short.
+// Returns an InsufficientFundsError if no prefix covers the send.
+func (m *Manager) EstimateFee(recipients []wallet.Recipient, feeRate uint32) (*FeeEstimate, error) {
+	if feeRate < 1 {
+		return nil, wallet.ErrInvalidFeeRate
+	}
+
+	utxos, strategy := orderUTXOs(m.spendableUTXOs(), m.CoinSelectionStrategy, recipients, feeRate, m.MinChangeAmount)
+	if estimate := m.estimateFee(utxos, recipients, feeRate); estimate != nil {
+		return estimate, nil
+	}
+	if strategy != CoinSelectionLargestFirst {
+		utxos, _ = orderUTXOs(m.spendableUTXOs(), CoinSelectionLargestFirst, recipients, feeRate, m.MinChangeAmount)
+		if estimate := m.estimateFee(utxos, recipients, feeRate); estimate != nil {
+			return estimate, nil
+		}
+	}
+
+	return nil, m.insufficientFunds(recipients, feeRate)
+}
+
+// estimateFee follows the builder's selection on utxos in the given order, nil if they do not cover the send
+func (m *Manager) estimateFee(utxos []*wallet.OwnedUTXO, recipients []wallet.Recipient, feeRate uint32) *FeeEstimate {
+	baseVBytes, amount := baseTxSize(recipients)
+	target := uint64(amount)
+	// the builder always sizes the transaction with a change output
+	baseVBytes += changeOutputVBytes
+	inputVBytes := wallet.TrInputOutpointLen + wallet.TrWitnessDataLen
+
+	for _, requireChange := range []bool{true, false} {
+		vBytes := baseVBytes
+		var sum uint64
+		for i, utxo := range utxos {
+			sum += utxo.Amount
+			vBytes += inputVBytes
+			fee := wallet.NeededFeeAbsolutSats(vBytes, feeRate)
+			if sum <= target+fee {
+				continue
+			}
+			change := sum - target - fee
+			if change >= m.MinChangeAmount {
+				return &FeeEstimate{Fee: fee, Inputs: i + 1, Change: change}
+			}
+			if !requireChange {
+				return &FeeEstimate{Fee: sum - target, Inputs: i + 1}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package controller
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/setavenger/blindbit-lib/wallet"
+)
+
+// newTestManager returns a manager whose wallet holds utxos, all confirmed at height 1
+func newTestManager(utxos ...*wallet.OwnedUTXO) *Manager {
+	m := NewManager()
+	m.Wallet = &wallet.Wallet{LastScanHeight: 10}
+	for _, utxo := range utxos {
+		if utxo.Height == 0 {
+			utxo.Height = 1
+		}
+		m.Wallet.UTXOs = append(m.Wallet.UTXOs, utxo)
+	}
+	return m
+}
+
+func TestMaxSendableIsAcceptedByBuilder(t *testing.T) {
+	const feeRate = 7
+	utxos := []*wallet.OwnedUTXO{
+		testUTXO(1, 0, 40_000),
+		testUTXO(2, 1, 25_000),
+	}
+	m := newTestManager(utxos...)
+
+	result := m.insufficientFunds([]wallet.Recipient{taprootRecipient(100_000)}, feeRate)
+	if result.MaxSendable == 0 {
+		t.Fatal("expected a max sendable amount")
+	}
+
+	coinSelect := func(amount uint64) error {
+		selector := wallet.NewFeeRateCoinSelector(
+			m.GetUTXOs(wallet.StateUnspent), m.MinChangeAmount,
+			[]wallet.Recipient{taprootRecipient(amount)}, &chaincfg.MainNetParams,
+		)
+		_, _, err := selector.CoinSelect(feeRate)
+		return err
+	}
+	if err := coinSelect(result.MaxSendable); err != nil {
+		t.Fatalf("builder rejected max sendable %d: %v", result.MaxSendable, err)
+	}
+	if err := coinSelect(result.MaxSendable + 1); !errors.Is(err, wallet.ErrInsufficientFunds) {
+		t.Fatalf("expected max sendable %d to be the maximum, one sat more gave %v", result.MaxSendable, err)
+	}
+}
@@ -3,6 +3,8 @@ package controller
 import (
 	"bytes"
 	"encoding/hex"
+	"errors"
+	"fmt"
 	"sort"
 
 	"github.com/btcsuite/btcd/btcutil"
@@ -20,18 +22,57 @@ func (m *Manager) GetNetwork() types.Network {
 	return m.Wallet.Network
 }
 
+// ErrNetworkMismatch is returned by CheckNetwork for a wallet created on another network
+var ErrNetworkMismatch = errors.New("wallet was created for another network")
+
+// CheckNetwork refuses to open the wallet for a network other than the stored one.
+// The keys and addresses are derived per network, so the stored network is never rewritten.
+func (m *Manager) CheckNetwork(network types.Network) error {
+	if stored := m.GetNetwork(); stored != network {
+		return fmt.Errorf("%w: wallet is on %s, requested %s", ErrNetworkMismatch, stored, network)
+	}
+	return nil
+}
+
+// ExplorerURL returns the custom block explorer of the wallet's network, empty for the default
+func (m *Manager) ExplorerURL() string {
+	return m.ExplorerURLs[m.GetNetwork()]
+}
+
+// SetExplorerURL sets the block explorer of the wallet's network, empty resets to the default
+func (m *Manager) SetExplorerURL(explorerURL string) {
+	network := m.GetNetwork()
+	if explorerURL == "" {
+		delete(m.ExplorerURLs, network)
+		return
+	}
+	if m.ExplorerURLs == nil {
+		m.ExplorerURLs = make(map[types.Network]string)
+	}
+	m.ExplorerURLs[network] = explorerURL
+}
+
 // GetSilentPaymentAddress returns the main Silent Payment address
-// Note: This is NOT the change address (label 0), but the main receiving address
+// Note: This is NOT the change address (label 0), but the main receiving address.
+// The address is computed once per network and cached.
 func (m *Manager) GetSilentPaymentAddress() string {
-	return m.Wallet.Address()
+	m.addressMu.Lock()
+	defer m.addressMu.Unlock()
+
+	network := m.GetNetwork()
+	if m.address == "" || m.addressNetwork != network {
+		m.address = m.Wallet.Address()
+		m.addressNetwork = network
+	}
+	return m.address
 }
 
 // GetUTXOsSorted returns UTXOs sorted by block height (newest first)
 func (m *Manager) GetUTXOsSorted() []*wallet.OwnedUTXO {
-	utxos := m.Wallet.GetUTXOs()
+	utxos := m.GetUTXOs()
 
 	// Sort by height in descending order (newest first)
-	sort.Slice(utxos, func(i, j int) bool {
+	sort.SliceStable(utxos, func(i, j int) bool {
 		return utxos[i].Height > utxos[j].Height
 	})
 
@@ -40,10 +81,10 @@ func (m *Manager) GetUTXOsSorted() []*wallet.OwnedUTXO {
 
 // GetUnspentUTXOsSorted returns only unspent UTXOs sorted by block height (newest first)
 func (m *Manager) GetUnspentUTXOsSorted() []*wallet.OwnedUTXO {
-	utxos := m.Wallet.GetUTXOs(wallet.StateUnspent)
+	utxos := m.GetUTXOs(wallet.StateUnspent)
 
 	// Sort by height in descending order (newest first)
-	sort.Slice(utxos, func(i, j int) bool {
+	sort.SliceStable(utxos, func(i, j int) bool {
 		return utxos[i].Height > utxos[j].Height
 	})
 
@@ -59,6 +100,17 @@ func GetTxID(tx *wire.MsgTx) [32]byte {
 	return txid
 }
 
+// OutpointKey returns the key SerialiseToOutpoint gives the UTXO spent by a transaction input.
+// OwnedUTXO.Txid is in display order, the reverse of wire's hash bytes.
+// Compare inputs to wallet UTXOs only through this key.
+func OutpointKey(outpoint wire.OutPoint) [36]byte {
+	prevOut := wallet.OwnedUTXO{
+		Txid: [32]byte(utils.ReverseBytesCopy(outpoint.Hash[:])),
+		Vout: outpoint.Index,
+	}
+	return prevOut.SerialiseToOutpoint()
+}
+
 // SerializeTx converts transaction to hex string for broadcasting
 func SerializeTx(tx *wire.MsgTx) (string, error) {
 	var buf bytes.Buffer
@@ -70,6 +122,11 @@ func SerializeTx(tx *wire.MsgTx) (string, error) {
 	return hex.EncodeToString(buf.Bytes()), nil
 }
 
+// DecodeTx parses a transaction from hex, the counterpart of SerializeTx
+func DecodeTx(txHex string) (*wire.MsgTx, error) {
+	return decodeTxHex(txHex)
+}
+
 // CalculateTxFee calculates fee from inputs and outputs
 func CalculateTxFee(inputSum, outputSum uint64) uint64 {
 	if inputSum > outputSum {
@@ -83,6 +140,25 @@ func CalculateTxVBytes(tx *wire.MsgTx) uint64 {
 	return uint64(mempool.GetTxVirtualSize(btcutil.NewTx(tx)))
 }
 
+// changeToleranceVBytes absorbs the difference between the size estimated
+// during coin selection and the size of the signed transaction
+const changeToleranceVBytes = 2
+
+// DroppedChange returns how much of fee goes beyond feeRate because the remainder was below
+// the minimum change amount and no change output was created. 0 if tx has a change output.
+// The builder sizes the fee with a change output either way, that allowance is not part of the remainder.
+func DroppedChange(tx *wire.MsgTx, recipientCount int, fee uint64, feeRate uint32) uint64 {
+	if len(tx.TxOut) > recipientCount {
+		return 0
+	}
+	expected := uint64(feeRate) * (CalculateTxVBytes(tx) + uint64(changeOutputVBytes))
+	tolerance := uint64(feeRate) * changeToleranceVBytes
+	if fee <= expected+tolerance {
+		return 0
+	}
+	return fee - expected
+}
+
 // CalculateFeeRate calculates fee rate in sat/vB
 func CalculateFeeRate(fee, vbytes uint64) float64 {
 	if vbytes == 0 {
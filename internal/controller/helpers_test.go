@@ -0,0 +1,141 @@
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"testing"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/setavenger/blindbit-lib/types"
+	"github.com/setavenger/blindbit-lib/wallet"
+	"github.com/setavenger/go-bip352"
+)
+
+const testMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+// newTestWallet returns a manager with a signet wallet holding one confirmed UTXO per amount.
+// The UTXOs pay to B_spend + t·G like a scanned silent payment, so the wallet can spend them.
+func newTestWallet(t *testing.T, amounts ...uint64) *Manager {
+	t.Helper()
+	w, err := wallet.NewFromMnemonic(testMnemonic, types.NetworkSignet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.LastScanHeight = 10
+
+	m := NewManager()
+	m.Wallet = w
+	for i, amount := range amounts {
+		tweak := sha256.Sum256([]byte{byte(i)})
+		outputKey := tweak
+		if err = bip352.AddPrivateKeys(&outputKey, w.SecretKeySpend.ToArrayPtr()); err != nil {
+			t.Fatal(err)
+		}
+		utxo := testUTXO(byte(i+1), 0, amount)
+		utxo.PrivKeyTweak = tweak
+		utxo.PubKey = [32]byte(bip352.PubKeyFromSecKey(&outputKey)[1:])
+		utxo.Height = 1
+		w.UTXOs = append(w.UTXOs, utxo)
+	}
+	return m
+}
+
+// sendTo prepares a transaction from the test wallet like the send tab does
+func sendTo(t *testing.T, m *Manager, recipients ...wallet.Recipient) *wallet.TxMetadata {
+	t.Helper()
+	txMetadata, _, err := m.PrepareTransaction(context.Background(), recipients, 2)
+	if err != nil {
+		t.Fatalf("failed to prepare transaction: %v", err)
+	}
+	return txMetadata
+}
+
+// txFee returns inputSum minus all outputs of the transaction
+func txFee(txMetadata *wallet.TxMetadata, inputSum int) int {
+	fee := inputSum
+	for _, txOut := range txMetadata.Tx.TxOut {
+		fee -= int(txOut.Value)
+	}
+	return fee
+}
+
+// testSpend returns a UTXO created by a funding transaction and the input spending it
+func testSpend(t *testing.T) (*wallet.OwnedUTXO, wire.OutPoint) {
+	t.Helper()
+	funding := wire.NewMsgTx(wire.TxVersion)
+	funding.AddTxOut(wire.NewTxOut(1_000, []byte{0x51}))
+	funding.AddTxOut(wire.NewTxOut(2_000, []byte{0x51}))
+
+	utxo := &wallet.OwnedUTXO{Txid: GetTxID(funding), Vout: 1, Amount: 2_000, State: wallet.StateUnspent}
+	return utxo, wire.OutPoint{Hash: funding.TxHash(), Index: 1}
+}
+
+func TestOutpointKeyMatchesSerialiseToOutpoint(t *testing.T) {
+	utxo, outpoint := testSpend(t)
+
+	if OutpointKey(outpoint) != utxo.SerialiseToOutpoint() {
+		t.Fatalf("OutpointKey(%s) does not match the UTXO's SerialiseToOutpoint", outpoint)
+	}
+
+	// display: the UTXO's txid reads the same as the input's outpoint
+	if got, want := fmt.Sprintf("%x:%d", utxo.Txid, utxo.Vout), outpoint.String(); got != want {
+		t.Fatalf("UTXO displays as %s, the input as %s", got, want)
+	}
+}
+
+func TestMarkUTXOsAsSpentMatchesOutpoint(t *testing.T) {
+	utxo, outpoint := testSpend(t)
+
+	// same bytes in wire order, a byte order mixup would mark this one
+	reversed := &wallet.OwnedUTXO{Vout: 1, Amount: 3_000, State: wallet.StateUnspent}
+	copy(reversed.Txid[:], outpoint.Hash[:])
+
+	m := newTestManager(utxo, reversed)
+
+	tx := wire.NewMsgTx(wire.TxVersion)
+	tx.AddTxIn(wire.NewTxIn(&outpoint, nil, nil))
+	m.markUTXOsAsSpent(tx)
+
+	for _, u := range m.GetUTXOs() {
+		want := wallet.StateUnspent
+		if u.Amount == utxo.Amount {
+			want = wallet.StateUnconfirmedSpent
+		}
+		if u.State != want {
+			t.Errorf("UTXO %x:%d has state %v, want %v", u.Txid, u.Vout, u.State, want)
+		}
+	}
+}
+
+func TestDroppedChange(t *testing.T) {
+	const feeRate = 2
+	m := newTestWallet(t, 100_000)
+
+	// the fee the builder charges, sized with a change output
+	withChange := sendTo(t, m, taprootRecipient(50_000))
+	fee := uint64(txFee(withChange, 100_000))
+	if DroppedChange(withChange.Tx, 1, fee, feeRate) != 0 {
+		t.Fatal("reported dropped change for a transaction with change")
+	}
+
+	// the builder needs inputs above amount plus fee, 1 sat is the smallest remainder
+	for _, remainder := range []uint64{1, 100, 500} {
+		txMetadata := sendTo(t, m, taprootRecipient(100_000-fee-remainder))
+		if len(txMetadata.Tx.TxOut) != 1 {
+			t.Fatalf("remainder %d: expected no change output, got %d outputs", remainder, len(txMetadata.Tx.TxOut))
+		}
+
+		dropped := DroppedChange(txMetadata.Tx, 1, fee+remainder, feeRate)
+		tolerance := uint64(feeRate * changeToleranceVBytes)
+		if remainder <= tolerance {
+			if dropped != 0 {
+				t.Fatalf("reported %d sats dropped change for a remainder of %d", dropped, remainder)
+			}
+			continue
+		}
+		if dropped+tolerance < remainder || dropped > remainder+tolerance {
+			t.Fatalf("reported %d sats dropped change, the remainder was %d", dropped, remainder)
+		}
+	}
+}
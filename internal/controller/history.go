@@ -0,0 +1,272 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/setavenger/blindbit-lib/logging"
+	"github.com/setavenger/blindbit-lib/wallet"
+)
+
+// HistoryReconcileReport lists what ReconcileHistory changed
+type HistoryReconcileReport struct {
+	// Added counts UTXOs which had no history entry yet
+	Added int
+	// HeightsFixed counts entries whose confirmation height did not match their UTXOs
+	HeightsFixed int
+	// Merged counts duplicate entries which were folded into one
+	Merged int
+}
+
+func (r HistoryReconcileReport) String() string {
+	if r.Added == 0 && r.HeightsFixed == 0 && r.Merged == 0 {
+		return "Transaction history is consistent with the UTXO set, nothing changed."
+	}
+	return fmt.Sprintf(
+		"Added %d missing transaction(s), fixed %d confirmation height(s) and merged %d duplicate(s).",
+		r.Added, r.HeightsFixed, r.Merged,
+	)
+}
+
+// ReconcileHistory checks the transaction history against the wallet's UTXOs.
+// UTXOs without an entry are added as incoming transactions and
+// entries take the confirmation height of their confirmed UTXOs.
+// Classification of stored outputs (self or external) is kept as is,
+// blindbit-lib does not expose the outputs of an entry.
+func (m *Manager) ReconcileHistory() HistoryReconcileReport {
+	m.historyMu.Lock()
+	defer m.historyMu.Unlock()
+
+	var report HistoryReconcileReport
+
+	merged, err := m.deduplicateHistory()
+	if err != nil {
+		logging.L.Err(err).Msg("failed to deduplicate transaction history")
+	}
+	report.Merged = merged
+
+	for _, utxo := range m.GetUTXOs() {
+		item := m.TransactionHistory.FindTxItemByTxID(utxo.Txid)
+		if item == nil {
+			if err := m.addOutUtxoToHistoryLocked(utxo); err != nil {
+				logging.L.Err(err).
+					Hex("txid", utxo.Txid[:]).
+					Uint32("vout", utxo.Vout).
+					Msg("failed to add missing history entry")
+				continue
+			}
+			report.Added++
+			continue
+		}
+
+		// unconfirmed UTXOs carry no height
+		if utxo.Height > 0 && item.ConfirmHeight != int(utxo.Height) {
+			logging.L.Debug().
+				Hex("txid", utxo.Txid[:]).
+				Int("old_height", item.ConfirmHeight).
+				Uint32("new_height", utxo.Height).
+				Msg("fixing confirmation height")
+			item.ConfirmHeight = int(utxo.Height)
+			report.HeightsFixed++
+		}
+	}
+
+	if report.HeightsFixed > 0 {
+		m.TransactionHistory.Sort()
+	}
+
+	logging.L.Info().
+		Int("added", report.Added).
+		Int("heights_fixed", report.HeightsFixed).
+		Int("merged", report.Merged).
+		Msg("reconciled transaction history")
+
+	return report
+}
+
+// GetTransactionHistory returns a copy of the transaction history.
+// The GUI can read it while the channel handlers keep adding to the original.
+func (m *Manager) GetTransactionHistory() wallet.TxHistory {
+	m.historyMu.RLock()
+	data, err := json.Marshal(m.TransactionHistory)
+	m.historyMu.RUnlock()
+	if err != nil {
+		logging.L.Err(err).Msg("failed to copy transaction history")
+		return nil
+	}
+
+	var history wallet.TxHistory
+	if err = json.Unmarshal(data, &history); err != nil {
+		logging.L.Err(err).Msg("failed to copy transaction history")
+		return nil
+	}
+	return history
+}
+
+// HasTransaction reports whether txid is part of the transaction history
+func (m *Manager) HasTransaction(txid [32]byte) bool {
+	m.historyMu.RLock()
+	defer m.historyMu.RUnlock()
+	return m.TransactionHistory.FindTxItemByTxID(txid) != nil
+}
+
+// persistHistory saves the wallet right after the history changed,
+// so a crash before the next periodic save does not lose the entry
+func (m *Manager) persistHistory() {
+	m.lifecycleMu.Lock()
+	saveFunc := m.saveFunc
+	m.lifecycleMu.Unlock()
+
+	if saveFunc == nil {
+		logging.L.Debug().Msg("no save function yet, history is saved with the next save")
+		return
+	}
+	if err := saveFunc(); err != nil {
+		logging.L.Err(err).Msg("failed to save wallet after history change")
+	}
+}
+
+// addOutUtxoToHistory adds a received UTXO to the history.
+// Outputs of one transaction end up in a single entry and
+// adding the same txid:vout twice is a no-op.
+func (m *Manager) addOutUtxoToHistory(utxo *wallet.OwnedUTXO) error {
+	m.historyMu.Lock()
+	defer m.historyMu.Unlock()
+	return m.addOutUtxoToHistoryLocked(utxo)
+}
+
+func (m *Manager) addOutUtxoToHistoryLocked(utxo *wallet.OwnedUTXO) error {
+	if item := m.TransactionHistory.FindTxItemByTxID(utxo.Txid); item != nil {
+		has, err := txItemHasOutput(item, utxo.Vout)
+		if err != nil {
+			return err
+		}
+		if has {
+			// AddOutUtxo would leave the entry's read lock held for known outputs
+			if utxo.Height > 0 {
+				item.ConfirmHeight = int(utxo.Height)
+			}
+			return nil
+		}
+	}
+	return m.TransactionHistory.AddOutUtxo(utxo)
+}
+
+// DeduplicateHistory merges history entries with the same txid into one.
+// Inputs and outputs are combined per outpoint and vout,
+// a confirmed height wins over pending. Returns the number of removed entries.
+func (m *Manager) DeduplicateHistory() (int, error) {
+	m.historyMu.Lock()
+	defer m.historyMu.Unlock()
+	return m.deduplicateHistory()
+}
+
+func (m *Manager) deduplicateHistory() (int, error) {
+	var deduped wallet.TxHistory
+	index := make(map[[32]byte]int, len(m.TransactionHistory))
+
+	for _, item := range m.TransactionHistory {
+		i, seen := index[item.TxID]
+		if !seen {
+			index[item.TxID] = len(deduped)
+			deduped = append(deduped, item)
+			continue
+		}
+
+		merged, err := mergeTxItems(deduped[i], item)
+		if err != nil {
+			return 0, fmt.Errorf("failed to merge history entries for %x: %w", item.TxID, err)
+		}
+		deduped[i] = merged
+	}
+
+	removed := len(m.TransactionHistory) - len(deduped)
+	if removed == 0 {
+		return 0, nil
+	}
+
+	logging.L.Info().Int("removed", removed).Msg("merged duplicate history entries")
+	deduped.Sort()
+	m.TransactionHistory = deduped
+	return removed, nil
+}
+
+// txItemJSON exposes inputs and outputs of a history entry,
+// blindbit-lib keeps them unexported apart from the JSON form
+func txItemJSON(item *wallet.TxItem) (wallet.TxItemJSON, error) {
+	var view wallet.TxItemJSON
+	data, err := json.Marshal(item)
+	if err != nil {
+		return view, err
+	}
+	err = json.Unmarshal(data, &view)
+	return view, err
+}
+
+func txItemHasOutput(item *wallet.TxItem, vout uint32) (bool, error) {
+	view, err := txItemJSON(item)
+	if err != nil {
+		return false, err
+	}
+	for _, out := range view.TxOuts {
+		if out.Vout == vout {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// mergeTxItems combines two entries of the same transaction
+func mergeTxItems(a, b *wallet.TxItem) (*wallet.TxItem, error) {
+	viewA, err := txItemJSON(a)
+	if err != nil {
+		return nil, err
+	}
+	viewB, err := txItemJSON(b)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := viewA
+	if merged.ConfirmHeight <= 0 && viewB.ConfirmHeight > 0 {
+		merged.ConfirmHeight = viewB.ConfirmHeight
+	}
+
+	for _, in := range viewB.TxIns {
+		known := false
+		for _, existing := range merged.TxIns {
+			if existing.Outpoint == in.Outpoint {
+				known = true
+				break
+			}
+		}
+		if !known {
+			merged.TxIns = append(merged.TxIns, in)
+		}
+	}
+
+	for _, out := range viewB.TxOuts {
+		known := false
+		for _, existing := range merged.TxOuts {
+			if existing.Vout == out.Vout {
+				// an output seen as ours in either entry is ours
+				existing.Self = existing.Self || out.Self
+				known = true
+				break
+			}
+		}
+		if !known {
+			merged.TxOuts = append(merged.TxOuts, out)
+		}
+	}
+
+	data, err := json.Marshal(merged)
+	if err != nil {
+		return nil, err
+	}
+	item := new(wallet.TxItem)
+	if err = json.Unmarshal(data, item); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
@@ -0,0 +1,36 @@
+package controller
+
+import (
+	"sync"
+	"testing"
+)
+
+// run with -race, the channel handlers add to the history while the transactions tab reads it
+func TestHistoryConcurrentAddAndRead(t *testing.T) {
+	m := newTestManager()
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			utxo := testUTXO(byte(i), 0, 1_000)
+			utxo.Height = uint32(i + 1)
+			if err := m.addOutUtxoToHistory(utxo); err != nil {
+				t.Errorf("add %d: %v", i, err)
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			m.GetTransactionHistory()
+			m.HasTransaction(testUTXO(byte(i), 0, 0).Txid)
+		}
+	}()
+	wg.Wait()
+
+	if got := len(m.GetTransactionHistory()); got != 100 {
+		t.Fatalf("history has %d entries, want 100", got)
+	}
+}
@@ -0,0 +1,136 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/setavenger/go-bip352"
+)
+
+// ReceiveLabel is a labeled silent payment address handed out to one counterparty.
+// Label m = 0 is reserved for change and never part of the registry.
+type ReceiveLabel struct {
+	M         uint32    `json:"m"`
+	Name      string    `json:"name"`
+	Address   string    `json:"address"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// LabelUsage tells whether payments arrived on a labeled address
+type LabelUsage struct {
+	Label ReceiveLabel
+	// Payments counts the UTXOs found for the label, spent ones included
+	Payments int
+	Received uint64
+}
+
+// Used reports whether anything was received on the label
+func (u LabelUsage) Used() bool {
+	return u.Payments > 0
+}
+
+// NewReceiveLabel creates the next labeled address for name and adds it to the registry.
+// The scanner only looks for the label once it was rebuilt, see NeedsReconnect.
+func (m *Manager) NewReceiveLabel(name string) (*ReceiveLabel, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errors.New("label name is required")
+	}
+
+	m.walletMu.Lock()
+	defer m.walletMu.Unlock()
+
+	// labels up to LabelCount are scanned anyway, a named label gets its own m
+	next := uint32(m.LabelCount) + 1
+	for _, label := range m.ReceiveLabels {
+		if label.Name == name {
+			return nil, fmt.Errorf("label %q already exists", name)
+		}
+		if label.M >= next {
+			next = label.M + 1
+		}
+	}
+
+	// ChangeAddress reads the first label slot, which stays nil if a higher m is computed first
+	if err := m.Wallet.ComputeLabelForM(0); err != nil {
+		return nil, fmt.Errorf("failed to compute change label: %w", err)
+	}
+	if err := m.Wallet.ComputeLabelForM(next); err != nil {
+		return nil, fmt.Errorf("failed to compute label: %w", err)
+	}
+
+	label := &ReceiveLabel{
+		M:         next,
+		Name:      name,
+		Address:   m.Wallet.GetLabel(next).Address,
+		CreatedAt: time.Now(),
+	}
+	m.ReceiveLabels = append(m.ReceiveLabels, label)
+	return label, nil
+}
+
+// GetLabelUsage lists the registered labels in creation order with the payments found for each
+func (m *Manager) GetLabelUsage() []LabelUsage {
+	m.walletMu.RLock()
+	usage := make([]LabelUsage, len(m.ReceiveLabels))
+	index := make(map[uint32]int, len(m.ReceiveLabels))
+	for i, label := range m.ReceiveLabels {
+		usage[i].Label = *label
+		index[label.M] = i
+	}
+	m.walletMu.RUnlock()
+
+	for _, utxo := range m.GetUTXOs() {
+		if utxo.Label == nil {
+			continue
+		}
+		i, ok := index[utxo.Label.M]
+		if !ok {
+			continue
+		}
+		usage[i].Payments++
+		usage[i].Received += utxo.Amount
+	}
+	return usage
+}
+
+// LabelName returns the name of the registered label m, empty for change and unnamed labels
+func (m *Manager) LabelName(labelM uint32) string {
+	m.walletMu.RLock()
+	defer m.walletMu.RUnlock()
+	for _, label := range m.ReceiveLabels {
+		if label.M == labelM {
+			return label.Name
+		}
+	}
+	return ""
+}
+
+// receiveLabelCount returns the number of registered labels
+func (m *Manager) receiveLabelCount() int {
+	m.walletMu.RLock()
+	defer m.walletMu.RUnlock()
+	return len(m.ReceiveLabels)
+}
+
+// scanLabels returns the change label, the labels 1 to LabelCount and all registered labels,
+// each m once
+func (m *Manager) scanLabels() []*bip352.Label {
+	// GetLabel computes and stores missing labels in the wallet
+	m.walletMu.Lock()
+	defer m.walletMu.Unlock()
+
+	labels := []*bip352.Label{m.Wallet.GetLabel(0)}
+	for i := 1; i <= m.LabelCount; i++ {
+		labels = append(labels, m.Wallet.GetLabel(uint32(i)))
+	}
+	for _, label := range m.ReceiveLabels {
+		if label.M <= uint32(m.LabelCount) {
+			continue
+		}
+		labels = append(labels, m.Wallet.GetLabel(label.M))
+	}
+	return labels
+}
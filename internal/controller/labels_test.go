@@ -0,0 +1,83 @@
+package controller
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/setavenger/blindbit-lib/wallet"
+)
+
+func TestLabeledPaymentDetected(t *testing.T) {
+	m := newTestWallet(t, 100_000)
+	label, err := m.NewReceiveLabel("alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if label.M <= uint32(m.LabelCount) {
+		t.Fatalf("label m = %d, want above the scanned range of %d", label.M, m.LabelCount)
+	}
+
+	txMetadata := sendTo(t, m, &wallet.RecipientImpl{Address: label.Address, Amount: 25_000})
+	found, err := m.scanPendingTransaction(txMetadata.Tx, m.ownPrevOutScripts(txMetadata.Tx))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var payment *wallet.OwnedUTXO
+	for _, utxo := range found {
+		if utxo.Amount == 25_000 {
+			payment = utxo
+		}
+	}
+	if payment == nil {
+		t.Fatalf("payment to the labeled address was not detected, found %d outputs", len(found))
+	}
+	if payment.Label == nil || payment.Label.M != label.M {
+		t.Fatalf("payment carries label %v, want m = %d", payment.Label, label.M)
+	}
+	if !bytes.Equal(txMetadata.Tx.TxOut[payment.Vout].PkScript[2:], payment.PubKey[:]) {
+		t.Fatal("detected output key does not match the transaction output")
+	}
+
+	m.addPendingUTXOs(found)
+	usage := m.GetLabelUsage()
+	if len(usage) != 1 || usage[0].Payments != 1 || usage[0].Received != 25_000 {
+		t.Fatalf("label usage = %+v, want one payment of 25000 sats", usage)
+	}
+}
+
+func TestOwnedOutputsLabeledChange(t *testing.T) {
+	m := newTestWallet(t, 100_000)
+	txMetadata := sendTo(t, m, taprootRecipient(30_000))
+
+	change := txMetadata.ChangeRecipient
+	if change == nil {
+		t.Fatal("expected a change output")
+	}
+	// change pays to the m = 0 label, never to the wallet's address
+	if change.Address == m.Wallet.Address() {
+		t.Fatal("change was sent to the unlabeled address")
+	}
+
+	owned, err := m.OwnedOutputs(txMetadata.Tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(owned) != 1 {
+		t.Fatalf("expected only the change to be own, got %v", owned)
+	}
+	for vout, txOut := range txMetadata.Tx.TxOut {
+		isChange := uint64(txOut.Value) == change.Amount
+		if owned[uint32(vout)] != isChange {
+			t.Errorf("output %d (%d sats): own = %v, want %v", vout, txOut.Value, owned[uint32(vout)], isChange)
+		}
+	}
+
+	found, err := m.scanPendingTransaction(txMetadata.Tx, m.ownPrevOutScripts(txMetadata.Tx))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 1 || found[0].Label == nil || found[0].Label.M != 0 {
+		t.Fatalf("expected the change to be found with the change label, got %v", found)
+	}
+}
@@ -6,21 +6,22 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/setavenger/blindbit-desktop/internal/configs"
 	"github.com/setavenger/blindbit-lib/logging"
 	"github.com/setavenger/blindbit-lib/networking/grpc"
 	"github.com/setavenger/blindbit-lib/scanning/scannerv2"
+	"github.com/setavenger/blindbit-lib/types"
 	"github.com/setavenger/blindbit-lib/wallet"
-	"github.com/setavenger/go-bip352"
 )
 
 type Manager struct {
 	Wallet          *wallet.Wallet `json:"wallet_data"`
 	DataDir         string         `json:"-"`
 	DustLimit       int            `json:"dust_limit"`
-	LabelCount      int            `json:"label_count"` // should always be 0
+	LabelCount      int            `json:"label_count"` // labels 1 to LabelCount are scanned for besides change
 	MinChangeAmount uint64         `json:"min_change_amount"`
 	OracleAddress   string         `json:"oracle_address"` // for now only gRPC possible will need a flag and options in future
 	OracleUseTLS    bool           `json:"oracle_use_tls"`
@@ -30,9 +31,93 @@ type Manager struct {
 	// avoid contacting a third-party service (fingerprinting tradeoff).
 	FeeEstimationEnabled bool `json:"fee_estimation_enabled"`
 
-	TransactionHistory wallet.TxHistory     `json:"transaction_history"`
-	OracleClient       *grpc.OracleClient   `json:"-"`
-	Scanner            *scannerv2.ScannerV2 `json:"-"`
+	// KeepRunningInTray hides the window on close instead of quitting,
+	// so scanning continues in the background. Enabled by default.
+	KeepRunningInTray bool `json:"keep_running_in_tray"`
+
+	// ConfirmationTarget is the number of confirmations after which
+	// transactions are shown as confirmed instead of confirming
+	ConfirmationTarget int `json:"confirmation_target"`
+
+	// MinConfirmations is the number of confirmations a UTXO needs before it is spent,
+	// younger ones are left out of coin selection
+	MinConfirmations int `json:"min_confirmations"`
+
+	// MinFeeRate is the lowest fee rate in sat/vB accepted for a transaction, at least 1.
+	// Nodes reject transactions below their minimum relay fee.
+	MinFeeRate int `json:"min_fee_rate"`
+
+	// SaveEveryBlocks and SaveIntervalSeconds decide how often the wallet is saved while scanning.
+	// Frequent saves mean less to rescan after a crash but more disk writes.
+	SaveEveryBlocks     int `json:"save_every_blocks"`
+	SaveIntervalSeconds int `json:"save_interval_seconds"`
+
+	// ChainTipRefreshSeconds and UTXORefreshSeconds decide how often the GUI
+	// asks for the chain tip and reloads the UTXOs, at least configs.MinRefreshSeconds
+	ChainTipRefreshSeconds int `json:"chain_tip_refresh_seconds"`
+	UTXORefreshSeconds     int `json:"utxo_refresh_seconds"`
+
+	// PauseScanningOnBattery stops watching for new blocks while the computer runs on battery
+	PauseScanningOnBattery bool `json:"pause_scanning_on_battery"`
+
+	// Offline stops all network traffic until turned off again, see offline.go
+	Offline bool `json:"offline"`
+
+	// TipPollsPerSecond limits the chain tip polling, 0 picks a rate by address, see TipPollRate
+	TipPollsPerSecond float64 `json:"tip_polls_per_second"`
+
+	// CPULimitPercent is the share of the CPU cores the wallet runs on, 1-100.
+	// Lower values leave more room for other programs during heavy scans but scan slower.
+	CPULimitPercent int `json:"cpu_limit_percent"`
+
+	// LogLevel is one of configs.LogLevels, --debug overrides it.
+	// LogToFile writes the log to configs.LogFilename in the data dir as well, enabled by default.
+	LogLevel  string `json:"log_level"`
+	LogToFile bool   `json:"log_to_file"`
+
+	// CoinSelectionStrategy decides which UTXOs are spent first.
+	// An empty value falls back to largest-first.
+	CoinSelectionStrategy CoinSelectionStrategy `json:"coin_selection_strategy"`
+
+	// BroadcastBackend selects where signed transactions are pushed to.
+	// An empty value falls back to mempool.space.
+	BroadcastBackend BroadcastBackend `json:"broadcast_backend"`
+	// ElectrumAddress is the Electrum server used when BroadcastBackend is electrum.
+	// Format: ssl://host:port or tcp://host:port
+	ElectrumAddress string `json:"electrum_address"`
+
+	// ExplorerURLs overrides the block explorer per network, see configs.GetExplorerTxURL.
+	// Networks without an entry use mempool.space.
+	ExplorerURLs map[types.Network]string `json:"explorer_urls,omitempty"`
+
+	// FailedBroadcasts holds transactions the backend refused.
+	// They are kept apart from TransactionHistory so rejected sends never show up as sent.
+	FailedBroadcasts []*FailedBroadcast `json:"failed_broadcasts"`
+	// PendingBroadcasts holds transactions whose broadcast timed out or could not reach the backend.
+	// They may have been sent, retrying the same transaction is safe, see BroadcastTransaction.
+	PendingBroadcasts []*PendingBroadcast `json:"pending_broadcasts,omitempty"`
+
+	// UTXONotes and TxNotes hold user notes keyed by "txid:vout" and txid (hex, display order)
+	UTXONotes map[string]string `json:"utxo_notes,omitempty"`
+	TxNotes   map[string]string `json:"tx_notes,omitempty"`
+
+	// ReceiveLabels are the labeled addresses handed out on the Receive tab, see labels.go.
+	// Guarded by walletMu like the wallet's labels they are computed into.
+	ReceiveLabels []*ReceiveLabel `json:"receive_labels,omitempty"`
+
+	// PausedRescan is kept until the rescan is resumed or a new one started, see rescan.go.
+	// Written under rescan.mu, read it via GetPausedRescan.
+	PausedRescan *PausedRescan `json:"paused_rescan,omitempty"`
+
+	TransactionHistory wallet.TxHistory `json:"transaction_history"`
+	// OracleClient and Scanner are replaced by ReconnectOracle, read them under lifecycleMu
+	// or via oracleClient and GetScanner
+	OracleClient *grpc.OracleClient   `json:"-"`
+	Scanner      *scannerv2.ScannerV2 `json:"-"`
+
+	// RestoredFromBackup is set when the wallet file was unreadable on load
+	// and the backup of the previous save was used instead
+	RestoredFromBackup bool `json:"-"`
 
 	// scnaner channels - internal use only
 	// Deprecated: modify the blindbit-lib scanner
@@ -43,62 +128,173 @@ type Manager struct {
 	// GUI update channels - for real-time UI updates
 	GUIScanProgressChan chan uint32 `json:"-"` // todo: review sense of this channel logic
 	StreamEndChan       chan bool   `json:"-"` // Signal when scanning streams end
+
+	// background work lifecycle, see Context and Shutdown
+	lifecycleMu sync.Mutex
+	ctx         context.Context
+	cancel      context.CancelFunc
+	workers     sync.WaitGroup
+	// the scanner Stop was called on, Stop must not be called twice on one scanner
+	stoppedScanner *scannerv2.ScannerV2
+	// handlers of the running workers, reused by ReconnectOracle
+	saveFunc   func() error
+	onWatchErr func(error)
+	// contexts the watcher and channel handlers run under, a second start under the same one is a no-op
+	watchCtx, handlingCtx context.Context
+	// stops the running watcher alone, watchDone is closed once it returned
+	stopWatch context.CancelFunc
+	watchDone chan struct{}
+	// number of holdWatcher callers which did not release the watcher yet
+	watchHolds int
+	// set while the watcher is stopped because the computer runs on battery, see battery.go
+	batteryPaused bool
+	// settings the running oracle client and channel handlers were started with, see NeedsReconnect
+	active activeSettings
+	// shared by all tip polls of the running oracle client, see waitForTipPoll
+	tipPollLimiter *tokenBucket
+
+	scanRate scanRate
+	rescan   rescanWindow
+
+	// guards Wallet.LastScanHeight, Wallet.UTXOs and ReceiveLabels, see walletstate.go
+	walletMu sync.RWMutex
+	// guards TransactionHistory, taken before walletMu when both are needed
+	historyMu sync.RWMutex
+
+	// main address cached by GetSilentPaymentAddress for addressNetwork
+	addressMu      sync.Mutex
+	address        string
+	addressNetwork types.Network
+
+	// last chain tip seen by GetCurrentHeight
+	tipMu sync.RWMutex
+	tip   uint32
+
+	// periodic info level summary of the scan progress
+	scanSummary scanSummary
+
+	// set by ConstructScanner and when going online, guarded by lifecycleMu, see OracleNetworkError
+	oracleNetworkErr error
+
+	// outcome of the GetCurrentHeight requests, see OracleHealth
+	oracleHealth oracleHealthTracker
 }
 
 func NewManager() *Manager {
 	return &Manager{
-		Wallet:               &wallet.Wallet{},
-		DataDir:              "",
-		DustLimit:            configs.DefaultMinimumAmount,       // default
-		LabelCount:           configs.DefaultLabelCount,          // default
-		MinChangeAmount:      configs.DefaultMinimumAmount,       // default
-		OracleAddress:        configs.DefaultOracleAddressSignet, // set basic default
-		FeeEstimationEnabled: true,
-		TransactionHistory:   wallet.TxHistory{},     // Initialize empty TxHistory
-		Scanner:              nil,                  // Don't initialize scanner until needed
-		GUIScanProgressChan:  make(chan uint32, 100), // Buffer for GUI updates
-		StreamEndChan:        make(chan bool, 10),    // Buffer for stream end signals
+		Wallet:                 &wallet.Wallet{},
+		DataDir:                "",
+		DustLimit:              configs.DefaultMinimumAmount,       // default
+		LabelCount:             configs.DefaultLabelCount,          // default
+		MinChangeAmount:        configs.DefaultMinimumAmount,       // default
+		OracleAddress:          configs.DefaultOracleAddressSignet, // set basic default
+		FeeEstimationEnabled:   true,
+		KeepRunningInTray:      true,
+		ConfirmationTarget:     configs.DefaultConfirmationTarget,
+		MinConfirmations:       configs.DefaultMinConfirmations,
+		MinFeeRate:             configs.DefaultMinFeeRate,
+		SaveEveryBlocks:        configs.DefaultSaveEveryBlocks,
+		SaveIntervalSeconds:    configs.DefaultSaveIntervalSeconds,
+		ChainTipRefreshSeconds: configs.DefaultChainTipRefreshSeconds,
+		UTXORefreshSeconds:     configs.DefaultUTXORefreshSeconds,
+		CPULimitPercent:        configs.DefaultCPULimitPercent,
+		LogLevel:               configs.DefaultLogLevel,
+		LogToFile:              true,
+		BroadcastBackend:       BroadcastBackendMempoolSpace,
+		CoinSelectionStrategy:  CoinSelectionLargestFirst,
+		TransactionHistory:     wallet.TxHistory{},     // Initialize empty TxHistory
+		Scanner:                nil,                    // Don't initialize scanner until needed
+		GUIScanProgressChan:    make(chan uint32, 100), // Buffer for GUI updates
+		StreamEndChan:          make(chan bool, 10),    // Buffer for stream end signals
 	}
 }
 
+// oracleClient returns the current oracle client, nil before ConstructScanner.
+// Callers keep the returned client for the whole request, ReconnectOracle may swap it meanwhile.
+func (m *Manager) oracleClient() *grpc.OracleClient {
+	m.lifecycleMu.Lock()
+	defer m.lifecycleMu.Unlock()
+	return m.OracleClient
+}
+
+// GetScanner returns the current scanner, nil before ConstructScanner
+func (m *Manager) GetScanner() *scannerv2.ScannerV2 {
+	m.lifecycleMu.Lock()
+	defer m.lifecycleMu.Unlock()
+	return m.Scanner
+}
+
+// swapOracleClient puts client in place together with the settings and rate limit it was built for.
+// Returns the previous client, which the caller closes.
+func (m *Manager) swapOracleClient(client *grpc.OracleClient) *grpc.OracleClient {
+	m.lifecycleMu.Lock()
+	defer m.lifecycleMu.Unlock()
+
+	previous := m.OracleClient
+	m.OracleClient = client
+	m.active.oracleAddress, m.active.oracleUseTLS = m.OracleAddress, m.OracleUseTLS
+	m.active.tipPollRate = m.TipPollRate()
+	m.tipPollLimiter = newTokenBucket(m.active.tipPollRate, configs.TipPollBurst)
+	return previous
+}
+
 func (m *Manager) ConstructScanner(ctx context.Context) error {
 	if m.Wallet == nil {
-		return errors.New("wallet not initialized")
+		return ErrNoWallet
 	}
 	if m.OracleAddress == "" {
 		return errors.New("address is empty string")
 	}
-	if m.OracleClient == nil {
-		oracleClient, err := grpc.NewClient(ctx, m.OracleAddress, m.OracleUseTLS)
+	oracleClient := m.oracleClient()
+	if oracleClient == nil {
+		var err error
+		oracleClient, err = grpc.NewClient(ctx, m.OracleAddress, m.OracleUseTLS)
 		if err != nil {
 			logging.L.Err(err).
 				Str("address", m.OracleAddress).
 				Msg("failed to constuct scanner")
 			return err
 		}
-		m.OracleClient = oracleClient
+		m.swapOracleClient(oracleClient)
 	}
 
-	// we only use change labels for now
-	labels := []*bip352.Label{m.Wallet.GetLabel(0)}
+	labels := m.scanLabels()
+	receiveLabels := m.receiveLabelCount()
+	m.lifecycleMu.Lock()
+	m.active.receiveLabels, m.active.labelCount = receiveLabels, m.LabelCount
+	m.lifecycleMu.Unlock()
+
+	networkErr := m.checkOracleInfo(ctx)
+	m.setOracleNetworkError(networkErr)
 
+	// todo: ScannerV2 does not forward a dust limit, its ranged requests leave
+	//  RangedBlockHeightRequestFiltered.Dustlimit unset and the oracle client is not configurable
+	//  from here. DustLimit only takes effect once blindbit-lib exposes it.
 	scanner := scannerv2.NewScannerV2(
-		m.OracleClient,
+		oracleClient,
 		m.Wallet.SecretKeyScan,
 		m.Wallet.PubKeySpend,
 		labels,
 	)
 
-	m.Scanner = scanner
-	err := m.Scanner.AttachWallet(m.Wallet)
+	err := scanner.AttachWallet(m.Wallet)
 	if err != nil {
 		logging.L.Err(err).Msg("failed to attach wallet to scanner")
 		return err
 	}
 
-	m.OwnedUTXOsChan = m.Scanner.SubscribeOwnedUTXOs()
-	m.ProgressUpdateChan = m.Scanner.ProgressUpdateChan()
+	m.OwnedUTXOsChan = scanner.SubscribeOwnedUTXOs()
+	m.ProgressUpdateChan = scanner.ProgressUpdateChan()
+
+	m.lifecycleMu.Lock()
+	m.Scanner = scanner
+	m.lifecycleMu.Unlock()
 
+	// the scanner is still set up so the GUI works and the oracle can be changed in settings
+	if networkErr != nil {
+		logging.L.Err(networkErr).Str("address", m.OracleAddress).Msg("oracle network check failed")
+		return networkErr
+	}
 	return nil
 }
 
@@ -106,6 +302,14 @@ func (m *Manager) ConstructScanner(ctx context.Context) error {
 
 // Serialise creates byte data which can then be stored in an arbitrary way
 func (m *Manager) Serialise() ([]byte, error) {
+	// PausedRescan is written under rescan.mu, which goes before historyMu
+	m.rescan.mu.Lock()
+	defer m.rescan.mu.Unlock()
+	m.historyMu.RLock()
+	defer m.historyMu.RUnlock()
+	m.walletMu.RLock()
+	defer m.walletMu.RUnlock()
+
 	// Marshal to JSON
 	jsonData, err := json.MarshalIndent(m, "", "  ")
 	if err != nil {
@@ -120,6 +324,16 @@ func (m *Manager) DeSerialise(data []byte) error {
 		return err
 	}
 	_, hasFeeEstimation := raw["fee_estimation_enabled"]
+	_, hasKeepRunningInTray := raw["keep_running_in_tray"]
+	_, hasConfirmationTarget := raw["confirmation_target"]
+	_, hasMinConfirmations := raw["min_confirmations"]
+	_, hasMinFeeRate := raw["min_fee_rate"]
+	_, hasSaveEveryBlocks := raw["save_every_blocks"]
+	_, hasSaveIntervalSeconds := raw["save_interval_seconds"]
+	_, hasChainTipRefresh := raw["chain_tip_refresh_seconds"]
+	_, hasUTXORefresh := raw["utxo_refresh_seconds"]
+	_, hasCPULimit := raw["cpu_limit_percent"]
+	_, hasLogToFile := raw["log_to_file"]
 	if err := json.Unmarshal(data, m); err != nil {
 		return err
 	}
@@ -127,6 +341,40 @@ func (m *Manager) DeSerialise(data []byte) error {
 		// Wallets saved before this field existed default to on.
 		m.FeeEstimationEnabled = true
 	}
+	if !hasKeepRunningInTray {
+		m.KeepRunningInTray = true
+	}
+	if !hasConfirmationTarget {
+		m.ConfirmationTarget = configs.DefaultConfirmationTarget
+	}
+	if !hasMinConfirmations {
+		m.MinConfirmations = configs.DefaultMinConfirmations
+	}
+	if !hasMinFeeRate {
+		m.MinFeeRate = configs.DefaultMinFeeRate
+	}
+	if !hasSaveEveryBlocks {
+		m.SaveEveryBlocks = configs.DefaultSaveEveryBlocks
+	}
+	if !hasSaveIntervalSeconds {
+		m.SaveIntervalSeconds = configs.DefaultSaveIntervalSeconds
+	}
+	if !hasChainTipRefresh {
+		m.ChainTipRefreshSeconds = configs.DefaultChainTipRefreshSeconds
+	}
+	if !hasUTXORefresh {
+		m.UTXORefreshSeconds = configs.DefaultUTXORefreshSeconds
+	}
+	if !hasCPULimit {
+		m.CPULimitPercent = configs.DefaultCPULimitPercent
+	}
+	if !hasLogToFile {
+		m.LogToFile = true
+	}
+	// older versions could record a transaction more than once
+	if _, err := m.DeduplicateHistory(); err != nil {
+		logging.L.Err(err).Msg("failed to deduplicate transaction history")
+	}
 	return nil
 }
 
@@ -135,7 +383,7 @@ func (m *Manager) DeSerialise(data []byte) error {
 // GetBalance returns the total balance of all UTXOs
 func (m *Manager) GetBalance() uint64 {
 	var total uint64
-	utxos := m.Wallet.GetUTXOs()
+	utxos := m.GetUTXOs()
 
 	for _, utxo := range utxos {
 		if utxo.State != wallet.StateUnspent {
@@ -147,6 +395,37 @@ func (m *Manager) GetBalance() uint64 {
 	return total
 }
 
+// BalanceBreakdown splits the balance by how soon it can be spent
+type BalanceBreakdown struct {
+	// Confirmed is unspent with at least MinConfirmations confirmations, coin selection uses it
+	Confirmed uint64
+	// Immature is unspent but below MinConfirmations
+	Immature uint64
+	// Unconfirmed is received but not in a block yet, it is not part of the balance
+	Unconfirmed uint64
+}
+
+// Balance is Confirmed plus Immature, the same as GetBalance
+func (b BalanceBreakdown) Balance() uint64 {
+	return b.Confirmed + b.Immature
+}
+
+// GetBalanceBreakdown sums the wallet's UTXOs by spendability
+func (m *Manager) GetBalanceBreakdown() BalanceBreakdown {
+	var breakdown BalanceBreakdown
+	for _, utxo := range m.GetUTXOs() {
+		switch {
+		case utxo.State == wallet.StateUnconfirmed:
+			breakdown.Unconfirmed += utxo.Amount
+		case m.IsImmature(utxo):
+			breakdown.Immature += utxo.Amount
+		case utxo.State == wallet.StateUnspent:
+			breakdown.Confirmed += utxo.Amount
+		}
+	}
+	return breakdown
+}
+
 // GetBirthHeight returns the wallet's birth height
 func (m *Manager) GetBirthHeight() uint64 {
 	if m.Wallet == nil {
@@ -162,22 +441,127 @@ func (m *Manager) SetBirthHeight(height uint64, setLastScanHeight bool) {
 	}
 	m.Wallet.BirthHeight = height
 	if setLastScanHeight {
-		m.Wallet.LastScanHeight = height
+		m.SetScanHeight(height)
+	}
+}
+
+// BirthHeightByDate returns the block height at date for the wallet's network.
+// The height can be slightly earlier than the actual block to not miss any payments.
+func (m *Manager) BirthHeightByDate(date time.Time) (uint64, error) {
+	if date.After(time.Now()) {
+		return 0, errors.New("date is in the future")
+	}
+	if m.IsOffline() {
+		return 0, ErrOffline
 	}
+	return configs.GetBlockHeightByDate(m.GetNetwork(), date)
 }
 
-// GetCurrentHeight queries the oracle for the current blockchain height
+// RescanRange rescans the blocks from to to (inclusive) to find payments which were missed.
+// New UTXOs are added, UTXOs outside the range are kept and the scan cursor is not moved.
+// The watcher is stopped for the rescan, the scanner skips a scan while it is busy.
+func (m *Manager) RescanRange(ctx context.Context, from, to uint32) error {
+	scanner := m.GetScanner()
+	if scanner == nil {
+		return ErrScannerNotReady
+	}
+	if m.IsOffline() {
+		return ErrOffline
+	}
+	if err := m.OracleNetworkError(); err != nil {
+		return err
+	}
+	if from > to {
+		return fmt.Errorf("start height %d is above end height %d", from, to)
+	}
+	if tip, err := m.GetCurrentHeight(); err == nil && to > tip {
+		return fmt.Errorf("end height %d is above the chain tip %d", to, tip)
+	}
+
+	// SetOffline cancels the rescan through rangeCancel
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	m.rescan.mu.Lock()
+	if m.rescan.active || m.rescan.rangeCancel != nil {
+		m.rescan.mu.Unlock()
+		return ErrRescanRunning
+	}
+	m.rescan.rangeCancel = cancel
+	m.rescan.mu.Unlock()
+	defer func() {
+		m.rescan.mu.Lock()
+		m.rescan.rangeCancel = nil
+		m.rescan.mu.Unlock()
+	}()
+	if m.IsOffline() {
+		// went offline before rangeCancel was set
+		return ErrOffline
+	}
+
+	release, err := m.holdWatcher()
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	logging.L.Info().
+		Uint32("from", from).
+		Uint32("to", to).
+		Msg("rescanning height range")
+
+	// rescan mode neither moves the scanner's cursor nor sends progress updates
+	if err := scanner.Scan(ctx, from, to, true); err != nil {
+		if m.IsOffline() {
+			return fmt.Errorf("rescan of %d-%d stopped: %w", from, to, ErrOffline)
+		}
+		return fmt.Errorf("failed to rescan %d-%d: %w", from, to, err)
+	}
+	return nil
+}
+
+// oracleCallTimeout bounds single oracle requests so a slow oracle can't stall callers
+const oracleCallTimeout = 10 * time.Second
+
+// GetCurrentHeight queries the oracle for the current blockchain height.
+// While offline it returns the last known height, ErrOffline if there is none.
 func (m *Manager) GetCurrentHeight() (uint32, error) {
-	if m.OracleClient == nil {
-		return 0, errors.New("oracle client not initialized")
+	if m.IsOffline() {
+		if tip := m.CachedChainTip(); tip > 0 {
+			return tip, nil
+		}
+		return 0, ErrOffline
+	}
+	oracleClient := m.oracleClient()
+	if oracleClient == nil {
+		return 0, ErrOracleNotReady
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), oracleCallTimeout)
+	defer cancel()
+
+	if err := m.waitForTipPoll(ctx); err != nil {
+		return 0, err
 	}
-	resp, err := m.OracleClient.GetInfo(context.TODO())
+	resp, err := oracleClient.GetInfo(ctx)
+	m.oracleHealth.record(err)
 	if err != nil {
 		return 0, err
 	}
+
+	m.tipMu.Lock()
+	m.tip = uint32(resp.Height)
+	m.tipMu.Unlock()
+
 	return uint32(resp.Height), nil
 }
 
+// CachedChainTip returns the tip of the last GetCurrentHeight call without asking the oracle,
+// 0 if it was never fetched
+func (m *Manager) CachedChainTip() uint32 {
+	m.tipMu.RLock()
+	defer m.tipMu.RUnlock()
+	return m.tip
+}
+
 // SignalStreamEnd signals that a scanning stream has ended
 func (m *Manager) SignalStreamEnd() {
 	select {
@@ -189,7 +573,22 @@ func (m *Manager) SignalStreamEnd() {
 }
 
 // StartChannelHandling starts unified handling of scanner channels for background operations
+// Calling it again while the handlers run under ctx does nothing.
 func (m *Manager) StartChannelHandling(ctx context.Context, saveFunc func() error) {
+	// kept without a scanner too, history changes are saved with it
+	m.lifecycleMu.Lock()
+	if m.handlingCtx == ctx && ctx.Err() == nil {
+		m.lifecycleMu.Unlock()
+		logging.L.Debug().Msg("channel handling already running")
+		return
+	}
+	m.handlingCtx = ctx
+	m.saveFunc = saveFunc
+	m.lifecycleMu.Unlock()
+
+	m.startPendingConfirmationPolling(ctx)
+	m.startBatteryMonitor(ctx)
+
 	if m.OwnedUTXOsChan == nil || m.ProgressUpdateChan == nil {
 		logging.L.Warn().Msg("scanner channels not initialized, skipping channel handling")
 		return
@@ -197,21 +596,43 @@ func (m *Manager) StartChannelHandling(ctx context.Context, saveFunc func() erro
 
 	logging.L.Info().Msg("starting unified channel handling for background scanning")
 
+	m.lifecycleMu.Lock()
+	m.active.saveEveryBlocks, m.active.saveIntervalSeconds = m.SaveEveryBlocks, m.SaveIntervalSeconds
+	m.lifecycleMu.Unlock()
+
+	blocksBetweenSaves := m.SaveEveryBlocks
+	if blocksBetweenSaves < 1 {
+		blocksBetweenSaves = configs.DefaultSaveEveryBlocks
+	}
+	saveInterval := m.SaveIntervalSeconds
+	if saveInterval < 1 {
+		saveInterval = configs.DefaultSaveIntervalSeconds
+	}
+
 	// Channel for periodic saves
-	saveTicker := time.NewTicker(15 * time.Second) // Save every 15 seconds
-	defer saveTicker.Stop()
+	saveTicker := time.NewTicker(time.Duration(saveInterval) * time.Second)
 
 	// Channel for block-based saves
 	blockSaveCounter := 0
-	const blocksBetweenSaves = 100 // Save every 100 blocks
+
+	m.workers.Add(2)
 
 	// Handle progress updates and periodic saves
 	go func() {
+		defer m.workers.Done()
+		defer saveTicker.Stop()
+
 		for {
 			select {
 			case height := <-m.ProgressUpdateChan:
+				if !m.rescan.observe(height) {
+					// last blocks of a paused rescan
+					continue
+				}
 				// Update wallet's LastScanHeight
-				m.Wallet.LastScanHeight = uint64(height)
+				m.SetScanHeight(uint64(height))
+				m.scanRate.observe(height)
+				m.scanSummary.observe(height)
 				// logging.L.Debug().Uint32("scan_height", height).Msg("scan progress update")
 
 				// Forward progress update to GUI channel for real-time updates
@@ -223,7 +644,7 @@ func (m *Manager) StartChannelHandling(ctx context.Context, saveFunc func() erro
 					// logging.L.Debug().Msg("GUI progress channel full, skipping update")
 				}
 
-				// Save every other block
+				// Save every blocksBetweenSaves blocks
 				blockSaveCounter++
 				if blockSaveCounter >= blocksBetweenSaves {
 					if err := saveFunc(); err != nil {
@@ -233,7 +654,7 @@ func (m *Manager) StartChannelHandling(ctx context.Context, saveFunc func() erro
 				}
 
 			case <-saveTicker.C:
-				// Periodic save every 30 seconds
+				// Periodic save
 				if err := saveFunc(); err != nil {
 					logging.L.Err(err).Msg("failed to save wallet periodically")
 				} else {
@@ -249,6 +670,8 @@ func (m *Manager) StartChannelHandling(ctx context.Context, saveFunc func() erro
 
 	// Handle new UTXOs
 	go func() {
+		defer m.workers.Done()
+
 		for {
 			select {
 			case utxo := <-m.OwnedUTXOsChan:
@@ -259,7 +682,10 @@ func (m *Manager) StartChannelHandling(ctx context.Context, saveFunc func() erro
 					Uint32("height", utxo.Height).
 					Msg("new UTXO discovered")
 
-				err := m.TransactionHistory.AddOutUtxo(utxo)
+				m.scanSummary.match()
+				m.confirmPendingUTXO(utxo)
+
+				err := m.addOutUtxoToHistory(utxo)
 				if err != nil {
 					logging.L.Err(err).Msg("failed to add out UTXO to transaction history")
 					continue
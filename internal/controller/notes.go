@@ -0,0 +1,50 @@
+package controller
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// Notes are keyed by outpoint and txid, not by scan state,
+// so they survive rescans and UTXOs being dropped and found again.
+
+// utxoNoteKey formats an outpoint as "txid:vout", txid in display order
+func utxoNoteKey(outpoint [36]byte) string {
+	return fmt.Sprintf("%s:%d", hex.EncodeToString(outpoint[:32]), binary.LittleEndian.Uint32(outpoint[32:]))
+}
+
+// SetUTXONote attaches a free-text note to the UTXO at outpoint (see OwnedUTXO.SerialiseToOutpoint).
+// An empty note removes it.
+func (m *Manager) SetUTXONote(outpoint [36]byte, note string) {
+	m.UTXONotes = setNote(m.UTXONotes, utxoNoteKey(outpoint), note)
+}
+
+// UTXONote returns the note for the UTXO at outpoint, empty if there is none
+func (m *Manager) UTXONote(outpoint [36]byte) string {
+	return m.UTXONotes[utxoNoteKey(outpoint)]
+}
+
+// SetTxNote attaches a free-text note to a transaction. An empty note removes it.
+func (m *Manager) SetTxNote(txid [32]byte, note string) {
+	m.TxNotes = setNote(m.TxNotes, hex.EncodeToString(txid[:]), note)
+}
+
+// TxNote returns the note for txid, empty if there is none
+func (m *Manager) TxNote(txid [32]byte) string {
+	return m.TxNotes[hex.EncodeToString(txid[:])]
+}
+
+func setNote(notes map[string]string, key, note string) map[string]string {
+	note = strings.TrimSpace(note)
+	if note == "" {
+		delete(notes, key)
+		return notes
+	}
+	if notes == nil {
+		notes = make(map[string]string)
+	}
+	notes[key] = note
+	return notes
+}
@@ -0,0 +1,48 @@
+package controller
+
+import (
+	"errors"
+
+	"github.com/setavenger/blindbit-lib/logging"
+)
+
+// ErrOffline is returned by actions which need the network while offline mode is on
+var ErrOffline = errors.New("wallet is in offline mode")
+
+// IsOffline reports whether offline mode is on.
+// Nothing is sent to the oracle, the broadcast backend or mempool.space while it is,
+// the wallet shows what was saved last.
+func (m *Manager) IsOffline() bool {
+	m.lifecycleMu.Lock()
+	defer m.lifecycleMu.Unlock()
+	return m.Offline
+}
+
+// SetOffline turns offline mode on or off.
+// Going offline stops the watcher, pauses a running rescan and cancels a range rescan.
+// Going online checks the oracle again and continues scanning from the saved scan height,
+// a paused rescan waits to be resumed.
+func (m *Manager) SetOffline(offline bool) {
+	m.lifecycleMu.Lock()
+	changed := m.Offline != offline
+	m.Offline = offline
+	m.lifecycleMu.Unlock()
+	if !changed {
+		return
+	}
+
+	if offline {
+		m.stopWatching()
+		m.stopRescans()
+		logging.L.Info().Uint64("scan_height", m.ScanHeight()).Msg("offline mode on, scanning stopped")
+		return
+	}
+
+	logging.L.Info().Msg("offline mode off, scanning resumed")
+	if m.oracleClient() == nil {
+		return
+	}
+	ctx := m.Context()
+	m.setOracleNetworkError(m.checkOracleInfo(ctx))
+	m.restartWatching(ctx)
+}
@@ -0,0 +1,134 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/setavenger/blindbit-lib/networking/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// OracleStatus summarises recent oracle requests
+type OracleStatus int
+
+const (
+	// OracleUnknown means no request has completed yet
+	OracleUnknown OracleStatus = iota
+	// OracleConnected means the last request succeeded
+	OracleConnected
+	// OracleRetrying means the last requests failed, but fewer than oracleDownAfter in a row
+	OracleRetrying
+	// OracleDown means at least oracleDownAfter requests in a row failed
+	OracleDown
+)
+
+// oracleDownAfter is the number of failed requests in a row after which the oracle counts as down.
+// The GUI asks for the chain tip every 10 seconds, so this is about half a minute.
+const oracleDownAfter = 3
+
+func (s OracleStatus) String() string {
+	switch s {
+	case OracleConnected:
+		return "connected"
+	case OracleRetrying:
+		return "retrying"
+	case OracleDown:
+		return "down"
+	}
+	return "unknown"
+}
+
+// OracleHealth is a snapshot of the oracle connectivity
+type OracleHealth struct {
+	Status      OracleStatus
+	LastSuccess time.Time
+	LastError   error
+	LastErrorAt time.Time
+	// Failures counts failed requests since the last success
+	Failures int
+}
+
+type oracleHealthTracker struct {
+	mu     sync.Mutex
+	health OracleHealth
+}
+
+func (t *oracleHealthTracker) record(err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if err == nil {
+		t.health.Status = OracleConnected
+		t.health.LastSuccess = time.Now()
+		t.health.Failures = 0
+		return
+	}
+
+	t.health.LastError = err
+	t.health.LastErrorAt = time.Now()
+	t.health.Failures++
+	if t.health.Failures >= oracleDownAfter {
+		t.health.Status = OracleDown
+	} else {
+		t.health.Status = OracleRetrying
+	}
+}
+
+func (t *oracleHealthTracker) snapshot() OracleHealth {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.health
+}
+
+// OracleHealth reports how the recent GetInfo requests to the oracle went
+func (m *Manager) OracleHealth() OracleHealth {
+	return m.oracleHealth.snapshot()
+}
+
+// OracleTestResult is what TestOracleConnection learned from the oracle
+type OracleTestResult struct {
+	Network string
+	Height  uint64
+	Latency time.Duration
+}
+
+// TestOracleConnection asks the oracle at address for its info without touching the manager,
+// so a new address can be checked before it is saved
+func TestOracleConnection(address string, useTLS bool) (*OracleTestResult, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), oracleCallTimeout)
+	defer cancel()
+
+	client, err := grpc.NewClient(ctx, address, useTLS)
+	if err != nil {
+		return nil, fmt.Errorf("invalid oracle address: %w", err)
+	}
+	defer client.Close()
+
+	start := time.Now()
+	info, err := client.GetInfo(ctx)
+	if err != nil {
+		return nil, describeOracleError(err)
+	}
+
+	return &OracleTestResult{
+		Network: info.Network,
+		Height:  info.Height,
+		Latency: time.Since(start),
+	}, nil
+}
+
+// describeOracleError turns gRPC failures into a hint what to check
+func describeOracleError(err error) error {
+	switch status.Code(err) {
+	case codes.DeadlineExceeded:
+		return fmt.Errorf("no answer within %s, the oracle may be down or the address wrong: %w", oracleCallTimeout, err)
+	case codes.Unavailable:
+		return fmt.Errorf("oracle unreachable, check address, port and the TLS setting: %w", err)
+	case codes.Unimplemented:
+		return fmt.Errorf("the server does not look like a BlindBit oracle: %w", err)
+	}
+	return fmt.Errorf("oracle request failed: %w", err)
+}
@@ -0,0 +1,104 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/setavenger/blindbit-lib/logging"
+	"github.com/setavenger/blindbit-lib/types"
+)
+
+// ErrOracleNetworkMismatch means the oracle indexes a different chain than the wallet's.
+// Scanning against it would look for outputs on the wrong chain.
+var ErrOracleNetworkMismatch = errors.New("oracle serves a different network than the wallet")
+
+// oracleNetwork maps the network name reported by an oracle to a wallet network.
+// Oracles may report bitcoind's chain names (main, test) as well as the wallet's names.
+func oracleNetwork(name string) (types.Network, bool) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "main", "mainnet", "bitcoin":
+		return types.NetworkMainnet, true
+	case "test", "testnet", "testnet3", "testnet4":
+		return types.NetworkTestnet, true
+	case "signet":
+		return types.NetworkSignet, true
+	case "regtest":
+		return types.NetworkRegtest, true
+	}
+	return "", false
+}
+
+// CheckOracleNetwork returns ErrOracleNetworkMismatch if the network reported by the oracle
+// is not the wallet's. Names which cannot be mapped are let through with a warning.
+func CheckOracleNetwork(reported string, want types.Network) error {
+	network, ok := oracleNetwork(reported)
+	if !ok {
+		logging.L.Warn().
+			Str("oracle_network", reported).
+			Str("wallet_network", string(want)).
+			Msg("cannot verify the oracle's network")
+		return nil
+	}
+	if network != want {
+		return fmt.Errorf("%w: oracle is on %s, wallet is on %s", ErrOracleNetworkMismatch, network, want)
+	}
+	return nil
+}
+
+// checkOracleInfo asks the oracle for its info once the client exists.
+// It verifies the network and reports whether the oracle can filter dust outputs server side,
+// so a configured DustLimit can be honored once the scanner passes it along.
+// An unreachable oracle is only logged, the network is then checked on the next construction.
+// While offline the oracle is not asked, SetOffline checks it when going online.
+func (m *Manager) checkOracleInfo(ctx context.Context) error {
+	if m.IsOffline() {
+		logging.L.Info().Msg("offline mode on, oracle info is checked once online")
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	oracleClient := m.oracleClient()
+	if oracleClient == nil {
+		return nil
+	}
+	if err := m.waitForTipPoll(ctx); err != nil {
+		logging.L.Warn().Err(err).Msg("could not query oracle info")
+		return nil
+	}
+	info, err := oracleClient.GetInfo(ctx)
+	if err != nil {
+		logging.L.Warn().Err(err).Msg("could not query oracle info")
+		return nil
+	}
+
+	supported := info.TweaksFullWithDustFilter || info.TweaksCutThroughWithDustFilter
+	event := logging.L.Info()
+	if m.DustLimit > 0 && !supported {
+		event = logging.L.Warn()
+	}
+	event.
+		Bool("dust_filter_supported", supported).
+		Int("dust_limit", m.DustLimit).
+		Msg("oracle dust filter")
+
+	return CheckOracleNetwork(info.Network, m.GetNetwork())
+}
+
+// OracleNetworkError returns the network mismatch found when the scanner was constructed.
+// Scanning is refused while it is set.
+func (m *Manager) OracleNetworkError() error {
+	m.lifecycleMu.Lock()
+	defer m.lifecycleMu.Unlock()
+	return m.oracleNetworkErr
+}
+
+// setOracleNetworkError stores the result of the last oracle network check
+func (m *Manager) setOracleNetworkError(err error) {
+	m.lifecycleMu.Lock()
+	defer m.lifecycleMu.Unlock()
+	m.oracleNetworkErr = err
+}
@@ -0,0 +1,119 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/shopspring/decimal"
+)
+
+// paymentURIScheme is the BIP21 scheme, silent payment addresses go in the path like any other address
+const paymentURIScheme = "bitcoin"
+
+// PaymentRequest is a BIP21 payment URI, bitcoin:<address>?amount=<btc>&label=<memo>
+type PaymentRequest struct {
+	Address string
+	// Amount in sats, 0 leaves it to the payer
+	Amount uint64
+	// Label is the memo shown to the payer
+	Label string
+}
+
+// URI encodes the request, the amount in BTC as BIP21 requires
+func (r PaymentRequest) URI() (string, error) {
+	if r.Address == "" {
+		return "", errors.New("payment request needs an address")
+	}
+	if r.Amount > btcutil.MaxSatoshi {
+		return "", errors.New("amount exceeds the bitcoin supply")
+	}
+
+	var params []string
+	if r.Amount > 0 {
+		params = append(params, "amount="+decimal.New(int64(r.Amount), -8).String())
+	}
+	if label := strings.TrimSpace(r.Label); label != "" {
+		params = append(params, "label="+escapeURIValue(label))
+	}
+
+	uri := paymentURIScheme + ":" + r.Address
+	if len(params) > 0 {
+		uri += "?" + strings.Join(params, "&")
+	}
+	return uri, nil
+}
+
+// escapeURIValue percent-encodes a query value, spaces as %20 since BIP21 has no form encoding
+func escapeURIValue(value string) string {
+	return strings.ReplaceAll(url.QueryEscape(value), "+", "%20")
+}
+
+// IsPaymentURI reports whether text starts with the bitcoin: scheme
+func IsPaymentURI(text string) bool {
+	text = strings.TrimSpace(text)
+	return len(text) > len(paymentURIScheme) &&
+		strings.EqualFold(text[:len(paymentURIScheme)+1], paymentURIScheme+":")
+}
+
+// ParsePaymentURI reads a BIP21 URI. The address comes from the path or, if that is empty,
+// from an sp parameter. A message stands in for a missing label.
+// Unknown parameters are ignored unless they carry the req- prefix, which BIP21 makes mandatory.
+func ParsePaymentURI(text string) (*PaymentRequest, error) {
+	text = strings.TrimSpace(text)
+	if !IsPaymentURI(text) {
+		return nil, errors.New("not a bitcoin: payment URI")
+	}
+	rest := text[len(paymentURIScheme)+1:]
+
+	address, rawQuery, _ := strings.Cut(rest, "?")
+	params, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return nil, fmt.Errorf("invalid payment URI parameters: %w", err)
+	}
+
+	for key := range params {
+		if strings.HasPrefix(strings.ToLower(key), "req-") {
+			return nil, fmt.Errorf("payment URI requires unsupported parameter %q", key)
+		}
+	}
+
+	request := &PaymentRequest{Address: strings.TrimSpace(address)}
+	if request.Address == "" {
+		request.Address = params.Get("sp")
+	}
+	if request.Address == "" {
+		return nil, errors.New("payment URI contains no address")
+	}
+
+	if amount := params.Get("amount"); amount != "" {
+		request.Amount, err = parseBTCAmount(amount)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	request.Label = params.Get("label")
+	if request.Label == "" {
+		request.Label = params.Get("message")
+	}
+	return request, nil
+}
+
+// parseBTCAmount converts a BIP21 amount in BTC to sats
+func parseBTCAmount(amount string) (uint64, error) {
+	value, err := decimal.NewFromString(amount)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q in payment URI", amount)
+	}
+	sats := value.Shift(8)
+	if !sats.IsInteger() {
+		return 0, fmt.Errorf("amount %s has more than 8 decimals", amount)
+	}
+	if sats.Sign() <= 0 || sats.GreaterThan(decimal.NewFromInt(btcutil.MaxSatoshi)) {
+		return 0, fmt.Errorf("amount %s is out of range", amount)
+	}
+	return uint64(sats.IntPart()), nil
+}
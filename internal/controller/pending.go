@@ -0,0 +1,423 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/setavenger/blindbit-desktop/internal/configs"
+	"github.com/setavenger/blindbit-desktop/internal/electrum"
+	"github.com/setavenger/blindbit-lib/logging"
+	"github.com/setavenger/blindbit-lib/types"
+	"github.com/setavenger/blindbit-lib/utils"
+	"github.com/setavenger/blindbit-lib/wallet"
+	"github.com/setavenger/go-bip352"
+)
+
+// The oracle only serves confirmed blocks, so unconfirmed receives are found by
+// scanning single mempool transactions fetched from the broadcast backend.
+// Those outputs are stored with wallet.StateUnconfirmed and switch to unspent
+// once the scanner finds them in a block (see confirmPendingUTXO).
+
+// GetPendingBalance returns the sum of all unconfirmed incoming UTXOs
+func (m *Manager) GetPendingBalance() uint64 {
+	var total uint64
+	for _, utxo := range m.GetUTXOs() {
+		if utxo.State != wallet.StateUnconfirmed {
+			continue
+		}
+		total += utxo.Amount
+	}
+	return total
+}
+
+// CheckPendingTransaction fetches txid from the configured broadcast backend
+// (Electrum or mempool.space) and adds outputs paying to this wallet as unconfirmed UTXOs.
+// Returns the newly found UTXOs.
+func (m *Manager) CheckPendingTransaction(ctx context.Context, txid string) ([]*wallet.OwnedUTXO, error) {
+	txid = strings.TrimSpace(txid)
+	if _, err := hex.DecodeString(txid); err != nil || len(txid) != 64 {
+		return nil, fmt.Errorf("invalid txid %q", txid)
+	}
+	if m.IsOffline() {
+		return nil, ErrOffline
+	}
+
+	var (
+		tx             *wire.MsgTx
+		prevOutScripts [][]byte
+		err            error
+	)
+	switch m.BroadcastBackend {
+	case BroadcastBackendElectrum:
+		tx, prevOutScripts, err = m.fetchTransactionElectrum(ctx, txid)
+	default:
+		tx, prevOutScripts, err = fetchTransactionMempoolSpace(ctx, txid, m.GetNetwork())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	found, err := m.scanPendingTransaction(tx, prevOutScripts)
+	if err != nil {
+		return nil, err
+	}
+
+	newUTXOs := m.addPendingUTXOs(found)
+	for _, utxo := range newUTXOs {
+		if err = m.addOutUtxoToHistory(utxo); err != nil {
+			logging.L.Err(err).Msg("failed to add pending UTXO to transaction history")
+			continue
+		}
+	}
+	if len(newUTXOs) > 0 {
+		m.historyMu.Lock()
+		// AddOutUtxo takes the height from the UTXO which is 0 for mempool outputs
+		if txItem := m.TransactionHistory.FindTxItemByTxID(newUTXOs[0].Txid); txItem != nil && txItem.ConfirmHeight == 0 {
+			txItem.ConfirmHeight = wallet.TxPending
+			m.TransactionHistory.Sort()
+		}
+		m.historyMu.Unlock()
+	}
+
+	return newUTXOs, nil
+}
+
+// trackOwnPendingOutputs adds outputs of a transaction we just broadcast that pay back
+// to the wallet (change) as unconfirmed UTXOs. The spent coins are our own,
+// so the prevout scripts are known without asking any backend.
+func (m *Manager) trackOwnPendingOutputs(tx *wire.MsgTx) {
+	prevOutScripts := m.ownPrevOutScripts(tx)
+	if prevOutScripts == nil {
+		// not all inputs are ours, nothing reliable to compute
+		return
+	}
+
+	found, err := m.scanPendingTransaction(tx, prevOutScripts)
+	if err != nil {
+		logging.L.Err(err).Msg("failed to scan own transaction for change outputs")
+		return
+	}
+	m.addPendingUTXOs(found)
+}
+
+// OwnedOutputs returns the vouts of tx which pay to this wallet, change and self-sends alike.
+// Silent payment outputs never repeat the address, so they are found with the
+// BIP352 receiver check on the outputs. All inputs of tx must be wallet UTXOs.
+func (m *Manager) OwnedOutputs(tx *wire.MsgTx) (map[uint32]bool, error) {
+	prevOutScripts := m.ownPrevOutScripts(tx)
+	if prevOutScripts == nil {
+		return nil, errors.New("transaction spends coins which are not in the wallet")
+	}
+
+	found, err := m.scanPendingTransaction(tx, prevOutScripts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan transaction outputs: %w", err)
+	}
+
+	owned := make(map[uint32]bool, len(found))
+	for _, utxo := range found {
+		owned[utxo.Vout] = true
+	}
+	return owned, nil
+}
+
+// ownPrevOutScripts returns the scripts of the wallet UTXOs tx spends, in input order.
+// nil if any input is not a wallet UTXO.
+func (m *Manager) ownPrevOutScripts(tx *wire.MsgTx) [][]byte {
+	ownUTXOs := make(map[[36]byte]*wallet.OwnedUTXO)
+	for _, utxo := range m.GetUTXOs() {
+		ownUTXOs[utxo.SerialiseToOutpoint()] = utxo
+	}
+
+	prevOutScripts := make([][]byte, len(tx.TxIn))
+	for i, txIn := range tx.TxIn {
+		utxo := ownUTXOs[OutpointKey(txIn.PreviousOutPoint)]
+		if utxo == nil {
+			return nil
+		}
+		vin := wallet.ConvertOwnedUTXOIntoVin(utxo)
+		prevOutScripts[i] = vin.ScriptPubKey
+	}
+	return prevOutScripts
+}
+
+// addPendingUTXOs adds the UTXOs unless the wallet already knows the outpoint
+func (m *Manager) addPendingUTXOs(utxos []*wallet.OwnedUTXO) []*wallet.OwnedUTXO {
+	m.walletMu.Lock()
+	defer m.walletMu.Unlock()
+
+	known := make(map[[36]byte]struct{})
+	for _, utxo := range m.Wallet.GetUTXOs() {
+		known[utxo.SerialiseToOutpoint()] = struct{}{}
+	}
+
+	var added []*wallet.OwnedUTXO
+	for _, utxo := range utxos {
+		if _, ok := known[utxo.SerialiseToOutpoint()]; ok {
+			continue
+		}
+		added = append(added, utxo)
+		logging.L.Info().
+			Str("txid", fmt.Sprintf("%x", utxo.Txid)).
+			Uint32("vout", utxo.Vout).
+			Uint64("amount", utxo.Amount).
+			Msg("new unconfirmed UTXO discovered")
+	}
+	m.Wallet.AddUTXOs(added...)
+
+	return added
+}
+
+// confirmPendingUTXO moves a wallet UTXO from unconfirmed to the state the scanner found it in.
+// The scanner does not overwrite UTXOs the wallet already knows, hence the manual update.
+func (m *Manager) confirmPendingUTXO(confirmed *wallet.OwnedUTXO) {
+	m.walletMu.Lock()
+	defer m.walletMu.Unlock()
+
+	outpoint := confirmed.SerialiseToOutpoint()
+	for _, utxo := range m.Wallet.GetUTXOs() {
+		if utxo.State != wallet.StateUnconfirmed || utxo.SerialiseToOutpoint() != outpoint {
+			continue
+		}
+		utxo.State = confirmed.State
+		utxo.Height = confirmed.Height
+		utxo.Timestamp = confirmed.Timestamp
+		logging.L.Info().
+			Str("txid", fmt.Sprintf("%x", utxo.Txid)).
+			Uint32("vout", utxo.Vout).
+			Uint32("height", utxo.Height).
+			Msg("pending UTXO confirmed")
+		return
+	}
+}
+
+// scanPendingTransaction runs the BIP352 receiver check on a full transaction.
+// prevOutScripts must hold the scriptPubKey of the spent output for every input in order.
+func (m *Manager) scanPendingTransaction(
+	tx *wire.MsgTx, prevOutScripts [][]byte,
+) ([]*wallet.OwnedUTXO, error) {
+	if len(prevOutScripts) != len(tx.TxIn) {
+		return nil, errors.New("prevout scripts do not match inputs")
+	}
+
+	vins := make([]*bip352.Vin, len(tx.TxIn))
+	for i, txIn := range tx.TxIn {
+		vins[i] = &bip352.Vin{
+			Txid:         [32]byte(utils.ReverseBytesCopy(txIn.PreviousOutPoint.Hash[:])),
+			Vout:         txIn.PreviousOutPoint.Index,
+			ScriptPubKey: prevOutScripts[i],
+			ScriptSig:    txIn.SignatureScript,
+			Witness:      txIn.Witness,
+		}
+	}
+
+	eligibleVins, err := bip352.ExtractEligibleVins(vins)
+	if err != nil {
+		return nil, err
+	}
+	if len(eligibleVins) == 0 {
+		return nil, nil
+	}
+
+	pubKeys := make([][33]byte, 0, len(eligibleVins))
+	for _, vin := range eligibleVins {
+		pubKey, _ := bip352.ExtractPubKey(vin)
+		if vin.Taproot {
+			// x-only keys are always even
+			pubKey = append([]byte{0x02}, pubKey...)
+		}
+		pubKeys = append(pubKeys, utils.ConvertToFixedLength33(pubKey))
+	}
+
+	pubKeySum, err := bip352.SumPublicKeys(pubKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	// input hash commits to the smallest outpoint over all inputs, not only eligible ones
+	inputHash, err := bip352.ComputeInputHash(vins, pubKeySum)
+	if err != nil {
+		return nil, err
+	}
+
+	var txOutputs [][32]byte
+	for _, txOut := range tx.TxOut {
+		if bip352.IsP2TR(txOut.PkScript) {
+			txOutputs = append(txOutputs, [32]byte(txOut.PkScript[2:]))
+		}
+	}
+	if len(txOutputs) == 0 {
+		return nil, nil
+	}
+
+	// same labels as the scanner
+	labels := m.scanLabels()
+
+	foundOutputs, err := bip352.ReceiverScanTransaction(
+		[32]byte(m.Wallet.SecretKeyScan),
+		m.Wallet.PubKeySpend.ToArrayPtr(),
+		labels,
+		txOutputs,
+		pubKeySum,
+		inputHash,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	txid := GetTxID(tx)
+	now := uint64(time.Now().Unix())
+
+	var utxos []*wallet.OwnedUTXO
+	for _, found := range foundOutputs {
+		for vout, txOut := range tx.TxOut {
+			if !bip352.IsP2TR(txOut.PkScript) || !bytes.Equal(txOut.PkScript[2:], found.Output[:]) {
+				continue
+			}
+			utxos = append(utxos, &wallet.OwnedUTXO{
+				Txid:         txid,
+				Vout:         uint32(vout),
+				Amount:       uint64(txOut.Value),
+				PrivKeyTweak: found.SecKeyTweak,
+				PubKey:       found.Output,
+				Timestamp:    now,
+				State:        wallet.StateUnconfirmed,
+				Label:        found.Label,
+			})
+			break
+		}
+	}
+
+	return utxos, nil
+}
+
+// fetchTransactionElectrum loads the transaction and the outputs it spends from the Electrum server
+func (m *Manager) fetchTransactionElectrum(
+	ctx context.Context, txid string,
+) (*wire.MsgTx, [][]byte, error) {
+	if m.ElectrumAddress == "" {
+		return nil, nil, errors.New("electrum server address is not configured")
+	}
+
+	client, err := electrum.Dial(ctx, m.ElectrumAddress)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer client.Close()
+
+	getTx := func(txid string) (*wire.MsgTx, error) {
+		txHex, err := client.GetTransaction(ctx, txid)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch transaction %s: %w", txid, err)
+		}
+		return decodeTxHex(txHex)
+	}
+
+	tx, err := getTx(txid)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	prevOutScripts := make([][]byte, len(tx.TxIn))
+	for i, txIn := range tx.TxIn {
+		prevTx, err := getTx(txIn.PreviousOutPoint.Hash.String())
+		if err != nil {
+			return nil, nil, err
+		}
+		if int(txIn.PreviousOutPoint.Index) >= len(prevTx.TxOut) {
+			return nil, nil, fmt.Errorf("prevout %s not found", txIn.PreviousOutPoint)
+		}
+		prevOutScripts[i] = prevTx.TxOut[txIn.PreviousOutPoint.Index].PkScript
+	}
+
+	return tx, prevOutScripts, nil
+}
+
+// fetchTransactionMempoolSpace loads the transaction and its prevout scripts from the mempool.space API
+func fetchTransactionMempoolSpace(
+	ctx context.Context, txid string, network types.Network,
+) (*wire.MsgTx, [][]byte, error) {
+	if network == types.NetworkRegtest {
+		return nil, nil, fmt.Errorf("mempool.space does not support %s, use an electrum server", network)
+	}
+	baseURL := configs.GetMempoolSpaceURL(network) + "/api/tx/" + txid
+
+	txHex, err := httpGet(ctx, baseURL+"/hex")
+	if err != nil {
+		return nil, nil, err
+	}
+	tx, err := decodeTxHex(string(txHex))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	body, err := httpGet(ctx, baseURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	var txInfo struct {
+		Vin []struct {
+			Prevout struct {
+				ScriptPubKey string `json:"scriptpubkey"`
+			} `json:"prevout"`
+		} `json:"vin"`
+	}
+	if err = json.Unmarshal(body, &txInfo); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode transaction info: %w", err)
+	}
+	if len(txInfo.Vin) != len(tx.TxIn) {
+		return nil, nil, errors.New("transaction info does not match raw transaction")
+	}
+
+	prevOutScripts := make([][]byte, len(txInfo.Vin))
+	for i, vin := range txInfo.Vin {
+		prevOutScripts[i], err = hex.DecodeString(vin.Prevout.ScriptPubKey)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid prevout script: %w", err)
+		}
+	}
+
+	return tx, prevOutScripts, nil
+}
+
+func httpGet(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
+
+func decodeTxHex(txHex string) (*wire.MsgTx, error) {
+	raw, err := hex.DecodeString(strings.TrimSpace(txHex))
+	if err != nil {
+		return nil, fmt.Errorf("invalid transaction hex: %w", err)
+	}
+	tx := wire.NewMsgTx(wire.TxVersion)
+	if err = tx.Deserialize(bytes.NewReader(raw)); err != nil {
+		return nil, fmt.Errorf("failed to decode transaction: %w", err)
+	}
+	return tx, nil
+}
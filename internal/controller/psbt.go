@@ -0,0 +1,91 @@
+package controller
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/setavenger/blindbit-lib/wallet"
+)
+
+// BuildPSBT wraps a transaction from PrepareTransaction into a PSBT for use with other tools.
+// Every input gets its witness UTXO so amounts and fees can be verified.
+// Inputs that are already signed are exported as finalized.
+func (m *Manager) BuildPSBT(tx *wire.MsgTx) (*psbt.Packet, error) {
+	packet, _, witnesses, err := psbt.NewFromSignedTx(tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create psbt: %w", err)
+	}
+
+	ownUTXOs := make(map[[36]byte]*wallet.OwnedUTXO)
+	for _, utxo := range m.GetUTXOs() {
+		ownUTXOs[utxo.SerialiseToOutpoint()] = utxo
+	}
+
+	for i, txIn := range tx.TxIn {
+		utxo, ok := ownUTXOs[OutpointKey(txIn.PreviousOutPoint)]
+		if !ok {
+			return nil, fmt.Errorf("input %s is not owned by this wallet", txIn.PreviousOutPoint)
+		}
+
+		vin := wallet.ConvertOwnedUTXOIntoVin(utxo)
+		packet.Inputs[i].WitnessUtxo = wire.NewTxOut(int64(utxo.Amount), vin.ScriptPubKey)
+
+		if len(witnesses[i]) == 0 {
+			continue
+		}
+		var witness bytes.Buffer
+		if err = psbt.WriteTxWitness(&witness, witnesses[i]); err != nil {
+			return nil, fmt.Errorf("failed to serialise witness: %w", err)
+		}
+		packet.Inputs[i].FinalScriptWitness = witness.Bytes()
+	}
+
+	return packet, nil
+}
+
+// EncodePSBT returns the base64 encoding of BuildPSBT
+func (m *Manager) EncodePSBT(tx *wire.MsgTx) (string, error) {
+	packet, err := m.BuildPSBT(tx)
+	if err != nil {
+		return "", err
+	}
+	return packet.B64Encode()
+}
+
+// ParsePSBT reads a PSBT in binary or base64 form
+func ParsePSBT(data []byte) (*psbt.Packet, error) {
+	b64 := !bytes.HasPrefix(data, []byte("psbt\xff"))
+	if b64 {
+		data = bytes.TrimSpace(data)
+	}
+	packet, err := psbt.NewFromRawBytes(bytes.NewReader(data), b64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse psbt: %w", err)
+	}
+	return packet, nil
+}
+
+// FinalizePSBT finalizes a signed PSBT and extracts the transaction for broadcasting.
+// An unsigned export is not offered: spending a silent payment output needs the tweak of each input
+// (BIP 376), which BuildPSBT does not carry, so an external signer could not sign it.
+// Only PSBTs spending wallet UTXOs are accepted, so the spend can be recorded like one made here.
+func (m *Manager) FinalizePSBT(packet *psbt.Packet) (*wire.MsgTx, error) {
+	if m.ownPrevOutScripts(packet.UnsignedTx) == nil {
+		return nil, errors.New("psbt spends coins which are not in this wallet")
+	}
+
+	if err := psbt.MaybeFinalizeAll(packet); err != nil {
+		return nil, fmt.Errorf("failed to finalize psbt: %w", err)
+	}
+	tx, err := psbt.Extract(packet)
+	if errors.Is(err, psbt.ErrIncompletePSBT) {
+		return nil, errors.New("psbt is not fully signed")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract transaction: %w", err)
+	}
+	return tx, nil
+}
@@ -0,0 +1,95 @@
+package controller
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/setavenger/blindbit-desktop/internal/configs"
+)
+
+// tokenBucket lets through rate requests per second on average with bursts of up to burst requests
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	return &tokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// wait blocks until a request may be sent or ctx is done
+func (b *tokenBucket) wait(ctx context.Context) error {
+	for {
+		b.mu.Lock()
+		now := time.Now()
+		b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+		b.last = now
+		if b.tokens >= 1 {
+			b.tokens--
+			b.mu.Unlock()
+			return nil
+		}
+		delay := time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+		b.mu.Unlock()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// TipPollRate is the number of chain tip and oracle info requests per second the wallet sends to the oracle.
+// The scanner's block streams are not limited, they run inside the scanner library.
+// Without a configured rate a local oracle gets configs.DefaultLocalTipPollsPerSecond,
+// any other configs.DefaultTipPollsPerSecond.
+func (m *Manager) TipPollRate() float64 {
+	if m.TipPollsPerSecond > 0 {
+		return m.TipPollsPerSecond
+	}
+	if isLocalAddress(m.OracleAddress) {
+		return configs.DefaultLocalTipPollsPerSecond
+	}
+	return configs.DefaultTipPollsPerSecond
+}
+
+// waitForTipPoll holds a chain tip or oracle info request back until the rate limit allows it.
+// Requests are not limited before the oracle client exists.
+func (m *Manager) waitForTipPoll(ctx context.Context) error {
+	m.lifecycleMu.Lock()
+	limiter := m.tipPollLimiter
+	m.lifecycleMu.Unlock()
+
+	if limiter == nil {
+		return nil
+	}
+	return limiter.wait(ctx)
+}
+
+// isLocalAddress reports whether address, host or host:port, points at this computer
+func isLocalAddress(address string) bool {
+	host := address
+	if h, _, err := net.SplitHostPort(address); err == nil {
+		host = h
+	}
+	host = strings.Trim(host, "[]")
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
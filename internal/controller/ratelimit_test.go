@@ -0,0 +1,86 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTokenBucketBurst(t *testing.T) {
+	bucket := newTokenBucket(1, 5)
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		if err := bucket.wait(context.Background()); err != nil {
+			t.Fatalf("wait %d: %v", i, err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Fatalf("a full burst waited %s", elapsed)
+	}
+
+	// the burst is used up, the next request has to wait for a refill
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := bucket.wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the request after the burst to wait, got %v", err)
+	}
+}
+
+func TestTokenBucketRefillRate(t *testing.T) {
+	const rate = 50
+	bucket := newTokenBucket(rate, 1)
+
+	start := time.Now()
+	// the first request uses the initial token, the next five are refilled at rate
+	for i := 0; i < 6; i++ {
+		if err := bucket.wait(context.Background()); err != nil {
+			t.Fatalf("wait %d: %v", i, err)
+		}
+	}
+	elapsed := time.Since(start)
+	if want := 5 * time.Second / rate; elapsed < want-10*time.Millisecond {
+		t.Fatalf("6 requests took %s, expected at least %s at %d/s", elapsed, want, rate)
+	}
+	if elapsed > time.Second {
+		t.Fatalf("6 requests took %s at %d/s", elapsed, rate)
+	}
+}
+
+func TestTokenBucketCancel(t *testing.T) {
+	bucket := newTokenBucket(0.1, 1)
+	if err := bucket.wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- bucket.wait(ctx) }()
+	cancel()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context.Canceled, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("wait did not return after the context was cancelled")
+	}
+}
+
+func TestIsLocalAddress(t *testing.T) {
+	tests := map[string]bool{
+		"localhost:7000":          true,
+		"127.0.0.1:7000":          true,
+		"[::1]:7000":              true,
+		"192.168.1.10:7000":       false,
+		"oracle.setor.dev:443":    false,
+		"signet.oracle.setor.dev": false,
+	}
+	for address, want := range tests {
+		if got := isLocalAddress(address); got != want {
+			t.Errorf("isLocalAddress(%q) = %v, want %v", address, got, want)
+		}
+	}
+}
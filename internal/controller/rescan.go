@@ -0,0 +1,178 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/setavenger/blindbit-lib/logging"
+)
+
+var (
+	// ErrRescanRunning is returned when a rescan or range rescan is started while another one runs
+	ErrRescanRunning = errors.New("a rescan is already running")
+	// ErrNoRescan is returned when there is no running rescan to pause
+	ErrNoRescan = errors.New("no rescan is running")
+)
+
+// PausedRescan is a rescan stopped with PauseRescan.
+// Everything up to Height was scanned, a resume continues after it.
+type PausedRescan struct {
+	From   uint32 `json:"from"`
+	To     uint32 `json:"to"`
+	Height uint32 `json:"height"`
+}
+
+// rescanWindow follows a running rescan. height comes from the scanner's progress updates,
+// the wallet's scan height still shows the previous scan until the first one arrives.
+type rescanWindow struct {
+	mu       sync.Mutex
+	active   bool
+	from, to uint32
+	height   uint32
+	// scan height before the rescan, the watcher goes back to it when the rescan is paused
+	watchFrom uint64
+	// set by PauseRescan until the cancelled scan has returned
+	paused bool
+	cancel context.CancelFunc
+	// lets the watcher continue once the rescan ended, see holdWatcher
+	releaseWatcher func()
+	// stops a running RescanRange, which is not tracked like the rescan above
+	rangeCancel context.CancelFunc
+}
+
+// observe records the height of a progress update.
+// Returns false for updates of a paused rescan, they must not move the scan height.
+func (r *rescanWindow) observe(height uint32) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.paused {
+		return false
+	}
+	if r.active {
+		r.height = height
+	}
+	return true
+}
+
+// BeginRescan marks a rescan of from to to as running, SyncProgress reports its progress until EndRescan.
+// The scan has to run under the returned context so PauseRescan can stop it.
+// The watcher is stopped first and stays stopped until EndRescan, the scanner skips a scan while it is busy.
+// A previously paused rescan is dropped. While offline the context is cancelled right away.
+func (m *Manager) BeginRescan(from, to uint32) (context.Context, error) {
+	release, err := m.holdWatcher()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(m.Context())
+	watchFrom := m.ScanHeight()
+
+	m.rescan.mu.Lock()
+	if m.rescan.active || m.rescan.rangeCancel != nil {
+		m.rescan.mu.Unlock()
+		cancel()
+		release()
+		return nil, ErrRescanRunning
+	}
+	m.rescan.active, m.rescan.from, m.rescan.to, m.rescan.height = true, from, to, from
+	m.rescan.watchFrom, m.rescan.paused, m.rescan.cancel = watchFrom, false, cancel
+	m.rescan.releaseWatcher = release
+	if m.IsOffline() {
+		// went offline after the caller checked, stopRescans missed this one.
+		// The scan returns right away and a paused rescan is kept for a resume.
+		m.rescan.paused = true
+		cancel()
+	} else {
+		m.PausedRescan = nil
+	}
+	m.rescan.mu.Unlock()
+	return ctx, nil
+}
+
+// EndRescan marks the running rescan as finished, call it once the scan returned.
+// For a paused rescan the scan height goes back to where it was before the rescan,
+// so the watcher keeps following the tip instead of continuing the rescan. Returns whether it was paused.
+// The watcher continues from the scan height.
+func (m *Manager) EndRescan() bool {
+	m.rescan.mu.Lock()
+	release := m.rescan.releaseWatcher
+	m.rescan.active, m.rescan.releaseWatcher = false, nil
+	if m.rescan.cancel != nil {
+		m.rescan.cancel()
+		m.rescan.cancel = nil
+	}
+	paused := m.rescan.paused
+	if paused {
+		m.rescan.paused = false
+		m.SetScanHeight(m.rescan.watchFrom)
+		logging.L.Info().
+			Uint32("paused_at", m.rescan.height).
+			Uint64("scan_height", m.rescan.watchFrom).
+			Msg("rescan paused")
+	}
+	m.rescan.mu.Unlock()
+
+	// outside rescan.mu, restarting takes lifecycleMu and may wait for the stopped watcher
+	if release != nil {
+		release()
+	}
+	return paused
+}
+
+// PauseRescan stops the running rescan and keeps its progress in PausedRescan
+func (m *Manager) PauseRescan() error {
+	m.rescan.mu.Lock()
+	defer m.rescan.mu.Unlock()
+
+	if !m.rescan.active || m.rescan.paused {
+		return ErrNoRescan
+	}
+	m.rescan.paused = true
+	m.PausedRescan = &PausedRescan{
+		From:   m.rescan.from,
+		To:     m.rescan.to,
+		Height: m.rescan.height,
+	}
+	m.rescan.cancel()
+	return nil
+}
+
+// GetPausedRescan returns a copy of the paused rescan, nil if there is none
+func (m *Manager) GetPausedRescan() *PausedRescan {
+	m.rescan.mu.Lock()
+	defer m.rescan.mu.Unlock()
+	if m.PausedRescan == nil {
+		return nil
+	}
+	paused := *m.PausedRescan
+	return &paused
+}
+
+// RescanRunning reports whether a rescan is in progress and not being paused
+func (m *Manager) RescanRunning() bool {
+	m.rescan.mu.Lock()
+	defer m.rescan.mu.Unlock()
+	return m.rescan.active && !m.rescan.paused
+}
+
+// rescanActive reports whether a rescan or range rescan holds the scanner, paused or not
+func (m *Manager) rescanActive() bool {
+	m.rescan.mu.Lock()
+	defer m.rescan.mu.Unlock()
+	return m.rescan.active || m.rescan.rangeCancel != nil
+}
+
+// stopRescans pauses the running rescan and cancels a running range rescan,
+// neither may query the oracle once the wallet went offline
+func (m *Manager) stopRescans() {
+	if err := m.PauseRescan(); err == nil {
+		logging.L.Info().Msg("offline mode on, rescan paused")
+	}
+
+	m.rescan.mu.Lock()
+	defer m.rescan.mu.Unlock()
+	if m.rescan.rangeCancel != nil {
+		m.rescan.rangeCancel()
+		logging.L.Info().Msg("offline mode on, range rescan stopped")
+	}
+}
@@ -0,0 +1,72 @@
+package controller
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSetOfflinePausesRescan(t *testing.T) {
+	m := newTestManager()
+	ctx, err := m.BeginRescan(100, 200)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.rescan.observe(150)
+
+	m.SetOffline(true)
+
+	if ctx.Err() == nil {
+		t.Fatal("going offline did not cancel the rescan")
+	}
+	if m.RescanRunning() {
+		t.Fatal("rescan still running while offline")
+	}
+	if paused := m.GetPausedRescan(); paused == nil || paused.Height != 150 {
+		t.Fatalf("expected the rescan to be paused at 150, got %+v", paused)
+	}
+	if !m.EndRescan() {
+		t.Fatal("EndRescan did not report the pause")
+	}
+
+	// a resume while offline is stopped before it queries the oracle and keeps the progress
+	ctx, err = m.BeginRescan(151, 200)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ctx.Err() == nil {
+		t.Fatal("a rescan started while offline")
+	}
+	m.EndRescan()
+	if paused := m.GetPausedRescan(); paused == nil || paused.Height != 150 {
+		t.Fatalf("the paused rescan was dropped, got %+v", paused)
+	}
+}
+
+func TestBeginRescanRefusesSecondRescan(t *testing.T) {
+	m := newTestManager()
+	if _, err := m.BeginRescan(100, 200); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.BeginRescan(300, 400); !errors.Is(err, ErrRescanRunning) {
+		t.Fatalf("second rescan: got %v, want ErrRescanRunning", err)
+	}
+
+	m.EndRescan()
+	m.lifecycleMu.Lock()
+	holds := m.watchHolds
+	m.lifecycleMu.Unlock()
+	if holds != 0 {
+		t.Fatalf("watcher still held %d times after the rescan ended", holds)
+	}
+}
+
+func TestBeginRescanRefusedDuringRangeRescan(t *testing.T) {
+	m := newTestManager()
+	m.rescan.rangeCancel = func() {}
+	if _, err := m.BeginRescan(100, 200); !errors.Is(err, ErrRescanRunning) {
+		t.Fatalf("got %v, want ErrRescanRunning", err)
+	}
+	if m.RescanRunning() {
+		t.Fatal("refused rescan marked as running")
+	}
+}
@@ -0,0 +1,155 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/setavenger/blindbit-lib/logging"
+	"github.com/setavenger/blindbit-lib/wallet"
+	"github.com/setavenger/go-bip352"
+)
+
+// ScanExport is the wallet state of a blindbit-scan daemon.
+// Its UTXO format is the one of blindbit-lib, hex txid, tweak and output key.
+type ScanExport struct {
+	UTXOs []*wallet.OwnedUTXO
+	// Height the daemon has scanned up to, 0 if the export does not say
+	Height uint64
+}
+
+// scanExportFile is the object form of an export, e.g. a saved blindbit-scan wallet
+type scanExportFile struct {
+	UTXOs          []*wallet.OwnedUTXO `json:"utxos"`
+	Height         uint64              `json:"height"`
+	LastScanHeight uint64              `json:"last_scan_height"`
+}
+
+// ParseScanExport reads a blindbit-scan export. Accepted are the plain UTXO list
+// as returned by its /utxos endpoint and an object with "utxos" and the scan height.
+func ParseScanExport(data []byte) (*ScanExport, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, errors.New("export is empty")
+	}
+
+	if data[0] == '[' {
+		var utxos []*wallet.OwnedUTXO
+		if err := json.Unmarshal(data, &utxos); err != nil {
+			return nil, fmt.Errorf("failed to parse UTXO list: %w", err)
+		}
+		return &ScanExport{UTXOs: utxos}, nil
+	}
+
+	var file scanExportFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse export: %w", err)
+	}
+	height := file.LastScanHeight
+	if height == 0 {
+		height = file.Height
+	}
+	return &ScanExport{UTXOs: file.UTXOs, Height: height}, nil
+}
+
+// ScanImportResult summarises ImportScanExport
+type ScanImportResult struct {
+	Added   int
+	Skipped int
+	// Height is the scan height after the import
+	Height uint64
+}
+
+// ImportScanExport adds the UTXOs of a blindbit-scan export and moves the scan height to height,
+// so the wallet does not have to rescan what the daemon already scanned.
+// Every UTXO has to belong to the wallet's spend key, otherwise nothing is imported.
+// Known outpoints are skipped, a height below the current scan height is ignored.
+// A height above the chain tip is rejected, as is an import while a rescan runs.
+func (m *Manager) ImportScanExport(export *ScanExport, height uint64) (*ScanImportResult, error) {
+	if len(export.UTXOs) == 0 && height == 0 {
+		return nil, errors.New("export contains neither UTXOs nor a scan height")
+	}
+	if m.rescanActive() {
+		return nil, ErrRescanRunning
+	}
+	if height > 0 {
+		tip, err := m.GetCurrentHeight()
+		if err != nil {
+			return nil, fmt.Errorf("failed to check the scan height against the chain tip: %w", err)
+		}
+		if height > uint64(tip) {
+			return nil, fmt.Errorf("scan height %d is above the chain tip %d", height, tip)
+		}
+	}
+	for _, utxo := range export.UTXOs {
+		if err := m.checkOwnedOutput(utxo); err != nil {
+			return nil, err
+		}
+	}
+
+	if height > m.ScanHeight() {
+		// the watcher would overwrite the scan height, it restarts from the imported one once released
+		release, err := m.holdWatcher()
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+	}
+
+	m.walletMu.Lock()
+	known := make(map[[36]byte]struct{})
+	for _, utxo := range m.Wallet.GetUTXOs() {
+		known[utxo.SerialiseToOutpoint()] = struct{}{}
+	}
+	var added []*wallet.OwnedUTXO
+	for _, utxo := range export.UTXOs {
+		outpoint := utxo.SerialiseToOutpoint()
+		if _, ok := known[outpoint]; ok {
+			continue
+		}
+		known[outpoint] = struct{}{}
+		added = append(added, utxo)
+	}
+	m.Wallet.AddUTXOs(added...)
+	m.walletMu.Unlock()
+
+	for _, utxo := range added {
+		if err := m.addOutUtxoToHistory(utxo); err != nil {
+			logging.L.Err(err).Msg("failed to add imported UTXO to transaction history")
+		}
+	}
+
+	if height > m.ScanHeight() {
+		m.SetScanHeight(height)
+	}
+
+	result := &ScanImportResult{
+		Added:   len(added),
+		Skipped: len(export.UTXOs) - len(added),
+		Height:  m.ScanHeight(),
+	}
+	logging.L.Info().
+		Int("added", result.Added).
+		Int("skipped", result.Skipped).
+		Uint64("scan_height", result.Height).
+		Msg("imported blindbit-scan export")
+	return result, nil
+}
+
+// checkOwnedOutput verifies that spend key plus the UTXO's tweak gives its output key,
+// i.e. the wallet can spend it
+func (m *Manager) checkOwnedOutput(utxo *wallet.OwnedUTXO) error {
+	tweakPub := bip352.PubKeyFromSecKey(&utxo.PrivKeyTweak)
+	output, err := bip352.AddPublicKeys(m.Wallet.PubKeySpend.ToArrayPtr(), tweakPub)
+	if err != nil {
+		return fmt.Errorf("invalid tweak for %x:%d: %w", utxo.Txid, utxo.Vout, err)
+	}
+	if !bytes.Equal(output[1:], utxo.PubKey[:]) {
+		return fmt.Errorf(
+			"UTXO %x:%d does not belong to this wallet, the export was made with different keys",
+			utxo.Txid, utxo.Vout,
+		)
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package controller
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestImportScanExportHeight(t *testing.T) {
+	m := newTestManager()
+	m.Offline = true
+	m.tip = 500
+
+	if _, err := m.ImportScanExport(&ScanExport{}, 501); err == nil {
+		t.Fatal("expected a height above the chain tip to be rejected")
+	}
+	if got := m.ScanHeight(); got != 10 {
+		t.Fatalf("scan height moved to %d by a rejected import", got)
+	}
+
+	result, err := m.ImportScanExport(&ScanExport{}, 400)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Height != 400 || m.ScanHeight() != 400 {
+		t.Fatalf("scan height %d, result %d, want 400", m.ScanHeight(), result.Height)
+	}
+	if m.watchHolds != 0 {
+		t.Fatalf("watcher still held %d times after the import", m.watchHolds)
+	}
+}
+
+func TestImportScanExportRefusedDuringRescan(t *testing.T) {
+	m := newTestManager()
+	m.Offline = true
+	m.tip = 500
+	m.rescan.active = true
+
+	if _, err := m.ImportScanExport(&ScanExport{}, 400); !errors.Is(err, ErrRescanRunning) {
+		t.Fatalf("got %v, want ErrRescanRunning", err)
+	}
+}
@@ -0,0 +1,89 @@
+package controller
+
+import (
+	"sync"
+	"time"
+)
+
+// scanRateWindow is how far back progress samples are kept for the rate estimate
+const scanRateWindow = 60 * time.Second
+
+type scanSample struct {
+	at     time.Time
+	height uint32
+}
+
+// scanRate tracks a rolling blocks per second rate from scan progress updates
+type scanRate struct {
+	mu      sync.Mutex
+	samples []scanSample
+}
+
+// observe records a progress update. A height below the last sample means
+// a new (re)scan started and the estimate starts over.
+func (r *scanRate) observe(height uint32) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	if n := len(r.samples); n > 0 && height < r.samples[n-1].height {
+		r.samples = nil
+	}
+	r.samples = append(r.samples, scanSample{at: now, height: height})
+
+	// drop samples outside the window but always keep two to have a rate
+	cutoff := now.Add(-scanRateWindow)
+	drop := 0
+	for drop < len(r.samples)-2 && r.samples[drop].at.Before(cutoff) {
+		drop++
+	}
+	r.samples = r.samples[drop:]
+}
+
+func (r *scanRate) reset() {
+	r.mu.Lock()
+	r.samples = nil
+	r.mu.Unlock()
+}
+
+// blocksPerSecond returns 0 until there are enough samples for an estimate
+func (r *scanRate) blocksPerSecond() float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.samples) < 2 {
+		return 0
+	}
+	first, last := r.samples[0], r.samples[len(r.samples)-1]
+	elapsed := last.at.Sub(first.at).Seconds()
+	if elapsed <= 0 || last.height <= first.height {
+		return 0
+	}
+	return float64(last.height-first.height) / elapsed
+}
+
+// ResetScanRate drops the rate estimate, called when a rescan starts
+func (m *Manager) ResetScanRate() {
+	m.scanRate.reset()
+}
+
+// ScanETA estimates the time left until the scan reaches tip.
+// Returns false if there is no rate estimate yet, the scan is not progressing
+// or the tip is not known yet (0). A wallet which is caught up returns 0 and true.
+func (m *Manager) ScanETA(tip uint32) (time.Duration, bool) {
+	if tip == 0 {
+		return 0, false
+	}
+	scanned := m.ScanHeight()
+	if scanned >= uint64(tip) {
+		return 0, true
+	}
+
+	rate := m.scanRate.blocksPerSecond()
+	if rate <= 0 {
+		return 0, false
+	}
+
+	remaining := float64(uint64(tip) - scanned)
+	return time.Duration(remaining / rate * float64(time.Second)), true
+}
@@ -0,0 +1,52 @@
+package controller
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/setavenger/blindbit-lib/logging"
+)
+
+// scanSummaryEvery is the number of blocks between info level scan summaries.
+// Per block details stay at debug level so a long rescan does not flood the log.
+const scanSummaryEvery = 1000
+
+// scanSummary logs the scan rate and matches found every scanSummaryEvery blocks.
+// observe is called by the progress handler only, match by the UTXO handler.
+type scanSummary struct {
+	from    uint32
+	start   time.Time
+	matches atomic.Uint64
+}
+
+func (s *scanSummary) match() {
+	s.matches.Add(1)
+}
+
+func (s *scanSummary) observe(height uint32) {
+	now := time.Now()
+	// first update or a rescan from a lower height starts a new summary
+	if s.start.IsZero() || height < s.from {
+		s.from, s.start = height, now
+		return
+	}
+
+	blocks := height - s.from
+	if blocks < scanSummaryEvery {
+		return
+	}
+
+	rate := 0.0
+	if elapsed := now.Sub(s.start).Seconds(); elapsed > 0 {
+		rate = float64(blocks) / elapsed
+	}
+	logging.L.Info().
+		Uint32("height", height).
+		Uint32("blocks", blocks).
+		Str("blocks_per_sec", fmt.Sprintf("%.1f", rate)).
+		Uint64("matches", s.matches.Swap(0)).
+		Msg("scan progress")
+
+	s.from, s.start = height, now
+}
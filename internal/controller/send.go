@@ -3,41 +3,286 @@ package controller
 import (
 	"bytes"
 	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/btcsuite/btcd/wire"
+	"github.com/setavenger/blindbit-desktop/internal/electrum"
 	"github.com/setavenger/blindbit-lib/logging"
 	"github.com/setavenger/blindbit-lib/types"
-	"github.com/setavenger/blindbit-lib/utils"
 	"github.com/setavenger/blindbit-lib/wallet"
 )
 
+// PrepareTransaction builds and signs a transaction paying recipients.
+// Inputs are chosen according to m.CoinSelectionStrategy from UTXOs with at least m.MinConfirmations.
+// Returns the strategy that produced the inputs, branch-and-bound may fall back to largest-first.
 func (m *Manager) PrepareTransaction(
 	ctx context.Context,
 	recipients []wallet.Recipient,
 	feeRate uint32,
+) (
+	*wallet.TxMetadata, CoinSelectionStrategy, error,
+) {
+	utxos, strategy := orderUTXOs(
+		m.spendableUTXOs(),
+		m.CoinSelectionStrategy,
+		recipients,
+		feeRate,
+		m.MinChangeAmount,
+	)
+
+	txMetadata, err := m.prepareTransaction(recipients, utxos, feeRate)
+	if errors.Is(err, wallet.ErrInsufficientFunds) && strategy != CoinSelectionLargestFirst {
+		// the selected subset or order did not cover the builder's fee, largest-first spends what is needed
+		logging.L.Debug().Str("strategy", string(strategy)).Msg("coin selection fell short, retrying largest-first")
+		utxos, strategy = orderUTXOs(m.spendableUTXOs(), CoinSelectionLargestFirst, recipients, feeRate, m.MinChangeAmount)
+		txMetadata, err = m.prepareTransaction(recipients, utxos, feeRate)
+	}
+	if errors.Is(err, wallet.ErrInsufficientFunds) {
+		return nil, "", m.insufficientFunds(recipients, feeRate)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	logging.L.Debug().
+		Str("strategy", string(strategy)).
+		Int("inputs", len(txMetadata.Tx.TxIn)).
+		Msg("prepared transaction")
+
+	return txMetadata, strategy, nil
+}
+
+// prepareTransaction builds the transaction from utxos, which are taken in the given order
+func (m *Manager) prepareTransaction(
+	recipients []wallet.Recipient,
+	utxos []*wallet.OwnedUTXO,
+	feeRate uint32,
 ) (
 	*wallet.TxMetadata, error,
 ) {
-	txMetadata, err := m.Wallet.SendToRecipients(
+	return m.Wallet.SendToRecipients(
 		recipients,
-		m.Wallet.GetUTXOs(),
+		utxos,
 		int64(feeRate),
 		m.MinChangeAmount, // Minimum change amount
 		false,             // Don't mark here! Wait until after successful broadcast
 		false,             // Don't use unconfirmed spent todo: make optional in UI
 	)
+}
+
+// ErrAlreadyBroadcast is returned when a transaction is recorded which is already in the history
+var ErrAlreadyBroadcast = errors.New("transaction already exists in history")
+
+// BroadcastRejectedError is returned when the backend was reached but refused the transaction.
+// Reason holds the node's message, e.g. "min relay fee not met" or "missing inputs".
+type BroadcastRejectedError struct {
+	Backend BroadcastBackend
+	Reason  string
+}
+
+func (e *BroadcastRejectedError) Error() string {
+	return fmt.Sprintf("transaction rejected by %s: %s", e.Backend, e.Reason)
+}
+
+// ErrBroadcastOutcomeUnknown is returned when the backend could not be reached or did not answer in time.
+// The transaction may have been sent, retrying the same transaction is safe.
+var ErrBroadcastOutcomeUnknown = errors.New("broadcast outcome unknown, the transaction may have been sent")
+
+// PendingBroadcast is a transaction handed to the broadcast backend without a known outcome.
+// Its inputs are left out of coin selection until the outcome is known.
+type PendingBroadcast struct {
+	TxID      string `json:"txid"`
+	TxHex     string `json:"tx_hex"`
+	Timestamp int64  `json:"timestamp"`
+	// Recipients are the outputs the transaction was built for, change included,
+	// so a retry records the spend like the first attempt would have
+	Recipients []*wallet.RecipientImpl `json:"recipients,omitempty"`
+}
+
+// TxMetadata returns the transaction with its recipients for a retry and the recipients without change
+func (p *PendingBroadcast) TxMetadata() (*wallet.TxMetadata, []wallet.Recipient, error) {
+	tx, err := decodeTxHex(p.TxHex)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	txMetadata := &wallet.TxMetadata{Tx: tx}
+	var recipients []wallet.Recipient
+	for _, recipient := range p.Recipients {
+		txMetadata.AllRecipients = append(txMetadata.AllRecipients, recipient)
+		if recipient.Change {
+			txMetadata.ChangeRecipient = recipient
+			continue
+		}
+		recipients = append(recipients, recipient)
 	}
+	return txMetadata, recipients, nil
+}
 
-	return txMetadata, nil
+// alreadyKnownReasons are node messages for a transaction which is already in the mempool or a block
+var alreadyKnownReasons = []string{
+	"txn-already-in-mempool",
+	"txn-already-known",
+	"already in block chain",
+	"outputs already in utxo set",
+}
+
+// alreadyKnown reports whether a rejection only says that the node already has the transaction
+func alreadyKnown(reason string) bool {
+	reason = strings.ToLower(reason)
+	for _, known := range alreadyKnownReasons {
+		if strings.Contains(reason, known) {
+			return true
+		}
+	}
+	return false
 }
 
-// BroadcastTransaction broadcasts a transaction to mempool.space
-func (m *Manager) BroadcastTransaction(txHex string, network types.Network) error {
+// BroadcastTransaction broadcasts a transaction via the configured BroadcastBackend and returns its txid.
+// Broadcasting is safe to retry: a transaction the node already has counts as broadcast.
+// The transaction is kept in PendingBroadcasts and saved before the request,
+// a backend which can't be reached or times out leaves it there and returns ErrBroadcastOutcomeUnknown.
+// A refusal by the node is returned as *BroadcastRejectedError.
+// recipients are kept with the pending entry for a retry, nil for transactions not built by the wallet.
+func (m *Manager) BroadcastTransaction(
+	txHex string,
+	network types.Network,
+	recipients []wallet.Recipient,
+) (string, error) {
+	if m.IsOffline() {
+		return "", ErrOffline
+	}
+	tx, err := decodeTxHex(txHex)
+	if err != nil {
+		return "", err
+	}
+	txid := GetTxID(tx)
+	txidHex := hex.EncodeToString(txid[:])
+
+	m.addPendingBroadcast(txidHex, txHex, recipients)
+	m.persistHistory()
+
+	broadcastTxID, err := m.broadcast(txHex, network)
+	var rejected *BroadcastRejectedError
+	switch {
+	case errors.As(err, &rejected) && alreadyKnown(rejected.Reason):
+		logging.L.Info().
+			Str("txid", txidHex).
+			Str("reason", rejected.Reason).
+			Msg("backend already has the transaction, treating broadcast as successful")
+		broadcastTxID, err = txidHex, nil
+	case err != nil && !errors.As(err, &rejected):
+		return "", fmt.Errorf("%w: %w", ErrBroadcastOutcomeUnknown, err)
+	}
+
+	m.clearPendingBroadcast(txidHex)
+	if err != nil {
+		// rejected, the pending entry must not come back after a restart
+		m.persistHistory()
+	}
+	return broadcastTxID, err
+}
+
+// addPendingBroadcast keeps txid until the backend's answer is known, a retry replaces the entry.
+// A retry without recipients keeps those of the previous entry.
+func (m *Manager) addPendingBroadcast(txid, txHex string, recipients []wallet.Recipient) {
+	pending := &PendingBroadcast{
+		TxID:      txid,
+		TxHex:     txHex,
+		Timestamp: time.Now().Unix(),
+	}
+	for _, recipient := range recipients {
+		pending.Recipients = append(pending.Recipients, &wallet.RecipientImpl{
+			Address:  recipient.GetAddress(),
+			Amount:   recipient.GetAmount(),
+			PkScript: recipient.GetPkScript(),
+			Change:   recipient.IsChange(),
+		})
+	}
+
+	m.historyMu.Lock()
+	defer m.historyMu.Unlock()
+
+	for _, previous := range m.PendingBroadcasts {
+		if previous.TxID == txid && pending.Recipients == nil {
+			pending.Recipients = previous.Recipients
+		}
+	}
+	m.removePendingBroadcast(txid)
+	m.PendingBroadcasts = append(m.PendingBroadcasts, pending)
+}
+
+// clearPendingBroadcast drops the pending entry for txid once the backend accepted or refused it
+func (m *Manager) clearPendingBroadcast(txid string) {
+	m.historyMu.Lock()
+	defer m.historyMu.Unlock()
+	m.removePendingBroadcast(txid)
+}
+
+// removePendingBroadcast drops the entry for txid, the caller holds historyMu
+func (m *Manager) removePendingBroadcast(txid string) {
+	kept := m.PendingBroadcasts[:0]
+	for _, pending := range m.PendingBroadcasts {
+		if pending.TxID != txid {
+			kept = append(kept, pending)
+		}
+	}
+	m.PendingBroadcasts = kept
+}
+
+// GetPendingBroadcasts returns a copy of the broadcasts without a known outcome, oldest first
+func (m *Manager) GetPendingBroadcasts() []*PendingBroadcast {
+	m.historyMu.RLock()
+	defer m.historyMu.RUnlock()
+	return slices.Clone(m.PendingBroadcasts)
+}
+
+// pendingBroadcastInputs returns the outpoints spent by pending broadcasts,
+// they may be spent already and must not be selected again
+func (m *Manager) pendingBroadcastInputs() map[[36]byte]bool {
+	m.historyMu.RLock()
+	defer m.historyMu.RUnlock()
+
+	inputs := make(map[[36]byte]bool)
+	for _, pending := range m.PendingBroadcasts {
+		tx, err := decodeTxHex(pending.TxHex)
+		if err != nil {
+			logging.L.Warn().Err(err).Str("txid", pending.TxID).Msg("failed to decode pending broadcast")
+			continue
+		}
+		for _, txIn := range tx.TxIn {
+			inputs[OutpointKey(txIn.PreviousOutPoint)] = true
+		}
+	}
+	return inputs
+}
+
+// broadcast pushes txHex to the configured BroadcastBackend
+func (m *Manager) broadcast(txHex string, network types.Network) (string, error) {
+	switch m.BroadcastBackend {
+	case BroadcastBackendElectrum:
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		txid, err := m.BroadcastViaElectrum(ctx, txHex)
+		var rpcErr *electrum.RPCError
+		if errors.As(err, &rpcErr) {
+			return "", &BroadcastRejectedError{Backend: BroadcastBackendElectrum, Reason: rpcErr.Message}
+		}
+		return txid, err
+	default:
+		return broadcastViaMempoolSpace(txHex, network)
+	}
+}
+
+// broadcastViaMempoolSpace broadcasts a transaction to mempool.space
+func broadcastViaMempoolSpace(txHex string, network types.Network) (string, error) {
 	var url string
 	switch network {
 	case types.NetworkMainnet:
@@ -47,20 +292,60 @@ func (m *Manager) BroadcastTransaction(txHex string, network types.Network) erro
 	case types.NetworkSignet:
 		url = "https://mempool.space/signet/api/tx"
 	default:
-		return fmt.Errorf("unsupported network: %v", network)
+		return "", fmt.Errorf("unsupported network: %v", network)
 	}
 
-	resp, err := http.Post(url, "text/plain", strings.NewReader(txHex))
+	return postMempoolSpaceTx(url, txHex)
+}
+
+// mempoolSpaceTimeout bounds a broadcast request, no answer in time is an unknown outcome
+const mempoolSpaceTimeout = 30 * time.Second
+
+// postMempoolSpaceTx posts txHex to a mempool.space tx endpoint.
+// Only a 400 carrying the node's reject reason is a *BroadcastRejectedError.
+// Any other failure, e.g. 429, 5xx or no connection, says nothing about the transaction
+// and is returned as a plain error.
+func postMempoolSpaceTx(url, txHex string) (string, error) {
+	client := &http.Client{Timeout: mempoolSpaceTimeout}
+	resp, err := client.Post(url, "text/plain", strings.NewReader(txHex))
 	if err != nil {
-		return fmt.Errorf("failed to broadcast transaction: %w", err)
+		return "", fmt.Errorf("failed to broadcast transaction: %w", err)
 	}
 	defer resp.Body.Close()
 
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read broadcast response: %w", err)
+	}
+
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("broadcast failed with status: %d", resp.StatusCode)
+		if reason := parseMempoolSpaceRejection(body); resp.StatusCode == http.StatusBadRequest && reason != "" {
+			return "", &BroadcastRejectedError{Backend: BroadcastBackendMempoolSpace, Reason: reason}
+		}
+		return "", fmt.Errorf(
+			"mempool.space answered with status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)),
+		)
 	}
 
-	return nil
+	return strings.TrimSpace(string(body)), nil
+}
+
+// parseMempoolSpaceRejection extracts the node message from a mempool.space error body,
+// empty if the body does not carry one.
+// The body usually looks like: sendrawtransaction RPC error: {"code":-26,"message":"..."}
+func parseMempoolSpaceRejection(body []byte) string {
+	text := strings.TrimSpace(string(body))
+
+	if idx := strings.Index(text, "{"); idx >= 0 {
+		var rpcErr struct {
+			Message string `json:"message"`
+		}
+		if err := json.Unmarshal([]byte(text[idx:]), &rpcErr); err == nil && rpcErr.Message != "" {
+			return rpcErr.Message
+		}
+	}
+
+	return ""
 }
 
 // RecordSentTransaction records a sent transaction to history with proper net amount calculation
@@ -75,19 +360,22 @@ func (m *Manager) RecordSentTransaction(
 	// Get the transaction ID to check for duplicates
 	txID := GetTxID(txMetadata.Tx)
 
+	m.historyMu.Lock()
+
 	// Check if transaction already exists in history (prevent duplicates at struct level)
 	for _, existingTx := range m.TransactionHistory {
 		if bytes.Equal(existingTx.TxID[:], txID[:]) {
+			m.historyMu.Unlock()
 			logging.L.Warn().
 				Str("txid", fmt.Sprintf("%x", txID)).
 				Msg("transaction already exists in history, skipping duplicate")
-			return fmt.Errorf("transaction already exists in history")
+			return ErrAlreadyBroadcast
 		}
 	}
 
-	// Use the TxItemFromTxMetadata function from blindbit-lib
-	txItem, err := wallet.TxItemFromTxMetadata(m.Wallet, txMetadata)
+	txItem, err := m.sentTxItem(txMetadata)
 	if err != nil {
+		m.historyMu.Unlock()
 		logging.L.Err(err).Msg("failed to map tx to tx history item")
 		return err
 	}
@@ -95,21 +383,138 @@ func (m *Manager) RecordSentTransaction(
 	// Add the transaction to history
 	m.TransactionHistory = append(m.TransactionHistory, txItem)
 	m.TransactionHistory.Sort()
+	m.historyMu.Unlock()
 
 	// Mark UTXOs as spent
 	m.markUTXOsAsSpent(txMetadata.Tx)
 
+	// change comes back as a pending receive until it confirms
+	m.trackOwnPendingOutputs(txMetadata.Tx)
+
+	m.clearFailedBroadcast(hex.EncodeToString(txItem.TxID[:]))
+	m.clearPendingBroadcast(hex.EncodeToString(txItem.TxID[:]))
+
+	m.persistHistory()
+
+	return nil
+}
+
+// sentTxItem maps a transaction spending wallet coins to a history item.
+// Outputs are marked as own by the BIP352 receiver check, not by recipient address,
+// so change and self-sends to labeled addresses don't count as sent.
+// The fee then follows from the spent inputs minus all outputs.
+func (m *Manager) sentTxItem(txMetadata *wallet.TxMetadata) (*wallet.TxItem, error) {
+	owned, err := m.OwnedOutputs(txMetadata.Tx)
+	if err != nil {
+		logging.L.Warn().Err(err).Msg("failed to find own outputs, falling back to recipient addresses")
+		m.walletMu.RLock()
+		defer m.walletMu.RUnlock()
+		return wallet.TxItemFromTxMetadata(m.Wallet, txMetadata)
+	}
+
+	txid := GetTxID(txMetadata.Tx)
+	txItem := &wallet.TxItem{
+		TxID:          txid,
+		ConfirmHeight: wallet.TxPending,
+	}
+
+	ownUTXOs := make(map[[36]byte]*wallet.OwnedUTXO)
+	for _, utxo := range m.GetUTXOs() {
+		ownUTXOs[utxo.SerialiseToOutpoint()] = utxo
+	}
+	for _, txIn := range txMetadata.Tx.TxIn {
+		outpoint := OutpointKey(txIn.PreviousOutPoint)
+		utxo := ownUTXOs[outpoint]
+		if utxo == nil {
+			return nil, fmt.Errorf("input %s is not a wallet UTXO", txIn.PreviousOutPoint)
+		}
+		if err := txItem.AddTxIn(outpoint, utxo.Amount); err != nil {
+			return nil, fmt.Errorf("failed to add input: %w", err)
+		}
+	}
+	for vout, txOut := range txMetadata.Tx.TxOut {
+		err := txItem.AddTxOut(txOut.PkScript, uint64(txOut.Value), owned[uint32(vout)], uint32(vout))
+		if err != nil {
+			return nil, fmt.Errorf("failed to add output: %w", err)
+		}
+	}
+
+	logging.L.Debug().
+		Hex("txid", txid[:]).
+		Int("net_amount", txItem.NetAmount()).
+		Int("fee", txItem.Fees()).
+		Msg("mapped sent transaction")
+	return txItem, nil
+}
+
+// FailedBroadcast is a signed transaction that was rejected on broadcast
+type FailedBroadcast struct {
+	TxID      string `json:"txid"`
+	TxHex     string `json:"tx_hex"`
+	Reason    string `json:"reason"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+// RecordFailedBroadcast keeps a rejected transaction together with the rejection reason.
+// A repeated rejection of the same transaction replaces the previous entry.
+// The inputs are not marked as spent.
+func (m *Manager) RecordFailedBroadcast(tx *wire.MsgTx, reason string) error {
+	txHex, err := SerializeTx(tx)
+	if err != nil {
+		return err
+	}
+	txID := GetTxID(tx)
+
+	failed := &FailedBroadcast{
+		TxID:      hex.EncodeToString(txID[:]),
+		TxHex:     txHex,
+		Reason:    reason,
+		Timestamp: time.Now().Unix(),
+	}
+
+	m.historyMu.Lock()
+	defer m.historyMu.Unlock()
+	m.removeFailedBroadcast(failed.TxID)
+	m.FailedBroadcasts = append(m.FailedBroadcasts, failed)
+
 	return nil
 }
 
+// clearFailedBroadcast drops the failed entry for txid, e.g. after a successful retry
+func (m *Manager) clearFailedBroadcast(txid string) {
+	m.historyMu.Lock()
+	defer m.historyMu.Unlock()
+	m.removeFailedBroadcast(txid)
+}
+
+// removeFailedBroadcast drops the failed entry for txid, the caller holds historyMu
+func (m *Manager) removeFailedBroadcast(txid string) {
+	kept := m.FailedBroadcasts[:0]
+	for _, failed := range m.FailedBroadcasts {
+		if failed.TxID != txid {
+			kept = append(kept, failed)
+		}
+	}
+	m.FailedBroadcasts = kept
+}
+
+// GetFailedBroadcasts returns a copy of the rejected transactions, oldest first
+func (m *Manager) GetFailedBroadcasts() []*FailedBroadcast {
+	m.historyMu.RLock()
+	defer m.historyMu.RUnlock()
+	return slices.Clone(m.FailedBroadcasts)
+}
+
 // markUTXOsAsSpent marks UTXOs as spent (unconfirmed spend) after successful broadcast
 func (m *Manager) markUTXOsAsSpent(tx *wire.MsgTx) {
+	m.walletMu.Lock()
+	defer m.walletMu.Unlock()
+
 	for _, txIn := range tx.TxIn {
+		spent := OutpointKey(txIn.PreviousOutPoint)
 		// Find and mark the UTXO as spent
 		for _, utxo := range m.Wallet.GetUTXOs() {
-			isTxIDMatch := bytes.Equal(utxo.Txid[:], utils.ReverseBytesCopy(txIn.PreviousOutPoint.Hash[:]))
-			isVoutMatch := utxo.Vout == txIn.PreviousOutPoint.Index
-			if isTxIDMatch && isVoutMatch {
+			if utxo.SerialiseToOutpoint() == spent {
 				// Mark UTXO as spent
 				utxo.State = wallet.StateUnconfirmedSpent
 				break
@@ -0,0 +1,140 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/setavenger/blindbit-lib/wallet"
+)
+
+func TestPostMempoolSpaceTx(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		rejected bool
+		reason   string
+	}{
+		{
+			name:     "node reject",
+			status:   http.StatusBadRequest,
+			body:     `sendrawtransaction RPC error: {"code":-26,"message":"min relay fee not met"}`,
+			rejected: true,
+			reason:   "min relay fee not met",
+		},
+		{name: "bad request without reject reason", status: http.StatusBadRequest, body: "Bad Request"},
+		{name: "rate limited", status: http.StatusTooManyRequests, body: "Too Many Requests"},
+		{name: "server error", status: http.StatusInternalServerError, body: `{"message":"internal error"}`},
+		{name: "unavailable", status: http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			_, err := postMempoolSpaceTx(server.URL, "00")
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			var rejected *BroadcastRejectedError
+			if errors.As(err, &rejected) != tt.rejected {
+				t.Fatalf("rejected = %v, want %v (err: %v)", !tt.rejected, tt.rejected, err)
+			}
+			if tt.rejected && rejected.Reason != tt.reason {
+				t.Fatalf("reason = %q, want %q", rejected.Reason, tt.reason)
+			}
+		})
+	}
+}
+
+func TestPostMempoolSpaceTxAccepted(t *testing.T) {
+	const txid = "4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(txid + "\n"))
+	}))
+	defer server.Close()
+
+	got, err := postMempoolSpaceTx(server.URL, "00")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != txid {
+		t.Fatalf("txid = %q, want %q", got, txid)
+	}
+}
+
+func TestPostMempoolSpaceTxUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := server.URL
+	server.Close()
+
+	_, err := postMempoolSpaceTx(url, "00")
+	var rejected *BroadcastRejectedError
+	if err == nil || errors.As(err, &rejected) {
+		t.Fatalf("expected a transport error, got %v", err)
+	}
+}
+
+func TestSentTxItemExternalWithChange(t *testing.T) {
+	m := newTestWallet(t, 100_000)
+	txMetadata := sendTo(t, m, taprootRecipient(30_000))
+
+	if txMetadata.ChangeRecipient == nil || len(txMetadata.Tx.TxOut) != 2 {
+		t.Fatalf("expected a payment and a change output, got %d outputs", len(txMetadata.Tx.TxOut))
+	}
+
+	txItem, err := m.sentTxItem(txMetadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fee := txFee(txMetadata, 100_000)
+	if fee <= 0 {
+		t.Fatalf("transaction pays no fee: %d", fee)
+	}
+	// blindbit-lib reports the fee as outputs minus inputs
+	if txItem.Fees() != -fee {
+		t.Fatalf("fee = %d, want %d", txItem.Fees(), -fee)
+	}
+	// the change came back, only the payment and the fee left the wallet
+	if want := -(30_000 + fee); txItem.NetAmount() != want {
+		t.Fatalf("net amount = %d, want %d", txItem.NetAmount(), want)
+	}
+}
+
+func TestOwnedOutputsSelfSend(t *testing.T) {
+	m := newTestWallet(t, 100_000)
+	txMetadata := sendTo(t, m, &wallet.RecipientImpl{Address: m.Wallet.Address(), Amount: 40_000})
+
+	if len(txMetadata.Tx.TxOut) != 2 {
+		t.Fatalf("expected a payment and a change output, got %d outputs", len(txMetadata.Tx.TxOut))
+	}
+	owned, err := m.OwnedOutputs(txMetadata.Tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for vout := range txMetadata.Tx.TxOut {
+		if !owned[uint32(vout)] {
+			t.Errorf("output %d of a self-send is not detected as own", vout)
+		}
+	}
+
+	txItem, err := m.sentTxItem(txMetadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fee := txFee(txMetadata, 100_000)
+	// blindbit-lib reports the fee as outputs minus inputs
+	if txItem.Fees() != -fee {
+		t.Fatalf("fee = %d, want %d", txItem.Fees(), -fee)
+	}
+	// nothing left the wallet but the fee
+	if txItem.NetAmount() != -fee {
+		t.Fatalf("net amount = %d, want %d", txItem.NetAmount(), -fee)
+	}
+}
@@ -0,0 +1,317 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/setavenger/blindbit-lib/logging"
+	"github.com/setavenger/blindbit-lib/networking/grpc"
+)
+
+// DefaultShutdownTimeout is how long Shutdown waits for the background workers
+const DefaultShutdownTimeout = 10 * time.Second
+
+// Context returns the context all background work of the manager runs under.
+// It is cancelled by Shutdown.
+func (m *Manager) Context() context.Context {
+	m.lifecycleMu.Lock()
+	defer m.lifecycleMu.Unlock()
+
+	if m.ctx == nil {
+		m.ctx, m.cancel = context.WithCancel(context.Background())
+	}
+	return m.ctx
+}
+
+// StartWatching watches the chain tip and scans new blocks from startHeight onwards.
+// onErr is called if watching ends with an error other than shutdown.
+// Calling it again while a watcher runs under ctx does nothing.
+// While held, paused on battery or offline only onErr is kept,
+// the watcher starts once released, on AC power and online.
+func (m *Manager) StartWatching(ctx context.Context, startHeight uint32, onErr func(error)) {
+	scanner := m.GetScanner()
+	if scanner == nil {
+		logging.L.Warn().Msg("scanner not initialized, skipping watch")
+		return
+	}
+	if err := m.OracleNetworkError(); err != nil {
+		logging.L.Warn().Err(err).Msg("wrong oracle network, skipping watch")
+		return
+	}
+
+	m.lifecycleMu.Lock()
+	if m.watchCtx == ctx && ctx.Err() == nil {
+		m.lifecycleMu.Unlock()
+		logging.L.Debug().Msg("scanner already watching")
+		return
+	}
+	m.onWatchErr = onErr
+	if m.watchHolds > 0 {
+		m.lifecycleMu.Unlock()
+		logging.L.Debug().Msg("scanner held by a rescan or import, watching starts once it is released")
+		return
+	}
+	if m.batteryPaused {
+		m.lifecycleMu.Unlock()
+		logging.L.Info().Msg("on battery, scanning starts once on AC power")
+		return
+	}
+	if m.Offline {
+		m.lifecycleMu.Unlock()
+		logging.L.Info().Msg("offline mode on, scanning starts once online")
+		return
+	}
+	watchCtx, stop := context.WithCancel(ctx)
+	done := make(chan struct{})
+	m.watchCtx, m.stopWatch, m.watchDone = ctx, stop, done
+	m.lifecycleMu.Unlock()
+
+	m.workers.Add(1)
+	go func() {
+		defer m.workers.Done()
+		defer func() {
+			stop()
+			// a watcher which failed or was paused can be started again
+			m.lifecycleMu.Lock()
+			if m.watchDone == done {
+				m.watchCtx, m.stopWatch, m.watchDone = nil, nil, nil
+			}
+			m.lifecycleMu.Unlock()
+			close(done)
+		}()
+
+		err := scanner.Watch(watchCtx, startHeight)
+		if err != nil && !errors.Is(err, context.Canceled) {
+			logging.L.Err(err).Msg("failed to watch scanner")
+			if onErr != nil {
+				onErr(err)
+			}
+		}
+	}()
+}
+
+// stopWatching stops the watcher, the channel handlers keep running
+func (m *Manager) stopWatching() {
+	m.lifecycleMu.Lock()
+	stop := m.stopWatch
+	m.lifecycleMu.Unlock()
+
+	if stop != nil {
+		stop()
+	}
+}
+
+// holdWatcher stops the watcher and keeps it from starting until release is called,
+// so a rescan or import has the scanner to itself. blindbit-lib's scanner skips a scan
+// while another one runs and panics when its height is set meanwhile.
+// release starts the watcher again from the scan height, Watch hands that height to the scanner.
+func (m *Manager) holdWatcher() (release func(), err error) {
+	m.lifecycleMu.Lock()
+	m.watchHolds++
+	stop, done := m.stopWatch, m.watchDone
+	m.lifecycleMu.Unlock()
+
+	var once sync.Once
+	release = func() {
+		once.Do(func() {
+			m.lifecycleMu.Lock()
+			m.watchHolds--
+			held := m.watchHolds > 0
+			m.lifecycleMu.Unlock()
+			if !held {
+				m.restartWatching(m.Context())
+			}
+		})
+	}
+
+	if stop != nil {
+		stop()
+	}
+	if done != nil {
+		select {
+		case <-done:
+		case <-time.After(DefaultShutdownTimeout):
+			release()
+			return nil, ErrScannerBusy
+		}
+	}
+	return release, nil
+}
+
+// restartWatching starts the watcher again from the scan height once the stopped one has returned.
+// Like StartWatching it does nothing while held, paused on battery or offline.
+func (m *Manager) restartWatching(ctx context.Context) {
+	m.lifecycleMu.Lock()
+	onErr, done := m.onWatchErr, m.watchDone
+	m.lifecycleMu.Unlock()
+
+	if done != nil {
+		select {
+		case <-done:
+		case <-time.After(DefaultShutdownTimeout):
+			logging.L.Warn().Msg("stopped watcher did not return in time, not restarting")
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+	if ctx.Err() != nil {
+		return
+	}
+
+	startHeight := m.ScanHeight()
+	if startHeight == 0 {
+		startHeight = m.Wallet.BirthHeight
+	}
+	m.StartWatching(ctx, uint32(startHeight), onErr)
+}
+
+// Shutdown stops the scanner, cancels the background context and waits up to timeout
+// for the watcher and channel handlers to exit. Afterwards saveFunc persists the final state.
+// The wallet is saved even if the workers did not exit in time.
+func (m *Manager) Shutdown(timeout time.Duration, saveFunc func() error) error {
+	logging.L.Info().Msg("shutting down")
+
+	m.stopWorkers()
+
+	waitErr := m.waitForWorkers(timeout)
+	if waitErr != nil {
+		logging.L.Warn().Dur("timeout", timeout).Msg("background workers did not stop in time, saving anyway")
+	}
+
+	if err := saveFunc(); err != nil {
+		logging.L.Err(err).Msg("failed to save wallet on shutdown")
+		return fmt.Errorf("failed to save wallet on shutdown: %w", err)
+	}
+
+	if waitErr != nil {
+		return waitErr
+	}
+
+	logging.L.Info().Msg("shutdown complete")
+	return nil
+}
+
+// stopWorkers stops the scanner and cancels the background context
+func (m *Manager) stopWorkers() {
+	m.lifecycleMu.Lock()
+	defer m.lifecycleMu.Unlock()
+
+	if m.Scanner != nil && m.Scanner != m.stoppedScanner {
+		if err := m.Scanner.Stop(); err != nil {
+			logging.L.Err(err).Msg("failed to stop scanner")
+		}
+		m.stoppedScanner = m.Scanner
+	}
+	if m.cancel != nil {
+		m.cancel()
+	}
+}
+
+// waitForWorkers waits up to timeout for the watcher and channel handlers to exit
+func (m *Manager) waitForWorkers(timeout time.Duration) error {
+	done := make(chan struct{})
+	go func() {
+		m.workers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-time.After(timeout):
+		return fmt.Errorf("background workers did not stop within %s", timeout)
+	}
+}
+
+type activeSettings struct {
+	oracleAddress       string
+	oracleUseTLS        bool
+	saveEveryBlocks     int
+	saveIntervalSeconds int
+	receiveLabels       int
+	labelCount          int
+	tipPollRate         float64
+}
+
+// NeedsReconnect reports whether oracle, tip polling or save settings, the receive labels or the label count
+// changed since the workers were started. ReconnectOracle applies them.
+func (m *Manager) NeedsReconnect() bool {
+	receiveLabels := m.receiveLabelCount()
+
+	m.lifecycleMu.Lock()
+	defer m.lifecycleMu.Unlock()
+
+	return m.active != activeSettings{
+		oracleAddress:       m.OracleAddress,
+		oracleUseTLS:        m.OracleUseTLS,
+		saveEveryBlocks:     m.SaveEveryBlocks,
+		saveIntervalSeconds: m.SaveIntervalSeconds,
+		receiveLabels:       receiveLabels,
+		labelCount:          m.LabelCount,
+		tipPollRate:         m.TipPollRate(),
+	}
+}
+
+// ActiveOracleAddress returns the address the oracle client is connected to.
+// It differs from OracleAddress between saving a new address and the reconnect.
+func (m *Manager) ActiveOracleAddress() string {
+	m.lifecycleMu.Lock()
+	defer m.lifecycleMu.Unlock()
+	return m.active.oracleAddress
+}
+
+// ReconnectOracle applies a changed oracle address, TLS or save setting
+// and new receive labels without a restart.
+// The watcher and channel handlers are stopped, the oracle client and scanner rebuilt
+// and the workers started again with the handlers they were started with before.
+// A rescan running at the time is cancelled.
+func (m *Manager) ReconnectOracle(timeout time.Duration) error {
+	logging.L.Info().Str("address", m.OracleAddress).Msg("reconnecting to oracle")
+
+	m.stopWorkers()
+	if err := m.waitForWorkers(timeout); err != nil {
+		return err
+	}
+
+	// a fresh background context for the new workers
+	m.lifecycleMu.Lock()
+	m.ctx, m.cancel = nil, nil
+	m.lifecycleMu.Unlock()
+	ctx := m.Context()
+
+	// the new client is in place before the previous one is closed, readers never see none
+	oracleClient, err := grpc.NewClient(ctx, m.OracleAddress, m.OracleUseTLS)
+	if err != nil {
+		return fmt.Errorf("failed to connect to oracle: %w", err)
+	}
+	if previous := m.swapOracleClient(oracleClient); previous != nil {
+		if err := previous.Close(); err != nil {
+			logging.L.Warn().Err(err).Msg("failed to close previous oracle client")
+		}
+	}
+
+	err = m.ConstructScanner(ctx)
+	if err != nil && !errors.Is(err, ErrOracleNetworkMismatch) {
+		return fmt.Errorf("failed to construct scanner: %w", err)
+	}
+
+	m.lifecycleMu.Lock()
+	saveFunc, onWatchErr := m.saveFunc, m.onWatchErr
+	m.lifecycleMu.Unlock()
+
+	if saveFunc != nil {
+		m.StartChannelHandling(ctx, saveFunc)
+	}
+	startHeight := m.ScanHeight()
+	if startHeight == 0 {
+		startHeight = m.Wallet.BirthHeight
+	}
+	m.StartWatching(ctx, uint32(startHeight), onWatchErr)
+
+	// a network mismatch leaves the scanner idle, like on startup
+	return err
+}
@@ -0,0 +1,44 @@
+package controller
+
+// SyncProgress is how far the wallet has been scanned, in percent
+type SyncProgress struct {
+	// Overall covers birth height to chain tip
+	Overall float64
+	// Rescanning is set while a rescan runs, Rescan covers its start to end height
+	Rescanning bool
+	Rescan     float64
+}
+
+// SyncProgress computes the scan progress towards tip.
+// Returns false while the tip is not known (0), which must not read as synced.
+func (m *Manager) SyncProgress(tip uint32) (SyncProgress, bool) {
+	if tip == 0 {
+		return SyncProgress{}, false
+	}
+
+	scanned := m.ScanHeight()
+	progress := SyncProgress{
+		Overall: percentOfRange(scanned, m.GetBirthHeight(), uint64(tip)),
+	}
+
+	m.rescan.mu.Lock()
+	defer m.rescan.mu.Unlock()
+	if m.rescan.active {
+		progress.Rescanning = true
+		progress.Rescan = percentOfRange(
+			uint64(m.rescan.height), uint64(m.rescan.from), uint64(m.rescan.to),
+		)
+	}
+	return progress, true
+}
+
+// percentOfRange is the share of the heights from to to that height has reached, 0-100
+func percentOfRange(height, from, to uint64) float64 {
+	if height >= to {
+		return 100
+	}
+	if height <= from {
+		return 0
+	}
+	return float64(height-from) / float64(to-from) * 100
+}
@@ -0,0 +1,89 @@
+package controller
+
+import (
+	"encoding/hex"
+
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/setavenger/blindbit-lib/logging"
+	"github.com/setavenger/blindbit-lib/types"
+)
+
+// DecodedInput is a transaction input, Amount is 0 unless it spends a wallet UTXO
+type DecodedInput struct {
+	Outpoint string
+	Amount   uint64
+	Own      bool
+}
+
+// DecodedOutput is a transaction output. Own outputs pay back to the wallet, change or a self-send.
+type DecodedOutput struct {
+	Vout    uint32
+	Address string
+	// Script is the hex of the output script
+	Script string
+	Amount uint64
+	Own    bool
+}
+
+// DecodedTx is a transaction broken down for review before it is broadcast
+type DecodedTx struct {
+	TxID    string
+	Hex     string
+	VBytes  uint64
+	Inputs  []DecodedInput
+	Outputs []DecodedOutput
+}
+
+// DecodeTransaction breaks tx down into its inputs and outputs.
+// Addresses are encoded for the wallet's network, own outputs are found like OwnedOutputs does.
+func (m *Manager) DecodeTransaction(tx *wire.MsgTx) (*DecodedTx, error) {
+	txHex, err := SerializeTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	txid := GetTxID(tx)
+
+	owned, err := m.OwnedOutputs(tx)
+	if err != nil {
+		logging.L.Warn().Err(err).Msg("failed to find own outputs, showing all outputs as external")
+	}
+
+	utxos := make(map[[36]byte]uint64)
+	for _, utxo := range m.GetUTXOs() {
+		utxos[utxo.SerialiseToOutpoint()] = utxo.Amount
+	}
+
+	decoded := &DecodedTx{
+		TxID:   hex.EncodeToString(txid[:]),
+		Hex:    txHex,
+		VBytes: CalculateTxVBytes(tx),
+	}
+	for _, txIn := range tx.TxIn {
+		amount, own := utxos[OutpointKey(txIn.PreviousOutPoint)]
+		decoded.Inputs = append(decoded.Inputs, DecodedInput{
+			Outpoint: txIn.PreviousOutPoint.String(),
+			Amount:   amount,
+			Own:      own,
+		})
+	}
+
+	params := types.NetworkParams[m.GetNetwork()]
+	for vout, txOut := range tx.TxOut {
+		var address string
+		if params != nil {
+			_, addrs, _, err := txscript.ExtractPkScriptAddrs(txOut.PkScript, params)
+			if err == nil && len(addrs) == 1 {
+				address = addrs[0].EncodeAddress()
+			}
+		}
+		decoded.Outputs = append(decoded.Outputs, DecodedOutput{
+			Vout:    uint32(vout),
+			Address: address,
+			Script:  hex.EncodeToString(txOut.PkScript),
+			Amount:  uint64(txOut.Value),
+			Own:     owned[uint32(vout)],
+		})
+	}
+	return decoded, nil
+}
@@ -0,0 +1,92 @@
+package controller
+
+import (
+	"bytes"
+	"cmp"
+	"slices"
+
+	"github.com/setavenger/blindbit-lib/wallet"
+)
+
+// Wallet.LastScanHeight and Wallet.UTXOs are written by the channel handlers
+// while the GUI reads them from its tickers. Access goes through walletMu.
+// The scanner adds UTXOs and marks them spent inside blindbit-lib without the lock,
+// those writes are out of reach until the library guards its wallet itself.
+
+// ScanHeight returns the height the wallet has been scanned up to
+func (m *Manager) ScanHeight() uint64 {
+	m.walletMu.RLock()
+	defer m.walletMu.RUnlock()
+	return m.Wallet.LastScanHeight
+}
+
+// SetScanHeight moves the wallet's scan height
+func (m *Manager) SetScanHeight(height uint64) {
+	m.walletMu.Lock()
+	defer m.walletMu.Unlock()
+	m.Wallet.LastScanHeight = height
+}
+
+// GetUTXOs returns copies of the wallet's UTXOs, optionally filtered by state.
+// Callers can read them without racing the channel handlers,
+// changes to the copies do not reach the wallet.
+// They are ordered by height, then txid and vout, the same on every call
+// no matter in which order the scanner added them.
+func (m *Manager) GetUTXOs(states ...wallet.UTXOState) []*wallet.OwnedUTXO {
+	m.walletMu.RLock()
+	defer m.walletMu.RUnlock()
+
+	utxos := make([]*wallet.OwnedUTXO, 0, len(m.Wallet.UTXOs))
+	for _, utxo := range m.Wallet.UTXOs {
+		if len(states) > 0 && !slices.Contains(states, utxo.State) {
+			continue
+		}
+		utxoCopy := *utxo
+		utxos = append(utxos, &utxoCopy)
+	}
+	slices.SortFunc(utxos, compareUTXOs)
+	return utxos
+}
+
+// compareUTXOs orders by height, then txid and vout
+func compareUTXOs(a, b *wallet.OwnedUTXO) int {
+	if c := cmp.Compare(a.Height, b.Height); c != 0 {
+		return c
+	}
+	if c := bytes.Compare(a.Txid[:], b.Txid[:]); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.Vout, b.Vout)
+}
+
+// UTXOCount returns the number of UTXOs in the wallet, spent ones included
+func (m *Manager) UTXOCount() int {
+	m.walletMu.RLock()
+	defer m.walletMu.RUnlock()
+	return len(m.Wallet.UTXOs)
+}
+
+// UTXOStats counts the wallet's UTXOs by state.
+// Spent includes unconfirmed spends, unconfirmed receives only count towards Total.
+type UTXOStats struct {
+	Total   int
+	Unspent int
+	Spent   int
+}
+
+// GetUTXOStats counts the wallet's UTXOs by state
+func (m *Manager) GetUTXOStats() UTXOStats {
+	m.walletMu.RLock()
+	defer m.walletMu.RUnlock()
+
+	stats := UTXOStats{Total: len(m.Wallet.UTXOs)}
+	for _, utxo := range m.Wallet.UTXOs {
+		switch utxo.State {
+		case wallet.StateUnspent:
+			stats.Unspent++
+		case wallet.StateSpent, wallet.StateUnconfirmedSpent:
+			stats.Spent++
+		}
+	}
+	return stats
+}
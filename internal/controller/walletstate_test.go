@@ -0,0 +1,71 @@
+package controller
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/setavenger/blindbit-lib/wallet"
+)
+
+func TestGetUTXOsStableOrder(t *testing.T) {
+	utxos := []*wallet.OwnedUTXO{
+		testUTXO(3, 0, 1_000),
+		testUTXO(1, 2, 2_000),
+		testUTXO(1, 0, 3_000),
+		testUTXO(2, 1, 4_000),
+		testUTXO(9, 0, 5_000),
+	}
+	utxos[4].Height = 5
+	m := newTestManager(utxos...)
+
+	want := []uint64{3_000, 2_000, 4_000, 1_000, 5_000}
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		// the scanner adds UTXOs in no particular order
+		rng.Shuffle(len(m.Wallet.UTXOs), func(a, b int) {
+			m.Wallet.UTXOs[a], m.Wallet.UTXOs[b] = m.Wallet.UTXOs[b], m.Wallet.UTXOs[a]
+		})
+
+		got := m.GetUTXOs()
+		if len(got) != len(want) {
+			t.Fatalf("got %d UTXOs, want %d", len(got), len(want))
+		}
+		for j, utxo := range got {
+			if utxo.Amount != want[j] {
+				t.Fatalf("merge %d: UTXO %d is %d sats, want %d", i, j, utxo.Amount, want[j])
+			}
+		}
+	}
+}
+
+// run with -race, the scanner's handlers write while the GUI tickers read
+func TestWalletStateConcurrentAccess(t *testing.T) {
+	m := newTestManager(testUTXO(1, 0, 10_000))
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 200; i++ {
+			m.SetScanHeight(uint64(i))
+			m.addPendingUTXOs([]*wallet.OwnedUTXO{testUTXO(2, uint32(i), 1_000)})
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 200; i++ {
+			m.ScanHeight()
+			m.GetUTXOs(wallet.StateUnspent)
+			m.GetUTXOStats()
+		}
+	}()
+	wg.Wait()
+
+	if got := m.ScanHeight(); got != 199 {
+		t.Fatalf("scan height = %d, want 199", got)
+	}
+	if got := m.UTXOCount(); got != 201 {
+		t.Fatalf("UTXO count = %d, want 201", got)
+	}
+}
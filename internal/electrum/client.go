@@ -0,0 +1,228 @@
+// Package electrum is a minimal client for the Electrum server protocol.
+// It only implements the calls the desktop app needs and opens one
+// connection per client (no subscriptions).
+package electrum
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/setavenger/blindbit-lib/logging"
+)
+
+const (
+	clientName      = "blindbit-desktop"
+	protocolVersion = "1.4"
+	dialTimeout     = 10 * time.Second
+)
+
+// Client is a connection to a single Electrum server
+type Client struct {
+	conn   net.Conn
+	reader *bufio.Reader
+
+	mu     sync.Mutex
+	nextID uint64
+}
+
+type request struct {
+	JSONRPC string `json:"jsonrpc"`
+	ID      uint64 `json:"id"`
+	Method  string `json:"method"`
+	Params  []any  `json:"params"`
+}
+
+type response struct {
+	ID     *uint64         `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *RPCError       `json:"error"`
+}
+
+// RPCError is an error returned by the Electrum server.
+// For broadcasts the message holds the node's rejection reason.
+type RPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("electrum error %d: %s", e.Code, e.Message)
+}
+
+// ParseServerURL splits an Electrum server url into host:port and whether TLS should be used.
+// Accepted forms are ssl://host:port, tls://host:port and tcp://host:port.
+// A url without scheme defaults to TLS.
+func ParseServerURL(serverURL string) (address string, useTLS bool, err error) {
+	serverURL = strings.TrimSpace(serverURL)
+	if serverURL == "" {
+		return "", false, errors.New("electrum server url is empty")
+	}
+
+	scheme, rest, found := strings.Cut(serverURL, "://")
+	if !found {
+		scheme, rest = "ssl", serverURL
+	}
+
+	switch strings.ToLower(scheme) {
+	case "ssl", "tls":
+		useTLS = true
+	case "tcp":
+		useTLS = false
+	default:
+		return "", false, fmt.Errorf("unsupported electrum scheme %q", scheme)
+	}
+
+	if _, _, err = net.SplitHostPort(rest); err != nil {
+		return "", false, fmt.Errorf("invalid electrum server address %q: %w", rest, err)
+	}
+
+	return rest, useTLS, nil
+}
+
+// Dial connects to the server at serverURL and negotiates the protocol version
+func Dial(ctx context.Context, serverURL string) (*Client, error) {
+	address, useTLS, err := ParseServerURL(serverURL)
+	if err != nil {
+		return nil, err
+	}
+
+	dialer := &net.Dialer{Timeout: dialTimeout}
+
+	var conn net.Conn
+	if useTLS {
+		host, _, _ := net.SplitHostPort(address)
+		tlsDialer := &tls.Dialer{
+			NetDialer: dialer,
+			Config:    &tls.Config{ServerName: host},
+		}
+		conn, err = tlsDialer.DialContext(ctx, "tcp", address)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", address)
+	}
+	if err != nil {
+		logging.L.Err(err).
+			Str("address", address).
+			Bool("tls", useTLS).
+			Msg("failed to connect to electrum server")
+		return nil, fmt.Errorf("failed to connect to electrum server: %w", err)
+	}
+
+	c := &Client{
+		conn:   conn,
+		reader: bufio.NewReader(conn),
+	}
+
+	var serverVersion []string
+	err = c.call(ctx, "server.version", []any{clientName, protocolVersion}, &serverVersion)
+	if err != nil {
+		c.Close()
+		return nil, fmt.Errorf("failed to negotiate electrum protocol version: %w", err)
+	}
+	logging.L.Debug().Strs("server_version", serverVersion).Msg("connected to electrum server")
+
+	return c, nil
+}
+
+// Close closes the underlying connection
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// BroadcastTransaction submits a raw transaction in hex and returns the txid reported by the server
+func (c *Client) BroadcastTransaction(ctx context.Context, txHex string) (string, error) {
+	var txid string
+	err := c.call(ctx, "blockchain.transaction.broadcast", []any{txHex}, &txid)
+	if err != nil {
+		return "", err
+	}
+	return txid, nil
+}
+
+// GetTransaction returns the raw transaction in hex for txid.
+// Works for mempool and confirmed transactions alike.
+func (c *Client) GetTransaction(ctx context.Context, txid string) (string, error) {
+	var txHex string
+	err := c.call(ctx, "blockchain.transaction.get", []any{txid}, &txHex)
+	if err != nil {
+		return "", err
+	}
+	return txHex, nil
+}
+
+// HistoryEntry is a transaction touching a script, see GetScriptHashHistory
+type HistoryEntry struct {
+	TxHash string `json:"tx_hash"`
+	// Height of the confirming block, 0 or -1 while in the mempool
+	Height int64 `json:"height"`
+}
+
+// GetScriptHashHistory returns the confirmed and mempool transactions of a script.
+// scriptHash is the reversed sha256 of the output script in hex, as the protocol defines it.
+func (c *Client) GetScriptHashHistory(ctx context.Context, scriptHash string) ([]HistoryEntry, error) {
+	var history []HistoryEntry
+	err := c.call(ctx, "blockchain.scripthash.get_history", []any{scriptHash}, &history)
+	if err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// call sends a single request and waits for the response with the matching id.
+// Notifications (messages without id) are skipped.
+func (c *Client) call(ctx context.Context, method string, params []any, result any) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.nextID++
+	id := c.nextID
+
+	if deadline, ok := ctx.Deadline(); ok {
+		c.conn.SetDeadline(deadline)
+	} else {
+		c.conn.SetDeadline(time.Now().Add(30 * time.Second))
+	}
+
+	payload, err := json.Marshal(request{
+		JSONRPC: "2.0",
+		ID:      id,
+		Method:  method,
+		Params:  params,
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err = c.conn.Write(append(payload, '\n')); err != nil {
+		return fmt.Errorf("failed to write %s request: %w", method, err)
+	}
+
+	for {
+		line, err := c.reader.ReadBytes('\n')
+		if err != nil {
+			return fmt.Errorf("failed to read %s response: %w", method, err)
+		}
+
+		var resp response
+		if err = json.Unmarshal(line, &resp); err != nil {
+			return fmt.Errorf("failed to decode %s response: %w", method, err)
+		}
+		if resp.ID == nil || *resp.ID != id {
+			continue
+		}
+		if resp.Error != nil {
+			return resp.Error
+		}
+		if result == nil {
+			return nil
+		}
+		return json.Unmarshal(resp.Result, result)
+	}
+}
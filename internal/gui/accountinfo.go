@@ -0,0 +1,46 @@
+package gui
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// showAccountInfo lists the public wallet data, e.g. to check an import used the right keys.
+// Nothing secret is shown here.
+func (g *MainGUI) showAccountInfo() {
+	w := g.manager.Wallet
+
+	rows := []struct {
+		name  string
+		value string
+	}{
+		{"Silent Payment Address", g.manager.GetSilentPaymentAddress()},
+		{"Scan Public Key", w.PubKeyScan.String()},
+		{"Spend Public Key", w.PubKeySpend.String()},
+		{"Network", string(g.manager.GetNetwork())},
+		{"Birth Height", FormatHeightUint64(g.manager.GetBirthHeight())},
+		{"Labels (excluding change)", fmt.Sprintf("%d", len(w.Labels))},
+	}
+
+	var items []*widget.FormItem
+	for _, row := range rows {
+		valueLabel := widget.NewLabel(row.value)
+		valueLabel.TextStyle.Monospace = true
+		valueLabel.Wrapping = fyne.TextWrapBreak
+
+		copyBtn := widget.NewButton("Copy", func() {
+			g.copyIDToClipboard(row.name, row.value)
+		})
+		items = append(items, widget.NewFormItem(
+			row.name, container.NewBorder(nil, nil, nil, copyBtn, valueLabel),
+		))
+	}
+
+	d := dialog.NewCustom("Account Info", "Close", widget.NewForm(items...), g.window)
+	d.Resize(fyne.NewSize(720, d.MinSize().Height))
+	d.Show()
+}
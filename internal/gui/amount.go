@@ -0,0 +1,136 @@
+package gui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	satsPerBTC = 100_000_000
+	// maxAmountSats is the bitcoin supply, no amount can be larger
+	maxAmountSats = 21_000_000 * satsPerBTC
+)
+
+// ParseAmount reads an amount in sats as typed into an amount field:
+//   - sats with optional thousands separators: "100000", "100,000", "100,000 sats"
+//   - thousands of sats: "100k", "2.5k sats"
+//   - bitcoin with up to 8 decimals: "0.001 BTC"
+//
+// Input that could mean more than one amount is rejected: decimals without a unit ("0.5"),
+// commas not grouping thousands ("1,5") and commas in bitcoin amounts ("1,000 BTC").
+func ParseAmount(text string) (uint64, error) {
+	s := strings.ToLower(strings.Join(strings.Fields(text), ""))
+	if s == "" {
+		return 0, errors.New("amount is empty")
+	}
+
+	var unit string
+	for _, suffix := range []string{"btc", "sats", "sat"} {
+		if strings.HasSuffix(s, suffix) {
+			unit, s = suffix, strings.TrimSuffix(s, suffix)
+			break
+		}
+	}
+	kilo := strings.HasSuffix(s, "k")
+	s = strings.TrimSuffix(s, "k")
+
+	switch {
+	case unit == "btc" && kilo:
+		return 0, errors.New("k is only accepted for sats, e.g. 100k sats")
+	case unit == "btc" && strings.Contains(s, ","):
+		return 0, errors.New("bitcoin amounts take no thousands separators, use a dot for decimals, e.g. 0.001 BTC")
+	case unit == "" && !kilo && strings.Contains(s, "."):
+		return 0, fmt.Errorf("%q is ambiguous, add a unit, e.g. %s BTC", strings.TrimSpace(text), s)
+	case unit != "btc" && !kilo && strings.Contains(s, "."):
+		return 0, errors.New("sats have no decimals, use BTC or k for fractions, e.g. 0.001 BTC or 2.5k sats")
+	}
+
+	number, err := parseGroupedDecimal(s)
+	if err != nil {
+		return 0, err
+	}
+
+	var sats decimal.Decimal
+	switch {
+	case unit == "btc":
+		sats = number.Mul(decimal.NewFromInt(satsPerBTC))
+		if !sats.IsInteger() {
+			return 0, errors.New("bitcoin amounts have at most 8 decimals")
+		}
+	case kilo:
+		sats = number.Mul(decimal.NewFromInt(1000))
+		if !sats.IsInteger() {
+			return 0, fmt.Errorf("%sk sats is not a whole number of sats", s)
+		}
+	default:
+		sats = number
+	}
+
+	if sats.GreaterThan(decimal.NewFromInt(maxAmountSats)) {
+		return 0, errors.New("amount exceeds the bitcoin supply")
+	}
+	return uint64(sats.IntPart()), nil
+}
+
+// parseGroupedDecimal parses digits with an optional decimal point.
+// Commas have to separate thousands in the integer part, so "1,5" or "0,001" are not taken for decimals.
+func parseGroupedDecimal(s string) (decimal.Decimal, error) {
+	intPart, fracPart, hasPoint := strings.Cut(s, ".")
+	if intPart == "" && fracPart == "" {
+		return decimal.Decimal{}, errors.New("amount has no digits")
+	}
+
+	if strings.Contains(intPart, ",") {
+		groups := strings.Split(intPart, ",")
+		for i, group := range groups {
+			first := i == 0
+			if (first && (len(group) == 0 || len(group) > 3 || group[0] == '0')) || (!first && len(group) != 3) {
+				return decimal.Decimal{}, errors.New("commas only separate thousands, use a dot for decimals")
+			}
+		}
+		intPart = strings.Join(groups, "")
+	}
+	if intPart == "" {
+		intPart = "0"
+	}
+	if !onlyDigits(intPart) || !onlyDigits(fracPart) {
+		return decimal.Decimal{}, errors.New("amount must be a number, optionally followed by BTC, sats or k")
+	}
+
+	if hasPoint && fracPart != "" {
+		return decimal.NewFromString(intPart + "." + fracPart)
+	}
+	return decimal.NewFromString(intPart)
+}
+
+func onlyDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// formatAmountInput adds thousands separators to an amount typed as plain sats.
+// Commas in the text are only replaced if they group thousands or were added here before,
+// regrouped is set for the latter. Anything else, such as "0.001 BTC", is left alone.
+func formatAmountInput(text string, regroup bool) (string, bool) {
+	digits := strings.ReplaceAll(text, ",", "")
+	if digits == "" || !onlyDigits(digits) {
+		return "", false
+	}
+	if strings.Contains(text, ",") && !regroup {
+		if _, err := parseGroupedDecimal(text); err != nil {
+			return "", false
+		}
+	}
+	amount, err := ParseFormattedUint64(digits)
+	if err != nil {
+		return "", false
+	}
+	return FormatUint64(amount), true
+}
@@ -0,0 +1,72 @@
+package gui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/setavenger/blindbit-desktop/internal/controller"
+	"github.com/setavenger/blindbit-lib/logging"
+)
+
+const birthDateLayout = "2006-01-02"
+
+// showBirthHeightByDateDialog asks for the date the wallet was created
+// and passes the matching block height to onHeight
+func showBirthHeightByDateDialog(
+	window fyne.Window,
+	manager *controller.Manager,
+	onHeight func(height uint64),
+) {
+	dateEntry := widget.NewEntry()
+	dateEntry.SetPlaceHolder("YYYY-MM-DD")
+	hint := widget.NewLabel(
+		"Date of the wallet's first transaction (or creation).\n" +
+			"If unsure pick an earlier date, scanning starts from there.",
+	)
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Date", dateEntry),
+		widget.NewFormItem("", hint),
+	}
+
+	dialog.ShowForm("Set Birth Height by Date", "Look Up", "Cancel", items, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+
+		date, err := time.Parse(birthDateLayout, strings.TrimSpace(dateEntry.Text))
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("invalid date, expected YYYY-MM-DD"), window)
+			return
+		}
+
+		progress := dialog.NewCustomWithoutButtons(
+			"Looking up block height",
+			container.NewVBox(widget.NewProgressBarInfinite()),
+			window,
+		)
+		progress.Show()
+
+		go func() {
+			height, err := manager.BirthHeightByDate(date)
+			progress.Hide()
+			if err != nil {
+				logging.L.Err(err).Time("date", date).Msg("failed to look up birth height by date")
+				dialog.ShowError(fmt.Errorf("failed to look up block height: %v", err), window)
+				return
+			}
+
+			logging.L.Info().
+				Time("date", date).
+				Uint64("height", height).
+				Msg("birth height from date")
+			onHeight(height)
+		}()
+	}, window)
+}
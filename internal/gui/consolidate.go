@@ -0,0 +1,113 @@
+package gui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/setavenger/blindbit-desktop/internal/configs"
+	"github.com/setavenger/blindbit-desktop/internal/controller"
+	"github.com/setavenger/blindbit-lib/logging"
+)
+
+// showConsolidateDialog asks for a fee rate and an optional threshold and
+// prepares a self-send merging the matching UTXOs
+func (g *MainGUI) showConsolidateDialog() {
+	feeRateEntry := widget.NewEntry()
+	feeRateEntry.SetPlaceHolder("Fee rate (sat/vB)")
+
+	thresholdEntry := widget.NewEntry()
+	thresholdEntry.SetPlaceHolder("Optional, e.g. 10,000 (empty = all UTXOs)")
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Fee Rate", feeRateEntry),
+		widget.NewFormItem("Only UTXOs below (sats)", thresholdEntry),
+	}
+
+	dialog.ShowForm("Consolidate UTXOs", "Preview", "Cancel", items, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+
+		feeRate, err := ParseFormattedUint64(feeRateEntry.Text)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("invalid fee rate: %s", feeRateEntry.Text), g.window)
+			return
+		}
+		if err := g.manager.CheckFeeRate(feeRate); err != nil {
+			g.showError(err)
+			return
+		}
+
+		var threshold uint64
+		if strings.TrimSpace(thresholdEntry.Text) != "" {
+			threshold, err = ParseFormattedUint64(thresholdEntry.Text)
+			if err != nil {
+				dialog.ShowError(fmt.Errorf("invalid threshold: %v", err), g.window)
+				return
+			}
+		}
+
+		prepare := func() {
+			consolidation, err := g.manager.PrepareConsolidation(context.Background(), uint32(feeRate), threshold)
+			if err != nil {
+				g.showError(fmt.Errorf("failed to prepare consolidation: %w", err))
+				return
+			}
+
+			go g.confirmConsolidation(consolidation, uint32(feeRate))
+		}
+		if feeRate >= configs.HighFeeRate {
+			g.confirmHighFeeRate(feeRate, prepare)
+			return
+		}
+		prepare()
+	}, g.window)
+}
+
+// confirmConsolidation warns if the consolidation costs more than it saves at current rates
+// and then shows the regular transaction preview
+func (g *MainGUI) confirmConsolidation(consolidation *controller.Consolidation, feeRate uint32) {
+	// what the inputs would cost to spend separately at today's rates
+	referenceRate := feeRate
+	if g.manager.FeeEstimationEnabled && !g.manager.IsOffline() {
+		estimates, err := getCurrentFeeEstimates(g.manager.GetNetwork())
+		if err != nil {
+			logging.L.Err(err).Msg("failed to fetch fee estimates for consolidation")
+		} else if estimates.HalfHourFee > 0 {
+			referenceRate = uint32(estimates.HalfHourFee)
+		}
+	}
+
+	showPreview := func() {
+		g.showTransactionDetails(
+			consolidation.TxMetadata,
+			consolidation.Recipients,
+			controller.CoinSelectionSmallestFirst,
+			feeRate,
+		)
+	}
+
+	savings := consolidation.SavingsAt(referenceRate)
+	if consolidation.Fee <= savings {
+		showPreview()
+		return
+	}
+
+	dialog.ShowConfirm("Consolidation Not Worth It",
+		fmt.Sprintf(
+			"Merging %d UTXOs costs %s in fees but only saves about %s on future spends at %d sat/vB.\n\nContinue anyway?",
+			consolidation.InputCount,
+			FormatSatoshiUint64(consolidation.Fee),
+			FormatSatoshiUint64(savings),
+			referenceRate,
+		),
+		func(confirmed bool) {
+			if confirmed {
+				showPreview()
+			}
+		}, g.window)
+}
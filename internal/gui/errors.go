@@ -0,0 +1,46 @@
+package gui
+
+import (
+	"errors"
+	"fmt"
+
+	"fyne.io/fyne/v2/dialog"
+
+	"github.com/setavenger/blindbit-desktop/internal/controller"
+)
+
+// errorHint suggests what to do about errors the user can act on, "" for any other error
+func errorHint(err error) string {
+	switch {
+	case errors.Is(err, controller.ErrOffline):
+		return "Turn off Wallet → Offline Mode to use the network."
+	case errors.Is(err, controller.ErrScannerNotReady), errors.Is(err, controller.ErrOracleNotReady):
+		return "The wallet is still connecting to the oracle, try again in a moment."
+	case errors.Is(err, controller.ErrScannerBusy):
+		return "The scanner is still busy with new blocks, try again in a moment."
+	case errors.Is(err, controller.ErrOracleNetworkMismatch):
+		return "Pick an oracle for the wallet's network in Settings."
+	case errors.Is(err, controller.ErrNoWallet):
+		return "Create or restore a wallet first."
+	case errors.Is(err, controller.ErrInsufficientFunds):
+		return "Lower the amount or the fee rate, or wait for incoming payments to confirm."
+	case errors.Is(err, controller.ErrFeeRateTooLow):
+		return "Raise the fee rate or lower the minimum fee rate in Settings."
+	case errors.Is(err, controller.ErrRescanRunning):
+		return "Wait for the running rescan to finish or pause it first."
+	case errors.Is(err, controller.ErrBroadcastOutcomeUnknown):
+		return "Retry the same transaction instead of creating a new one, " +
+			"it counts as sent if the backend already has it. It is kept under Transactions → Failed Broadcasts."
+	case errors.Is(err, controller.ErrTooFewUTXOs):
+		return "Raise the threshold or leave it empty to include every UTXO."
+	}
+	return ""
+}
+
+// showError shows err together with a hint for errors the user can act on
+func (g *MainGUI) showError(err error) {
+	if hint := errorHint(err); hint != "" {
+		err = fmt.Errorf("%w\n\n%s", err, hint)
+	}
+	dialog.ShowError(err, g.window)
+}
@@ -0,0 +1,111 @@
+package gui
+
+import (
+	"encoding/hex"
+	"strings"
+
+	"github.com/setavenger/blindbit-lib/wallet"
+)
+
+// Search queries are whitespace separated terms, all of them have to match.
+// UTXO terms:
+//   - >N, <N or N-M filter the amount in sats
+//   - label:N matches the label index
+//   - a state (unspent, spent, unconfirmed, unconfirmed_spent)
+//   - anything else matches a txid prefix or the note
+//
+// Transaction terms:
+//   - a type (incoming, outgoing, self) or status (pending, confirmed)
+//   - anything else matches a txid prefix or the note
+const (
+	utxoSearchPlaceholder = "Search: txid, note, state, label:1, >1000, 1000-50000"
+	txSearchPlaceholder   = "Search: txid, note, incoming, outgoing, self, pending"
+)
+
+// transaction types as shown and searched for
+const (
+	txTypeIncoming = "incoming"
+	txTypeOutgoing = "outgoing"
+	txTypeSelf     = "self"
+)
+
+// txType classifies a history entry. Without inputs from the wallet it is incoming,
+// without outputs to others it is a transfer to self.
+func txType(tx *wallet.TxItem) string {
+	if tx.SumOutFlows() == 0 {
+		return txTypeIncoming
+	}
+	if tx.SumInflows(wallet.InflowAggModeExternal) == 0 {
+		return txTypeSelf
+	}
+	return txTypeOutgoing
+}
+
+func searchTerms(query string) []string {
+	return strings.Fields(strings.ToLower(query))
+}
+
+// matchesIDOrNote is the fallback for terms without special meaning
+func matchesIDOrNote(term string, id []byte, note string) bool {
+	return strings.HasPrefix(hex.EncodeToString(id), term) ||
+		strings.Contains(strings.ToLower(note), term)
+}
+
+func utxoMatches(utxo *wallet.OwnedUTXO, note string, terms []string) bool {
+	for _, term := range terms {
+		if !utxoMatchesTerm(utxo, note, term) {
+			return false
+		}
+	}
+	return true
+}
+
+func utxoMatchesTerm(utxo *wallet.OwnedUTXO, note, term string) bool {
+	switch {
+	case strings.HasPrefix(term, ">"):
+		if minAmount, err := ParseFormattedUint64(term[1:]); err == nil {
+			return utxo.Amount > minAmount
+		}
+	case strings.HasPrefix(term, "<"):
+		if maxAmount, err := ParseFormattedUint64(term[1:]); err == nil {
+			return utxo.Amount < maxAmount
+		}
+	case strings.HasPrefix(term, "label:"):
+		if m, err := ParseFormattedUint64(term[len("label:"):]); err == nil {
+			return utxo.Label != nil && uint64(utxo.Label.M) == m
+		}
+	case strings.Contains(term, "-"):
+		lower, upper, _ := strings.Cut(term, "-")
+		minAmount, errMin := ParseFormattedUint64(lower)
+		maxAmount, errMax := ParseFormattedUint64(upper)
+		if errMin == nil && errMax == nil {
+			return utxo.Amount >= minAmount && utxo.Amount <= maxAmount
+		}
+	}
+
+	if term == utxo.State.String() {
+		return true
+	}
+	return matchesIDOrNote(term, utxo.Txid[:], note)
+}
+
+func txMatches(tx *wallet.TxItem, note string, terms []string) bool {
+	for _, term := range terms {
+		if !txMatchesTerm(tx, note, term) {
+			return false
+		}
+	}
+	return true
+}
+
+func txMatchesTerm(tx *wallet.TxItem, note, term string) bool {
+	switch term {
+	case txTypeIncoming, txTypeOutgoing, txTypeSelf:
+		return txType(tx) == term
+	case "pending":
+		return tx.ConfirmHeight <= 0
+	case "confirmed":
+		return tx.ConfirmHeight > 0
+	}
+	return matchesIDOrNote(term, tx.TxID[:], note)
+}
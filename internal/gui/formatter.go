@@ -5,13 +5,28 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"golang.org/x/text/language"
 	"golang.org/x/text/message"
 
+	"github.com/setavenger/blindbit-desktop/internal/controller"
 	"github.com/setavenger/blindbit-lib/wallet"
+	"github.com/setavenger/go-bip352"
 )
 
+// addressEdgeChars is how many characters FormatAddress keeps at each end of a silent payment address
+const addressEdgeChars = 16
+
+// FormatAddress shortens silent payment addresses, which run over 100 characters, to their start and end.
+// Other addresses are shown in full.
+func FormatAddress(address string) string {
+	if !bip352.IsSilentPaymentAddress(address) || len(address) <= 2*addressEdgeChars+1 {
+		return address
+	}
+	return address[:addressEdgeChars] + "…" + address[len(address)-addressEdgeChars:]
+}
+
 // FormatNumber formats a number with thousand separators (commas) using golang.org/x/text
 func FormatNumber(n int64) string {
 	p := message.NewPrinter(language.English)
@@ -54,6 +69,23 @@ func FormatHeightUint64(height uint64) string {
 	return p.Sprintf("%d", height)
 }
 
+// FormatScanETA describes how long the scan needs to reach tip,
+// "Synced" once caught up and "Estimating..." until there is a rate estimate
+func FormatScanETA(eta time.Duration, ok bool) string {
+	switch {
+	case !ok:
+		return "Estimating..."
+	case eta == 0:
+		return "Synced"
+	case eta < time.Minute:
+		return "less than a minute remaining"
+	case eta < time.Hour:
+		return fmt.Sprintf("~%d min remaining", int(eta.Minutes()))
+	default:
+		return fmt.Sprintf("~%dh %dmin remaining", int(eta.Hours()), int(eta.Minutes())%60)
+	}
+}
+
 // ParseFormattedNumber parses a number string that may contain commas
 func ParseFormattedNumber(str string) (int64, error) {
 	// Remove commas
@@ -73,12 +105,30 @@ type TxRowData struct {
 	TXID      string // truncated hex (8 chars + "...")
 	Height    string // formatted block height
 	NetAmount string // formatted net amount with sign
-	Status    string // "Confirmed" or "Pending"
+	Status    string // see FormatConfirmationStatus
+}
+
+// FormatConfirmationStatus describes how settled a transaction at height is.
+// Below target confirmations it is "Confirming (n/target)".
+// With an unknown tip (0) it only tells confirmed and pending apart.
+func FormatConfirmationStatus(height int, tip uint32, target int) string {
+	if height <= 0 {
+		return "Pending"
+	}
+	if tip == 0 || tip < uint32(height) {
+		return "Confirmed"
+	}
+	confirmations := int(tip) - height + 1
+	if confirmations < target {
+		return fmt.Sprintf("Confirming (%d/%d)", confirmations, target)
+	}
+	return fmt.Sprintf("Confirmed (%s)", FormatNumber(int64(confirmations)))
 }
 
 // FormatTxRow extracts the display strings for a transaction row, shared between
 // the Dashboard's recent-transaction list and the full Transactions tab.
-func FormatTxRow(tx *wallet.TxItem) TxRowData {
+// tip and target are passed to FormatConfirmationStatus.
+func FormatTxRow(tx *wallet.TxItem, tip uint32, target int) TxRowData {
 	txidHex := hex.EncodeToString(tx.TxID[:])
 
 	netAmount := tx.NetAmount()
@@ -91,10 +141,7 @@ func FormatTxRow(tx *wallet.TxItem) TxRowData {
 		amountText = FormatSatoshiUint64(0)
 	}
 
-	status := "Pending"
-	if tx.ConfirmHeight > 0 {
-		status = "Confirmed"
-	}
+	status := FormatConfirmationStatus(tx.ConfirmHeight, tip, target)
 
 	return TxRowData{
 		TXID:      fmt.Sprintf("%.8s...", txidHex),
@@ -103,3 +150,42 @@ func FormatTxRow(tx *wallet.TxItem) TxRowData {
 		Status:    status,
 	}
 }
+
+// FormatUTXOStats formats the UTXO counts as "142 UTXOs · 120 unspent · 22 spent"
+func FormatUTXOStats(stats controller.UTXOStats) string {
+	return fmt.Sprintf("%s UTXOs · %s unspent · %s spent",
+		FormatNumber(int64(stats.Total)),
+		FormatNumber(int64(stats.Unspent)),
+		FormatNumber(int64(stats.Spent)),
+	)
+}
+
+// FormatBalanceBreakdown formats the parts of the balance which can't be spent right away,
+// e.g. "Spendable: 90,000 sats · Immature: 10,000 sats · Pending: 5,000 sats".
+// Empty if everything is spendable.
+func FormatBalanceBreakdown(b controller.BalanceBreakdown) string {
+	if b.Immature == 0 && b.Unconfirmed == 0 {
+		return ""
+	}
+	parts := []string{"Spendable: " + FormatSatoshiUint64(b.Confirmed)}
+	if b.Immature > 0 {
+		parts = append(parts, "Immature: "+FormatSatoshiUint64(b.Immature))
+	}
+	if b.Unconfirmed > 0 {
+		parts = append(parts, "Pending: "+FormatSatoshiUint64(b.Unconfirmed))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// FormatSyncProgress formats "Synced: 87.3%", with the rescan progress while one runs.
+// An unknown tip shows "—" instead of a misleading percentage.
+func FormatSyncProgress(progress controller.SyncProgress, ok bool) string {
+	if !ok {
+		return "Synced: —"
+	}
+	text := fmt.Sprintf("Synced: %.1f%%", progress.Overall)
+	if progress.Rescanning {
+		text += fmt.Sprintf(" · Rescan: %.1f%%", progress.Rescan)
+	}
+	return text
+}
@@ -0,0 +1,99 @@
+package gui
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/setavenger/blindbit-lib/logging"
+)
+
+// initialSyncMinBlocks is how far the scan has to be behind the tip for the initial sync screen
+const initialSyncMinBlocks = 1000
+
+// initialSyncScreen shows the progress of a long catch up scan, e.g. after restoring with an old birth height
+type initialSyncScreen struct {
+	dialog      dialog.Dialog
+	bar         *widget.ProgressBar
+	heightLabel *widget.Label
+	etaLabel    *widget.Label
+}
+
+// showInitialSyncIfBehind shows the initial sync screen if the wallet is far behind the chain tip.
+// Asks the oracle for the tip, call it off the UI thread.
+func (g *MainGUI) showInitialSyncIfBehind() {
+	if g.manager.GetScanner() == nil {
+		// scanning is disabled, nothing would move the progress
+		return
+	}
+	tip, err := g.manager.GetCurrentHeight()
+	if err != nil || tip == 0 {
+		return
+	}
+	scanned := g.manager.ScanHeight()
+	if scanned >= uint64(tip) || uint64(tip)-scanned < initialSyncMinBlocks {
+		return
+	}
+	logging.L.Info().
+		Uint64("scan_height", scanned).
+		Uint32("tip", tip).
+		Msg("wallet is far behind the chain tip, showing initial sync")
+
+	runOnMain(func() {
+		if !g.Running() {
+			return
+		}
+		screen := &initialSyncScreen{
+			bar:         widget.NewProgressBar(),
+			heightLabel: widget.NewLabel(""),
+			etaLabel:    widget.NewLabel(""),
+		}
+		hint := widget.NewLabel("The wallet is scanning every block since its birth height for payments. " +
+			"This may take a while, balances and transactions are complete once it has caught up. " +
+			"You can use the wallet while scanning continues.")
+		hint.Wrapping = fyne.TextWrapWord
+
+		content := container.NewVBox(hint, screen.bar, screen.heightLabel, screen.etaLabel)
+		screen.dialog = dialog.NewCustom("Initial Sync", "Continue in Background", content, g.window)
+		screen.dialog.SetOnClosed(func() {
+			g.initialSyncMu.Lock()
+			g.initialSync = nil
+			g.initialSyncMu.Unlock()
+		})
+		screen.dialog.Resize(fyne.NewSize(520, content.MinSize().Height))
+
+		g.initialSyncMu.Lock()
+		g.initialSync = screen
+		g.initialSyncMu.Unlock()
+
+		g.updateInitialSync()
+		screen.dialog.Show()
+	})
+}
+
+// updateInitialSync moves the initial sync screen to the current scan height
+// and closes it once the scan has caught up with the last known tip
+func (g *MainGUI) updateInitialSync() {
+	g.initialSyncMu.Lock()
+	screen := g.initialSync
+	g.initialSyncMu.Unlock()
+	if screen == nil {
+		return
+	}
+
+	tip := g.manager.CachedChainTip()
+	progress, ok := g.manager.SyncProgress(tip)
+	if !ok {
+		return
+	}
+	scanned := g.manager.ScanHeight()
+	if scanned >= uint64(tip) {
+		screen.dialog.Hide()
+		return
+	}
+
+	screen.bar.SetValue(progress.Overall / 100)
+	screen.heightLabel.SetText("Block " + FormatHeightUint64(scanned) + " of " + FormatHeight(tip))
+	screen.etaLabel.SetText("Time Remaining: " + FormatScanETA(g.manager.ScanETA(tip)))
+}
@@ -0,0 +1,112 @@
+package gui
+
+import (
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+	"github.com/rs/zerolog"
+
+	"github.com/setavenger/blindbit-desktop/internal/configs"
+	"github.com/setavenger/blindbit-desktop/internal/logbuffer"
+)
+
+const logsTabName = "Logs"
+
+// logsRefreshInterval is how often the log viewer picks up new lines while its tab is open
+const logsRefreshInterval = 2 * time.Second
+
+// createLogsTab shows the recent log lines kept in memory by logbuffer.
+// Lines below the global log level are never logged, so they cannot be shown either.
+func (g *MainGUI) createLogsTab() fyne.CanvasObject {
+	var entries []logbuffer.Entry
+	minLevel := zerolog.TraceLevel
+
+	list := widget.NewList(
+		func() int { return len(entries) },
+		func() fyne.CanvasObject {
+			label := widget.NewLabel("")
+			label.TextStyle.Monospace = true
+			label.Truncation = fyne.TextTruncateEllipsis
+			return label
+		},
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			label := obj.(*widget.Label)
+			label.SetText(entries[id].Text)
+			label.Importance = logLevelImportance(entries[id].Level)
+			label.Refresh()
+		},
+	)
+
+	followCheck := widget.NewCheck("Follow", nil)
+	followCheck.SetChecked(true)
+
+	refresh := func() {
+		entries = logbuffer.Entries(minLevel)
+		list.Refresh()
+		if followCheck.Checked {
+			list.ScrollToBottom()
+		}
+	}
+
+	levelSelect := widget.NewSelect(configs.LogLevels, func(value string) {
+		if level, err := zerolog.ParseLevel(value); err == nil {
+			minLevel = level
+		}
+		refresh()
+	})
+	levelSelect.SetSelected(minLevel.String())
+
+	copyAllBtn := widget.NewButton("Copy All", func() {
+		lines := make([]string, len(entries))
+		for i, entry := range entries {
+			lines[i] = entry.Text
+		}
+		g.window.Clipboard().SetContent(strings.Join(lines, "\n"))
+	})
+
+	toolbar := container.NewHBox(
+		widget.NewLabel("Minimum Level:"),
+		levelSelect,
+		followCheck,
+		widget.NewButton("Refresh", refresh),
+		copyAllBtn,
+	)
+
+	go func() {
+		ticker := time.NewTicker(logsRefreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+			case <-g.ctx.Done():
+				return
+			}
+			runOnMain(func() {
+				if g.tabs == nil || g.tabs.Selected() == nil || g.tabs.Selected().Text != logsTabName {
+					return
+				}
+				refresh()
+			})
+		}
+	}()
+
+	return container.NewBorder(toolbar, nil, nil, nil, list)
+}
+
+// logLevelImportance highlights warnings and errors in the log viewer
+func logLevelImportance(level zerolog.Level) widget.Importance {
+	switch {
+	case level == zerolog.NoLevel:
+		return widget.MediumImportance
+	case level >= zerolog.ErrorLevel:
+		return widget.DangerImportance
+	case level == zerolog.WarnLevel:
+		return widget.WarningImportance
+	case level <= zerolog.DebugLevel:
+		return widget.LowImportance
+	}
+	return widget.MediumImportance
+}
@@ -1,11 +1,14 @@
 package gui
 
 import (
+	"context"
 	"os"
 	"os/exec"
+	"sync"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
 	"fyne.io/fyne/v2/widget"
 	"github.com/setavenger/blindbit-desktop/internal/controller"
 	"github.com/setavenger/blindbit-desktop/internal/storage"
@@ -17,7 +20,42 @@ type MainGUI struct {
 	window          fyne.Window
 	manager         *controller.Manager
 	tabs            *container.AppTabs
-	transactionList *widget.List // Reference to transaction list for refreshing
+	content         fyne.CanvasObject // tabs below the header with the oracle status
+	transactionList *widget.List      // Reference to transaction list for refreshing
+
+	// UTXO tab widgets, refreshed when a scan finishes
+	utxoList         *widget.List
+	utxoBalanceLabel *widget.Label
+	// filtered and sorted rows of utxoList, see utxoView
+	utxoView utxoView
+
+	// disabled while offline, see applyOfflineMode
+	sendBtn            *widget.Button
+	offlineItem        *fyne.MenuItem
+	broadcastPSBTItem  *fyne.MenuItem
+	broadcastRawTxItem *fyne.MenuItem
+
+	// rescan pause and resume, set up by the scanning tab
+	rescanControls *rescanControls
+
+	// sort order picked via the list headers, kept for the session
+	utxoSort tableSort
+	txSort   tableSort
+
+	// lists scrolled back to their stored offset, see RestoreScrollPositions
+	scrollRestored map[string]bool
+
+	// shown while a long catch up scan runs, nil once dismissed or caught up
+	initialSyncMu sync.Mutex
+	initialSync   *initialSyncScreen
+
+	// periodic updates of the tabs run until Cleanup cancels ctx
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// closed and replaced when the settings change the refresh intervals, see runPeriodically
+	intervalsMu      sync.Mutex
+	intervalsChanged chan struct{}
 }
 
 func NewMainGUI(
@@ -26,12 +64,21 @@ func NewMainGUI(
 	manager *controller.Manager,
 ) *MainGUI {
 	gui := &MainGUI{
-		app:     app,
-		window:  window,
-		manager: manager,
+		app:              app,
+		window:           window,
+		manager:          manager,
+		intervalsChanged: make(chan struct{}),
+		scrollRestored:   make(map[string]bool),
 	}
+	gui.ctx, gui.cancel = context.WithCancel(context.Background())
 
 	gui.setupTabs()
+	gui.setupMenu()
+
+	header := container.NewHBox(layout.NewSpacer(), gui.newOracleStatusIndicator())
+	gui.content = container.NewBorder(header, nil, nil, nil, gui.tabs)
+
+	go gui.showInitialSyncIfBehind()
 	return gui
 }
 
@@ -44,20 +91,48 @@ func (g *MainGUI) setupTabs() {
 		container.NewTabItem("Scanning", g.createScanningTab()),
 		container.NewTabItem("UTXOs", g.createUTXOsTab()),
 		container.NewTabItem("Settings", g.createSettingsTab()),
+		container.NewTabItem(logsTabName, g.createLogsTab()),
 	)
+	g.restoreActiveTab()
 }
 
 func (g *MainGUI) GetContent() fyne.CanvasObject {
-	return g.tabs
+	return g.content
+}
+
+// Cleanup stops the periodic updates of the tabs and remembers the scroll positions,
+// the GUI does not refresh afterwards. Calling it more than once is safe.
+func (g *MainGUI) Cleanup() {
+	if g.Running() {
+		g.saveScrollPositions()
+	}
+	g.cancel()
+
+	// dialogs stay on the window when its content is replaced
+	g.initialSyncMu.Lock()
+	screen := g.initialSync
+	g.initialSyncMu.Unlock()
+	if screen != nil {
+		screen.dialog.Hide()
+	}
+}
+
+// Running reports whether the tabs are still refreshed, i.e. Cleanup was not called
+func (g *MainGUI) Running() bool {
+	return g.ctx.Err() == nil
 }
 
 // CleanupAndExit exits the program with status 0
 // Before that it:
+// - stops the GUI updates, scanning and the background handlers
 // - saves the data to file
 func (g *MainGUI) CleanupAndExit() {
-	// Simple cleanup - avoid any operations that might cause issues during shutdown
-	// The app will handle most cleanup automatically
-	if err := storage.SavePlain(g.manager.DataDir, g.manager); err != nil {
+	g.Cleanup()
+	RemovePrintPages(g.manager.DataDir)
+	err := g.manager.Shutdown(controller.DefaultShutdownTimeout, func() error {
+		return storage.SavePlain(g.manager.DataDir, g.manager)
+	})
+	if err != nil {
 		logging.L.Err(err).Msg("error during shutdown")
 		os.Exit(1)
 	}
@@ -71,12 +146,16 @@ func (g *MainGUI) CleanupAndExit() {
 // Deprecated: Discoured use.
 // tends to have problems when application is called from the terminal
 func (g *MainGUI) restartApplication() error {
+	// Preserve original args (excluding the current process name; os.Args[0] is the path)
+	return g.restartWithArgs(os.Args[1:])
+}
+
+// restartWithArgs re-executes the current binary with args and exits the current process
+func (g *MainGUI) restartWithArgs(args []string) error {
 	exePath, err := os.Executable()
 	if err != nil {
 		return err
 	}
-	// Preserve original args (excluding the current process name; os.Args[0] is the path)
-	args := os.Args[1:]
 
 	cmd := exec.Command(exePath, args...)
 	cmd.Stdout = os.Stdout
@@ -0,0 +1,73 @@
+package gui
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/test"
+	"github.com/setavenger/blindbit-desktop/internal/controller"
+	"github.com/setavenger/blindbit-lib/types"
+	"github.com/setavenger/blindbit-lib/wallet"
+)
+
+const testMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+// newTestManager returns an offline manager with an empty signet wallet
+func newTestManager(t testing.TB) *controller.Manager {
+	t.Helper()
+	w, err := wallet.NewFromMnemonic(testMnemonic, types.NetworkSignet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := controller.NewManager()
+	m.Wallet = w
+	m.DataDir = t.TempDir()
+	m.Offline = true
+	return m
+}
+
+// waitForGoroutines waits until at most want goroutines are left and returns the last count
+func waitForGoroutines(want int) int {
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		n := runtime.NumGoroutine()
+		if n <= want || time.Now().After(deadline) {
+			return n
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestCleanupStopsTabUpdates(t *testing.T) {
+	a := test.NewApp()
+	defer a.Quit()
+	window := a.NewWindow("BlindBit Desktop")
+	m := newTestManager(t)
+
+	// settle the goroutines the test app starts itself
+	time.Sleep(100 * time.Millisecond)
+	before := runtime.NumGoroutine()
+
+	// the GUI is rebuilt e.g. when switching profiles, nothing may pile up
+	for i := 0; i < 5; i++ {
+		g := NewMainGUI(a, window, m)
+		window.SetContent(g.GetContent())
+		if runtime.NumGoroutine() <= before {
+			t.Fatal("expected the tabs to start their periodic updates")
+		}
+		g.Cleanup()
+	}
+
+	if after := waitForGoroutines(before); after > before {
+		t.Fatalf("%d goroutines left after Cleanup, started with %d", after, before)
+	}
+
+	err := m.Shutdown(time.Second, func() error { return nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if after := waitForGoroutines(before); after > before {
+		t.Fatalf("%d goroutines left after Shutdown, started with %d", after, before)
+	}
+}
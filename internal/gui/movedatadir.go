@@ -0,0 +1,136 @@
+package gui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/setavenger/blindbit-desktop/internal/configs"
+	"github.com/setavenger/blindbit-desktop/internal/controller"
+	"github.com/setavenger/blindbit-desktop/internal/logbuffer"
+	"github.com/setavenger/blindbit-desktop/internal/storage"
+	"github.com/setavenger/blindbit-lib/logging"
+	"github.com/setavenger/blindbit-lib/utils"
+)
+
+// prefDefaultDataDir is where the default data directory was moved to
+const prefDefaultDataDir = "datadir.default"
+
+// DefaultDataDir returns the data directory used without --datadir,
+// configs.DefaultDataDir unless it was moved with Move Data Directory
+func DefaultDataDir(app fyne.App) string {
+	if moved := app.Preferences().String(prefDefaultDataDir); moved != "" {
+		return moved
+	}
+	return configs.DefaultDataDir()
+}
+
+// showMoveDataDirDialog asks for the new location of the data directory
+func (g *MainGUI) showMoveDataDirDialog() {
+	current := g.manager.DataDir
+
+	targetEntry := widget.NewEntry()
+	targetEntry.SetPlaceHolder("/path/to/new/datadir")
+
+	browseBtn := widget.NewButton("Browse...", func() {
+		dialog.ShowFolderOpen(func(dir fyne.ListableURI, err error) {
+			if err != nil || dir == nil {
+				return
+			}
+			targetEntry.SetText(filepath.Join(dir.Path(), filepath.Base(current)))
+		}, g.window)
+	})
+
+	hint := widget.NewLabel("Wallet, backup and logs are copied and checked before the old directory is removed. " +
+		"BlindBit restarts from the new location afterwards.")
+	hint.Wrapping = fyne.TextWrapWord
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Current", widget.NewLabel(current)),
+		widget.NewFormItem("New Location", container.NewBorder(nil, nil, nil, browseBtn, targetEntry)),
+		widget.NewFormItem("", hint),
+	}
+
+	d := dialog.NewForm("Move Data Directory", "Move", "Cancel", items, func(ok bool) {
+		if !ok {
+			return
+		}
+		target := strings.TrimSpace(targetEntry.Text)
+		if target == "" {
+			dialog.ShowError(errors.New("new location is empty"), g.window)
+			return
+		}
+		g.moveDataDir(current, utils.ResolvePath(target))
+	}, g.window)
+	d.Resize(fyne.NewSize(640, d.MinSize().Height))
+	d.Show()
+}
+
+// moveDataDir stops the background work, moves the data directory and restarts from the new location.
+// If the move fails the workers are started again on the old directory.
+func (g *MainGUI) moveDataDir(src, dst string) {
+	progress := dialog.NewCustomWithoutButtons(
+		"Moving Data Directory", widget.NewProgressBarInfinite(), g.window,
+	)
+	progress.Show()
+
+	go func() {
+		err := g.manager.Shutdown(controller.DefaultShutdownTimeout, func() error {
+			return storage.SavePlain(src, g.manager)
+		})
+		if err == nil {
+			// the log file must not change while it is copied
+			if err = logging.DisableFileLogging(); err == nil {
+				logbuffer.Attach()
+				err = storage.MoveDataDir(src, dst)
+			}
+		}
+
+		if err != nil {
+			logging.L.Err(err).Str("from", src).Str("to", dst).Msg("failed to move data directory")
+			g.applyLogSettings()
+			if reconnectErr := g.manager.ReconnectOracle(controller.DefaultShutdownTimeout); reconnectErr != nil &&
+				!errors.Is(reconnectErr, controller.ErrOracleNetworkMismatch) {
+				logging.L.Err(reconnectErr).Msg("failed to restart scanning after failed move")
+			}
+			runOnMain(func() {
+				progress.Hide()
+				dialog.ShowError(err, g.window)
+			})
+			return
+		}
+
+		g.manager.DataDir = dst
+		replaceProfile(g.app, src, dst)
+		if src == DefaultDataDir(g.app) {
+			g.app.Preferences().SetString(prefDefaultDataDir, dst)
+		}
+
+		runOnMain(func() {
+			progress.Hide()
+			if err := g.restartWithArgs(argsWithDataDir(os.Args[1:], dst)); err != nil {
+				dialog.ShowError(fmt.Errorf(
+					"data directory moved to %s, but restarting failed, start BlindBit with --datadir %s: %w",
+					dst, dst, err,
+				), g.window)
+			}
+		})
+	}()
+}
+
+// replaceProfile swaps a moved data directory in the known profiles
+func replaceProfile(app fyne.App, from, to string) {
+	prefs := app.Preferences()
+	profiles := slices.DeleteFunc(prefs.StringList(prefProfiles), func(p string) bool {
+		return p == from || p == to
+	})
+	prefs.SetStringList(prefProfiles, append(profiles, to))
+}
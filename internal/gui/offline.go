@@ -0,0 +1,52 @@
+package gui
+
+import (
+	"fyne.io/fyne/v2"
+
+	"github.com/setavenger/blindbit-desktop/internal/storage"
+	"github.com/setavenger/blindbit-lib/logging"
+)
+
+// toggleOfflineMode turns offline mode on or off and keeps it for the next start.
+// Going online waits for the stopped watcher, so it runs off the UI thread.
+func (g *MainGUI) toggleOfflineMode() {
+	offline := !g.manager.IsOffline()
+	go func() {
+		g.manager.SetOffline(offline)
+		if err := storage.SavePlain(g.manager.DataDir, g.manager); err != nil {
+			logging.L.Err(err).Msg("failed to save offline mode")
+		}
+		runOnMain(g.applyOfflineMode)
+	}()
+}
+
+// applyOfflineMode disables sending, broadcasting and resuming a rescan while offline and ticks the menu item
+func (g *MainGUI) applyOfflineMode() {
+	offline := g.manager.IsOffline()
+	g.rescanControls.update()
+
+	if g.sendBtn != nil {
+		if offline {
+			g.sendBtn.Disable()
+		} else {
+			g.sendBtn.Enable()
+		}
+	}
+	if g.offlineItem != nil {
+		g.offlineItem.Checked = offline
+		g.broadcastPSBTItem.Disabled = offline
+		g.broadcastRawTxItem.Disabled = offline
+		if menu := g.window.MainMenu(); menu != nil {
+			menu.Refresh()
+		}
+	}
+}
+
+// newOfflineMenuItems returns the menu items for offline mode and broadcasting a signed PSBT or raw transaction,
+// the broadcast items are disabled while offline
+func (g *MainGUI) newOfflineMenuItems() (offline, broadcastPSBT, broadcastRawTx *fyne.MenuItem) {
+	g.offlineItem = fyne.NewMenuItem("Offline Mode", g.toggleOfflineMode)
+	g.broadcastPSBTItem = fyne.NewMenuItem("Broadcast Signed PSBT...", g.showBroadcastPSBTDialog)
+	g.broadcastRawTxItem = fyne.NewMenuItem("Broadcast Raw Tx...", g.showBroadcastRawTxDialog)
+	return g.offlineItem, g.broadcastPSBTItem, g.broadcastRawTxItem
+}
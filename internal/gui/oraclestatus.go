@@ -0,0 +1,102 @@
+package gui
+
+import (
+	"fmt"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/setavenger/blindbit-desktop/internal/controller"
+)
+
+// oracleStatusInterval is how often the indicator picks up the oracle health.
+// The health itself comes from the chain tip requests of the dashboard and scanning tabs.
+const oracleStatusInterval = 5 * time.Second
+
+// newOracleStatusIndicator shows the oracle connectivity, clicking it shows the last error
+func (g *MainGUI) newOracleStatusIndicator() fyne.CanvasObject {
+	indicator := widget.NewButton("", g.showOracleHealth)
+
+	update := func() {
+		if g.manager.IsOffline() {
+			indicator.SetText("● Offline")
+			indicator.Importance = widget.LowImportance
+			indicator.Refresh()
+			return
+		}
+		if g.manager.OracleNetworkError() != nil {
+			indicator.SetText("● Oracle wrong network")
+			indicator.Importance = widget.DangerImportance
+			indicator.Refresh()
+			return
+		}
+		status := g.manager.OracleHealth().Status
+		indicator.SetText("● Oracle " + status.String())
+		indicator.Importance = oracleStatusImportance(status)
+		indicator.Refresh()
+	}
+	update()
+
+	go func() {
+		ticker := time.NewTicker(oracleStatusInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+			case <-g.ctx.Done():
+				return
+			}
+			runOnMain(update)
+		}
+	}()
+
+	return indicator
+}
+
+// oracleStatusImportance colours the indicator green, amber or red
+func oracleStatusImportance(status controller.OracleStatus) widget.Importance {
+	switch status {
+	case controller.OracleConnected:
+		return widget.SuccessImportance
+	case controller.OracleRetrying:
+		return widget.WarningImportance
+	case controller.OracleDown:
+		return widget.DangerImportance
+	}
+	return widget.LowImportance
+}
+
+func (g *MainGUI) showOracleHealth() {
+	health := g.manager.OracleHealth()
+
+	lastSuccess := "never"
+	if !health.LastSuccess.IsZero() {
+		lastSuccess = health.LastSuccess.Format("2006-01-02 15:04:05")
+	}
+
+	lastError := "none"
+	if err := g.manager.OracleNetworkError(); err != nil {
+		lastError = fmt.Sprintf("%s, scanning is disabled until the oracle is changed", err)
+	} else if health.LastError != nil {
+		lastError = fmt.Sprintf(
+			"%s (%s)", health.LastError, health.LastErrorAt.Format("2006-01-02 15:04:05"),
+		)
+	}
+
+	errorLabel := widget.NewLabel(lastError)
+	errorLabel.Wrapping = fyne.TextWrapWord
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Address", widget.NewLabel(g.manager.OracleAddress)),
+		widget.NewFormItem("Status", widget.NewLabel(health.Status.String())),
+		widget.NewFormItem("Last Success", widget.NewLabel(lastSuccess)),
+		widget.NewFormItem("Failed Requests", widget.NewLabel(fmt.Sprintf("%d", health.Failures))),
+		widget.NewFormItem("Last Error", errorLabel),
+	}
+
+	d := dialog.NewCustom("Oracle Connection", "Close", widget.NewForm(items...), g.window)
+	d.Resize(fyne.NewSize(520, d.MinSize().Height))
+	d.Show()
+}
@@ -1,12 +1,13 @@
 package gui
 
 import (
+	"fmt"
 	"sync"
-	"time"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/widget"
+	"github.com/setavenger/blindbit-desktop/internal/controller"
 	"github.com/setavenger/blindbit-lib/wallet"
 )
 
@@ -18,20 +19,35 @@ func (g *MainGUI) createOverviewTab() fyne.CanvasObject {
 	balanceLabel := widget.NewLabel("0 sats")
 	balanceLabel.TextStyle.Bold = true
 
+	// Immature and unconfirmed parts, only shown when there are any
+	breakdownLabel := widget.NewLabel("")
+	breakdownLabel.Hide()
+
+	// UTXO counts, many small UTXOs are a hint to consolidate
+	utxoStatsLabel := widget.NewLabel("")
+	utxoStatsLabel.Importance = widget.LowImportance
+
 	// Update balance from unspent UTXOs
 	updateBalance := func() {
-		unspentUTXOs := g.manager.GetUnspentUTXOsSorted()
-		var total uint64
-		for _, utxo := range unspentUTXOs {
-			total += utxo.Amount
+		breakdown := g.manager.GetBalanceBreakdown()
+		balanceLabel.SetText(FormatSatoshiUint64(breakdown.Balance()))
+
+		if text := FormatBalanceBreakdown(breakdown); text != "" {
+			breakdownLabel.SetText(text)
+			breakdownLabel.Show()
+		} else {
+			breakdownLabel.Hide()
 		}
-		balanceLabel.SetText(FormatSatoshiUint64(total))
+
+		utxoStatsLabel.SetText(FormatUTXOStats(g.manager.GetUTXOStats()))
 	}
 	updateBalance()
 
 	balanceSection := container.NewVBox(
 		balanceTitleLabel,
 		balanceLabel,
+		breakdownLabel,
+		utxoStatsLabel,
 	)
 
 	// --- Scanning status section ---
@@ -39,18 +55,34 @@ func (g *MainGUI) createOverviewTab() fyne.CanvasObject {
 	scanTitleLabel.TextStyle.Bold = true
 
 	currentScanLabel := widget.NewLabel(
-		"Scanned Height: " + FormatHeightUint64(g.manager.Wallet.LastScanHeight),
+		"Scanned Height: " + FormatHeightUint64(g.manager.ScanHeight()),
 	)
-	chainTipLabel := widget.NewLabel("Chain Tip: N/A")
-
-	if currentHeight, err := g.manager.GetCurrentHeight(); err == nil {
-		chainTipLabel.SetText("Chain Tip: " + FormatHeight(currentHeight))
+	chainTipLabel := widget.NewLabel("Chain Tip: Connecting...")
+	etaLabel := widget.NewLabel("Time Remaining: —")
+
+	// the oracle the client is connected to, follows a changed address once reconnected
+	oracleLabel := widget.NewLabel("")
+	oracleLabel.Truncation = fyne.TextTruncateEllipsis
+	updateOracleLabel := func() {
+		oracleLabel.SetText(formatActiveOracle(g.manager))
 	}
+	updateOracleLabel()
+
+	// the chain tip comes from the oracle, don't hold up building the tab
+	go func() {
+		if currentHeight, err := g.manager.GetCurrentHeight(); err == nil {
+			runOnMain(func() {
+				setChainTipLabels(g.manager, chainTipLabel, etaLabel, currentHeight, nil)
+			})
+		}
+	}()
 
 	scanSection := container.NewVBox(
 		scanTitleLabel,
 		currentScanLabel,
 		chainTipLabel,
+		etaLabel,
+		oracleLabel,
 	)
 
 	// --- Recent transactions section ---
@@ -74,7 +106,7 @@ func (g *MainGUI) createOverviewTab() fyne.CanvasObject {
 	// Pre-compute display order (see sortedTransactionHistory). Updated on each
 	// refresh tick so updateItem can index into it.
 	buildSortedHistory := func() []*wallet.TxItem {
-		return sortedTransactionHistory(g.manager.TransactionHistory)
+		return sortedTransactionHistory(g.manager.GetTransactionHistory())
 	}
 	var mu sync.RWMutex
 	orderedHistory := buildSortedHistory()
@@ -103,17 +135,33 @@ func (g *MainGUI) createOverviewTab() fyne.CanvasObject {
 			mu.RUnlock()
 
 			if id < historyLen && tx != nil {
-				c := obj.(*fyne.Container)
+				row := obj.(*contextRow)
+				row.menu = func() *fyne.Menu { return g.txRowMenu(tx) }
+				c := row.content.(*fyne.Container)
 
 				txidLabel := c.Objects[0].(*widget.Label)
 				heightLabel := c.Objects[1].(*widget.Label)
 				amountLabel := c.Objects[2].(*widget.Label)
 				statusLabel := c.Objects[3].(*widget.Label)
 
-				formatTxRowLabels(txidLabel, heightLabel, amountLabel, statusLabel, tx)
+				formatTxRowLabels(txidLabel, heightLabel, amountLabel, statusLabel, tx,
+					g.manager.CachedChainTip(), g.manager.ConfirmationTarget)
 			}
 		},
 	)
+	// Click a transaction for its details, right click to copy the txid
+	recentTxList.OnSelected = func(id widget.ListItemID) {
+		recentTxList.Unselect(id)
+		mu.RLock()
+		var tx *wallet.TxItem
+		if id < len(orderedHistory) {
+			tx = orderedHistory[id]
+		}
+		mu.RUnlock()
+		if tx != nil {
+			g.showTransactionHistoryDetails(tx)
+		}
+	}
 	recentTxScroll := container.NewScroll(recentTxList)
 	recentTxScroll.SetMinSize(fyne.NewSize(440, 200))
 
@@ -131,24 +179,26 @@ func (g *MainGUI) createOverviewTab() fyne.CanvasObject {
 	)
 
 	// --- Periodic updates ---
-	go func() {
-		ticker := time.NewTicker(10 * time.Second)
-		defer ticker.Stop()
-		for range ticker.C {
-			updateBalance()
-			newHistory := buildSortedHistory()
-			mu.Lock()
-			orderedHistory = newHistory
-			mu.Unlock()
-			recentTxList.Refresh()
-			currentScanLabel.SetText(
-				"Scanned Height: " + FormatHeightUint64(g.manager.Wallet.LastScanHeight),
-			)
-			if currentHeight, err := g.manager.GetCurrentHeight(); err == nil {
-				chainTipLabel.SetText("Chain Tip: " + FormatHeight(currentHeight))
+	go g.runPeriodically(g.chainTipRefreshInterval, func() {
+		updateBalance()
+		updateOracleLabel()
+		newHistory := buildSortedHistory()
+		mu.Lock()
+		orderedHistory = newHistory
+		mu.Unlock()
+		currentScanLabel.SetText(
+			"Scanned Height: " + FormatHeightUint64(g.manager.ScanHeight()),
+		)
+		previousTip := g.manager.CachedChainTip()
+		if currentHeight, err := g.manager.GetCurrentHeight(); err == nil {
+			setChainTipLabels(g.manager, chainTipLabel, etaLabel, currentHeight, nil)
+			// confirmation counts move with every new block
+			if currentHeight != previousTip && g.transactionList != nil {
+				g.transactionList.Refresh()
 			}
 		}
-	}()
+		recentTxList.Refresh()
+	})
 
 	// --- Main layout ---
 	content := container.NewBorder(
@@ -166,3 +216,15 @@ func (g *MainGUI) createOverviewTab() fyne.CanvasObject {
 
 	return content
 }
+
+// formatActiveOracle shows the oracle in use and a saved address which is not applied yet
+func formatActiveOracle(manager *controller.Manager) string {
+	active := manager.ActiveOracleAddress()
+	if active == "" {
+		return "Oracle: not connected"
+	}
+	if manager.OracleAddress != active {
+		return fmt.Sprintf("Oracle: %s (switching to %s)", active, manager.OracleAddress)
+	}
+	return "Oracle: " + active
+}
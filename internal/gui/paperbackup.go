@@ -0,0 +1,183 @@
+package gui
+
+import (
+	"encoding/base64"
+	"fmt"
+	"html"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/setavenger/blindbit-lib/logging"
+	"github.com/setavenger/blindbit-lib/types"
+	"github.com/skip2/go-qrcode"
+)
+
+// printPageLifetime is how long the printable page stays on disk.
+// The browser has loaded it by then and the seed should not linger on disk.
+const printPageLifetime = 2 * time.Minute
+
+// printPageDir is the directory in the data dir the printable pages are written to,
+// only the user may enter it
+const printPageDir = "print"
+
+// printPagePattern names the printable pages in printPageDir, see RemovePrintPages
+const printPagePattern = "blindbit-backup-*.html"
+
+// printPageDirPath creates printPageDir in dataDir, an existing one is restricted to the user
+func printPageDirPath(dataDir string) (string, error) {
+	dir := filepath.Join(dataDir, printPageDir)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+	if err := os.Chmod(dir, 0700); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// RemovePrintPages deletes printable backup pages left in dataDir.
+// Called on start and on quit, for pages whose timed removal did not run before the app exited.
+func RemovePrintPages(dataDir string) {
+	paths, err := filepath.Glob(filepath.Join(dataDir, printPageDir, printPagePattern))
+	if err != nil {
+		logging.L.Err(err).Msg("failed to list printable backups")
+		return
+	}
+	for _, path := range paths {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			logging.L.Err(err).Str("path", path).Msg("failed to remove printable backup")
+			continue
+		}
+		logging.L.Info().Str("path", path).Msg("removed printable backup")
+	}
+}
+
+const paperBackupWarning = "Anyone with these words or this QR code can spend your funds. " +
+	"Store the paper offline and never photograph or upload it. " +
+	"A wallet passphrase, if you use one, is not part of this backup, keep it separately."
+
+// showPaperBackup shows the mnemonic as numbered word list and QR code for cold storage
+// The printable page is written to dataDir.
+func showPaperBackup(app fyne.App, window fyne.Window, dataDir, mnemonic string, network types.Network) {
+	qrPNG, err := qrcode.Encode(mnemonic, qrcode.Medium, 256)
+	if err != nil {
+		logging.L.Err(err).Msg("failed to encode mnemonic QR code")
+		dialog.ShowError(fmt.Errorf("failed to generate QR code: %v", err), window)
+		return
+	}
+
+	warning := widget.NewLabel(paperBackupWarning)
+	warning.Importance = widget.DangerImportance
+	warning.TextStyle.Bold = true
+	warning.Wrapping = fyne.TextWrapWord
+
+	words := widget.NewLabel(formatMnemonicGrid(mnemonic))
+	words.TextStyle.Monospace = true
+
+	qrImage := canvas.NewImageFromResource(fyne.NewStaticResource("seed-qr.png", qrPNG))
+	qrImage.FillMode = canvas.ImageFillOriginal
+	qrImage.SetMinSize(fyne.NewSize(256, 256))
+
+	printBtn := widget.NewButton("Print...", func() {
+		if err := printPaperBackup(app, dataDir, mnemonic, network, qrPNG); err != nil {
+			logging.L.Err(err).Msg("failed to open printable backup")
+			dialog.ShowError(err, window)
+		}
+	})
+
+	content := container.NewVBox(
+		warning,
+		widget.NewSeparator(),
+		container.NewHBox(words, qrImage),
+		widget.NewLabel(fmt.Sprintf(
+			"Print opens the page in your browser. It is written to a file in the wallet's data folder "+
+				"and deleted after %d minutes or when the app quits, whichever comes first.",
+			int(printPageLifetime.Minutes()),
+		)),
+		container.NewHBox(printBtn),
+	)
+
+	dialog.ShowCustom("Paper Backup", "Close", content, window)
+}
+
+// printPaperBackup writes a printable page and opens it in the browser, which brings up the print dialog.
+// The page is only readable by the user, it is kept in printPageDir of dataDir instead of the shared temp dir.
+func printPaperBackup(app fyne.App, dataDir, mnemonic string, network types.Network, qrPNG []byte) error {
+	dir, err := printPageDirPath(dataDir)
+	if err != nil {
+		return fmt.Errorf("failed to create printable backup: %w", err)
+	}
+	file, err := os.CreateTemp(dir, printPagePattern)
+	if err != nil {
+		return fmt.Errorf("failed to create printable backup: %w", err)
+	}
+	path := file.Name()
+
+	_, err = file.WriteString(paperBackupHTML(mnemonic, network, qrPNG))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return fmt.Errorf("failed to write printable backup: %w", err)
+	}
+
+	time.AfterFunc(printPageLifetime, func() {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			logging.L.Err(err).Str("path", path).Msg("failed to remove printable backup")
+		}
+	})
+
+	if err = app.OpenURL(&url.URL{Scheme: "file", Path: path}); err != nil {
+		return fmt.Errorf("failed to open printable backup: %w", err)
+	}
+	return nil
+}
+
+func paperBackupHTML(mnemonic string, network types.Network, qrPNG []byte) string {
+	var words strings.Builder
+	for i, word := range strings.Fields(mnemonic) {
+		fmt.Fprintf(&words, "<li><span>%d.</span> %s</li>\n", i+1, html.EscapeString(word))
+	}
+
+	return fmt.Sprintf(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>BlindBit Wallet Backup</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+.warning { border: 3px solid #c00; padding: 1em; font-weight: bold; color: #c00; }
+ol { columns: 3; list-style: none; padding: 0; font-family: monospace; font-size: 1.2em; }
+li { margin: 0.4em 0; }
+li span { display: inline-block; width: 2.5em; color: #666; }
+</style>
+</head>
+<body onload="window.print()">
+<h1>BlindBit Wallet Backup</h1>
+<p>Network: %s &mdash; Created: %s</p>
+<p class="warning">%s</p>
+<h2>Recovery Phrase</h2>
+<ol>
+%s</ol>
+<h2>Recovery Phrase QR Code</h2>
+<img src="data:image/png;base64,%s" width="256" height="256" alt="recovery phrase QR code">
+</body>
+</html>
+`,
+		html.EscapeString(string(network)),
+		time.Now().Format("2006-01-02"),
+		html.EscapeString(paperBackupWarning),
+		words.String(),
+		base64.StdEncoding.EncodeToString(qrPNG),
+	)
+}
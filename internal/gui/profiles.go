@@ -0,0 +1,152 @@
+package gui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/setavenger/blindbit-lib/logging"
+	"github.com/setavenger/blindbit-lib/utils"
+)
+
+// prefProfiles lists the data directories which were opened before, each one is a wallet profile
+const prefProfiles = "profiles.datadirs"
+
+// RememberProfile adds dataDir to the known wallet profiles
+func RememberProfile(app fyne.App, dataDir string) {
+	prefs := app.Preferences()
+	profiles := prefs.StringList(prefProfiles)
+	if slices.Contains(profiles, dataDir) {
+		return
+	}
+	prefs.SetStringList(prefProfiles, append(profiles, dataDir))
+}
+
+// forgetProfile removes dataDir from the known profiles, the directory itself is left untouched
+func forgetProfile(app fyne.App, dataDir string) {
+	prefs := app.Preferences()
+	profiles := slices.DeleteFunc(prefs.StringList(prefProfiles), func(p string) bool {
+		return p == dataDir
+	})
+	prefs.SetStringList(prefProfiles, profiles)
+}
+
+func (g *MainGUI) setupMenu() {
+	offlineItem, broadcastPSBTItem, broadcastRawTxItem := g.newOfflineMenuItems()
+	walletMenu := fyne.NewMenu("Wallet",
+		fyne.NewMenuItem("Account Info...", g.showAccountInfo),
+		fyne.NewMenuItem("Import from blindbit-scan...", g.showScanImportDialog),
+		broadcastPSBTItem,
+		broadcastRawTxItem,
+		fyne.NewMenuItem("Switch Wallet...", g.showSwitchWalletDialog),
+		fyne.NewMenuItem("Move Data Directory...", g.showMoveDataDirDialog),
+		fyne.NewMenuItemSeparator(),
+		offlineItem,
+	)
+	g.window.SetMainMenu(fyne.NewMainMenu(walletMenu))
+	g.applyOfflineMode()
+}
+
+// showSwitchWalletDialog lists the known profiles and lets the user open another data directory.
+// Switching relaunches the app with a different --datadir.
+func (g *MainGUI) showSwitchWalletDialog() {
+	profiles := g.app.Preferences().StringList(prefProfiles)
+
+	var d dialog.Dialog
+
+	profileList := container.NewVBox()
+	for _, profile := range profiles {
+		if profile == g.manager.DataDir {
+			current := widget.NewLabel(profile + " (current)")
+			current.TextStyle.Bold = true
+			profileList.Add(current)
+			continue
+		}
+
+		status := ""
+		if _, err := os.Stat(filepath.Join(profile, "wallet.dat")); err != nil {
+			status = " (no wallet yet)"
+		}
+
+		openBtn := widget.NewButton("Open", func() {
+			d.Hide()
+			g.switchToProfile(profile)
+		})
+		forgetBtn := widget.NewButton("Forget", func() {
+			forgetProfile(g.app, profile)
+			d.Hide()
+			g.showSwitchWalletDialog()
+		})
+		profileList.Add(container.NewBorder(
+			nil, nil, nil,
+			container.NewHBox(openBtn, forgetBtn),
+			widget.NewLabel(profile+status),
+		))
+	}
+
+	newDirEntry := widget.NewEntry()
+	newDirEntry.SetPlaceHolder("/path/to/datadir")
+	newBtn := widget.NewButton("Open", func() {
+		dataDir := strings.TrimSpace(newDirEntry.Text)
+		if dataDir == "" {
+			dialog.ShowError(fmt.Errorf("data directory is empty"), g.window)
+			return
+		}
+		d.Hide()
+		// the setup wizard runs if the directory holds no wallet
+		g.switchToProfile(utils.ResolvePath(dataDir))
+	})
+
+	content := container.NewVBox(
+		widget.NewLabel("Wallet profiles (one per data directory):"),
+		profileList,
+		widget.NewSeparator(),
+		widget.NewLabel("Open or create a wallet in another data directory:"),
+		container.NewBorder(nil, nil, nil, newBtn, newDirEntry),
+	)
+
+	d = dialog.NewCustom("Switch Wallet", "Close", content, g.window)
+	d.Resize(fyne.NewSize(600, 300))
+	d.Show()
+}
+
+func (g *MainGUI) switchToProfile(dataDir string) {
+	dialog.ShowConfirm(
+		"Switch Wallet",
+		fmt.Sprintf("BlindBit will restart with the wallet in\n%s\n\nContinue?", dataDir),
+		func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+			logging.L.Info().Str("datadir", dataDir).Msg("switching wallet profile")
+			if err := g.restartWithArgs(argsWithDataDir(os.Args[1:], dataDir)); err != nil {
+				dialog.ShowError(fmt.Errorf("failed to switch wallet: %v", err), g.window)
+			}
+		},
+		g.window,
+	)
+}
+
+// argsWithDataDir replaces --datadir in args.
+// --network is dropped as well, it belongs to the wallet which is left.
+func argsWithDataDir(args []string, dataDir string) []string {
+	var out []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--datadir" || arg == "--network":
+			i++ // skip the value
+		case strings.HasPrefix(arg, "--datadir=") || strings.HasPrefix(arg, "--network="):
+		default:
+			out = append(out, arg)
+		}
+	}
+	return append(out, "--datadir", dataDir)
+}
@@ -0,0 +1,118 @@
+package gui
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/setavenger/blindbit-desktop/internal/controller"
+	"github.com/setavenger/blindbit-lib/logging"
+	"github.com/setavenger/blindbit-lib/wallet"
+)
+
+// showBroadcastPSBTDialog loads a signed PSBT, e.g. one saved with Save PSBT in the transaction preview,
+// and broadcasts it
+func (g *MainGUI) showBroadcastPSBTDialog() {
+	openDialog := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, g.window)
+			return
+		}
+		if reader == nil {
+			// cancelled
+			return
+		}
+		defer reader.Close()
+
+		data, err := io.ReadAll(reader)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("failed to read PSBT: %v", err), g.window)
+			return
+		}
+		packet, err := controller.ParsePSBT(data)
+		if err != nil {
+			dialog.ShowError(err, g.window)
+			return
+		}
+		tx, err := g.manager.FinalizePSBT(packet)
+		if err != nil {
+			dialog.ShowError(err, g.window)
+			return
+		}
+		g.confirmSignedPSBT(tx)
+	}, g.window)
+	openDialog.Show()
+}
+
+// externalOutputs returns the outputs of tx which do not pay back to the wallet as recipients
+func externalOutputs(manager *controller.Manager, tx *wire.MsgTx) []wallet.Recipient {
+	decoded, err := manager.DecodeTransaction(tx)
+	if err != nil {
+		logging.L.Err(err).Msg("failed to decode signed transaction")
+		return nil
+	}
+	var recipients []wallet.Recipient
+	for _, out := range decoded.Outputs {
+		if out.Own {
+			continue
+		}
+		address := out.Address
+		if address == "" {
+			address = "script " + out.Script
+		}
+		recipients = append(recipients, &wallet.RecipientImpl{Address: address, Amount: out.Amount})
+	}
+	return recipients
+}
+
+// confirmSignedPSBT shows the finalized transaction before it is broadcast
+func (g *MainGUI) confirmSignedPSBT(tx *wire.MsgTx) {
+	txID := controller.GetTxID(tx)
+
+	var inputSum uint64
+	spent := make(map[[36]byte]bool, len(tx.TxIn))
+	for _, txIn := range tx.TxIn {
+		spent[controller.OutpointKey(txIn.PreviousOutPoint)] = true
+	}
+	for _, utxo := range g.manager.GetUTXOs() {
+		if spent[utxo.SerialiseToOutpoint()] {
+			inputSum += utxo.Amount
+		}
+	}
+	var outputSum uint64
+	for _, txOut := range tx.TxOut {
+		outputSum += uint64(txOut.Value)
+	}
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("TxID", widget.NewLabel(hex.EncodeToString(txID[:]))),
+		widget.NewFormItem("Inputs", widget.NewLabel(fmt.Sprintf("%d (%s)", len(tx.TxIn), FormatSatoshiUint64(inputSum)))),
+		widget.NewFormItem("Outputs", widget.NewLabel(fmt.Sprintf("%d (%s)", len(tx.TxOut), FormatSatoshiUint64(outputSum)))),
+		widget.NewFormItem("Fee", widget.NewLabel(FormatSatoshiUint64(inputSum-outputSum))),
+	}
+	if g.manager.HasTransaction(txID) {
+		items = append(items, widget.NewFormItem("", widget.NewLabel("This transaction has already been broadcast.")))
+	}
+
+	content := container.NewVBox(
+		widget.NewLabel("The PSBT is fully signed and ready to broadcast."),
+	)
+	if destinations := g.newDestinationRows(externalOutputs(g.manager, tx)); destinations != nil {
+		content.Add(destinations)
+	}
+	content.Add(widget.NewForm(items...))
+	d := dialog.NewCustomConfirm("Broadcast Signed PSBT", "Broadcast", "Cancel", content, func(ok bool) {
+		if !ok {
+			return
+		}
+		g.broadcastTransaction(&wallet.TxMetadata{Tx: tx}, nil, nil)
+	}, g.window)
+	d.Resize(fyne.NewSize(640, d.MinSize().Height))
+	d.Show()
+}
@@ -0,0 +1,204 @@
+package gui
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/setavenger/blindbit-desktop/internal/controller"
+	"github.com/setavenger/blindbit-lib/logging"
+	"github.com/setavenger/blindbit-lib/types"
+	"github.com/setavenger/blindbit-lib/wallet"
+)
+
+// parseRawTx validates pasted transaction hex before anything is sent to the backend
+func parseRawTx(text string) (*wire.MsgTx, error) {
+	text = strings.Join(strings.Fields(text), "")
+	if text == "" {
+		return nil, fmt.Errorf("paste a signed transaction in hex")
+	}
+	if len(text)%2 != 0 {
+		return nil, fmt.Errorf("invalid transaction hex: odd number of characters")
+	}
+	raw, err := hex.DecodeString(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid transaction hex: %w", err)
+	}
+	tx := wire.NewMsgTx(wire.TxVersion)
+	if err := tx.Deserialize(bytes.NewReader(raw)); err != nil {
+		return nil, fmt.Errorf("invalid transaction: %w", err)
+	}
+	if len(tx.TxIn) == 0 || len(tx.TxOut) == 0 {
+		return nil, fmt.Errorf("transaction has no inputs or no outputs")
+	}
+	// trailing bytes would be silently dropped by the backend, reject them instead
+	if tx.SerializeSize() != len(raw) {
+		return nil, fmt.Errorf("transaction hex has %d unexpected trailing bytes", len(raw)-tx.SerializeSize())
+	}
+	return tx, nil
+}
+
+// walletOutpoints maps the outpoint keys of the wallet's UTXOs to their amounts
+func (g *MainGUI) walletOutpoints() map[[36]byte]uint64 {
+	outpoints := make(map[[36]byte]uint64)
+	for _, utxo := range g.manager.GetUTXOs() {
+		outpoints[utxo.SerialiseToOutpoint()] = utxo.Amount
+	}
+	return outpoints
+}
+
+// transactionFee returns the fee of tx, known only if every input spends a wallet UTXO
+func (g *MainGUI) transactionFee(tx *wire.MsgTx) (fee uint64, known bool) {
+	utxos := g.walletOutpoints()
+	var inputSum uint64
+	for _, txIn := range tx.TxIn {
+		amount, ok := utxos[controller.OutpointKey(txIn.PreviousOutPoint)]
+		if !ok {
+			return 0, false
+		}
+		inputSum += amount
+	}
+	var outputSum uint64
+	for _, txOut := range tx.TxOut {
+		outputSum += uint64(txOut.Value)
+	}
+	return controller.CalculateTxFee(inputSum, outputSum), true
+}
+
+// spendsWalletCoins reports whether any input of tx spends a wallet UTXO
+func (g *MainGUI) spendsWalletCoins(tx *wire.MsgTx) bool {
+	utxos := g.walletOutpoints()
+	for _, txIn := range tx.TxIn {
+		if _, ok := utxos[controller.OutpointKey(txIn.PreviousOutPoint)]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// rawTxOutputAddress returns the address an output pays to, the script for non-standard outputs
+func rawTxOutputAddress(txOut *wire.TxOut, network types.Network) string {
+	_, addrs, _, err := txscript.ExtractPkScriptAddrs(txOut.PkScript, types.NetworkParams[network])
+	if err != nil || len(addrs) != 1 {
+		return "script " + hex.EncodeToString(txOut.PkScript)
+	}
+	return addrs[0].EncodeAddress()
+}
+
+// showBroadcastRawTxDialog takes a signed transaction in hex, e.g. from an air-gapped signer
+// or a stuck transaction to rebroadcast, and previews it before broadcasting
+func (g *MainGUI) showBroadcastRawTxDialog() {
+	hexEntry := widget.NewMultiLineEntry()
+	hexEntry.SetPlaceHolder("Signed transaction hex")
+	hexEntry.Wrapping = fyne.TextWrapBreak
+	hexEntry.SetMinRowsVisible(8)
+
+	errorLabel := widget.NewLabel("")
+	errorLabel.Wrapping = fyne.TextWrapWord
+	errorLabel.Hide()
+
+	var d dialog.Dialog
+	previewBtn := widget.NewButton("Preview", func() {
+		tx, err := parseRawTx(hexEntry.Text)
+		if err != nil {
+			errorLabel.SetText(err.Error())
+			errorLabel.Show()
+			return
+		}
+		d.Hide()
+		g.confirmRawTransaction(tx)
+	})
+	previewBtn.Importance = widget.HighImportance
+	hexEntry.OnChanged = func(string) { errorLabel.Hide() }
+
+	content := container.NewVBox(
+		widget.NewLabel("Paste a fully signed transaction to broadcast it through the configured backend."),
+		hexEntry,
+		errorLabel,
+		previewBtn,
+	)
+	d = dialog.NewCustom("Broadcast Raw Tx", "Cancel", content, g.window)
+	d.Resize(fyne.NewSize(640, d.MinSize().Height))
+	d.Show()
+}
+
+// confirmRawTransaction shows the inputs, outputs and fee of a pasted transaction before it is broadcast.
+// Transactions which spend wallet coins are recorded as sent, anything else is only broadcast.
+func (g *MainGUI) confirmRawTransaction(tx *wire.MsgTx) {
+	txID := controller.GetTxID(tx)
+
+	inputs := container.NewVBox()
+	for _, txIn := range tx.TxIn {
+		inputs.Add(widget.NewLabel(txIn.PreviousOutPoint.String()))
+	}
+	outputs := container.NewVBox()
+	for _, txOut := range tx.TxOut {
+		outputs.Add(widget.NewLabel(fmt.Sprintf(
+			"%s  %s",
+			FormatSatoshi(txOut.Value),
+			rawTxOutputAddress(txOut, g.manager.GetNetwork()),
+		)))
+	}
+
+	feeText := "unknown, spends coins outside this wallet"
+	if fee, known := g.transactionFee(tx); known {
+		feeText = FormatSatoshiUint64(fee)
+		if vBytes := controller.CalculateTxVBytes(tx); vBytes > 0 {
+			feeText += fmt.Sprintf(" (%.1f sat/vB)", float64(fee)/float64(vBytes))
+		}
+	}
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("TxID", widget.NewLabel(hex.EncodeToString(txID[:]))),
+		widget.NewFormItem(fmt.Sprintf("Inputs (%d)", len(tx.TxIn)), inputs),
+		widget.NewFormItem(fmt.Sprintf("Outputs (%d)", len(tx.TxOut)), outputs),
+		widget.NewFormItem("Fee", widget.NewLabel(feeText)),
+	}
+	known := g.manager.HasTransaction(txID)
+	if known {
+		items = append(items, widget.NewFormItem("", widget.NewLabel("This transaction is already in the history, it will be rebroadcast.")))
+	}
+
+	content := container.NewVBox()
+	if destinations := g.newDestinationRows(externalOutputs(g.manager, tx)); destinations != nil {
+		content.Add(destinations)
+	}
+	content.Add(widget.NewForm(items...))
+
+	d := dialog.NewCustomConfirm("Broadcast Raw Tx", "Broadcast", "Cancel", content, func(ok bool) {
+		if !ok {
+			return
+		}
+		if known || !g.spendsWalletCoins(tx) {
+			g.rebroadcastTransaction(tx)
+			return
+		}
+		g.broadcastTransaction(&wallet.TxMetadata{Tx: tx}, nil, nil)
+	}, g.window)
+	d.Resize(fyne.NewSize(640, d.MinSize().Height))
+	d.Show()
+}
+
+// rebroadcastTransaction sends tx to the backend without recording it in the history
+func (g *MainGUI) rebroadcastTransaction(tx *wire.MsgTx) {
+	txHex, err := controller.SerializeTx(tx)
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("failed to serialize transaction: %w", err), g.window)
+		return
+	}
+	txID := controller.GetTxID(tx)
+	if _, err := g.manager.BroadcastTransaction(txHex, g.manager.GetNetwork(), nil); err != nil {
+		logging.L.Err(err).Str("tx_hex", txHex).Msg("failed to broadcast raw transaction")
+		g.showError(fmt.Errorf("failed to broadcast transaction: %w", err))
+		return
+	}
+	dialog.ShowInformation("Success", fmt.Sprintf("Transaction broadcast successfully!\n\nTxID: %x", txID), g.window)
+}
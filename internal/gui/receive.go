@@ -2,12 +2,19 @@ package gui
 
 import (
 	"bytes"
+	"context"
+	"fmt"
+	"strings"
 	"time"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/canvas"
 	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
 	"fyne.io/fyne/v2/widget"
+	"github.com/setavenger/blindbit-desktop/internal/controller"
+	"github.com/setavenger/blindbit-desktop/internal/storage"
+	"github.com/setavenger/blindbit-lib/logging"
 	"github.com/skip2/go-qrcode"
 )
 
@@ -63,6 +70,38 @@ func (g *MainGUI) createReceiveTab() fyne.CanvasObject {
 		qrImage,
 	)
 
+	// Payment request with amount and memo
+	requestTitle := widget.NewLabel("Payment Request")
+	requestTitle.TextStyle.Bold = true
+
+	requestText := widget.NewLabel("Ask for a specific amount. The payer's wallet fills in address, amount and memo from the link or QR code.")
+	requestText.Wrapping = fyne.TextWrapWord
+
+	requestBtn := widget.NewButton("Create Payment Request", func() {
+		g.showPaymentRequestDialog(address)
+	})
+
+	requestSection := container.NewVBox(
+		requestTitle,
+		requestText,
+		requestBtn,
+	)
+
+	// Pending receive section
+	pendingTitle := widget.NewLabel("Incoming Payment")
+	pendingTitle.TextStyle.Bold = true
+
+	pendingText := widget.NewLabel("Payments only show up once they are confirmed in a block. If the sender gave you the transaction ID, you can check it now to see it as pending.")
+	pendingText.Wrapping = fyne.TextWrapWord
+
+	checkPendingBtn := widget.NewButton("Check Incoming Transaction", g.showCheckPendingDialog)
+
+	pendingSection := container.NewVBox(
+		pendingTitle,
+		pendingText,
+		checkPendingBtn,
+	)
+
 	// Main content with proper spacing
 	content := container.NewVBox(
 		titleLabel,
@@ -72,11 +111,137 @@ func (g *MainGUI) createReceiveTab() fyne.CanvasObject {
 		addressSection,
 		widget.NewSeparator(),
 		qrContainer,
+		widget.NewSeparator(),
+		g.createReceiveLabelsSection(),
+		widget.NewSeparator(),
+		requestSection,
+		widget.NewSeparator(),
+		pendingSection,
 	)
 
 	return content
 }
 
+// showPaymentRequestDialog asks for amount and memo and shows the payment URI for address
+func (g *MainGUI) showPaymentRequestDialog(address string) {
+	amountEntry := widget.NewEntry()
+	amountEntry.SetPlaceHolder("Amount in satoshis, empty to let the payer choose")
+	memoEntry := widget.NewEntry()
+	memoEntry.SetPlaceHolder("e.g. invoice 42")
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Amount", amountEntry),
+		widget.NewFormItem("Memo", memoEntry),
+	}
+
+	d := dialog.NewForm("Create Payment Request", "Create", "Cancel", items, func(ok bool) {
+		if !ok {
+			return
+		}
+
+		request := controller.PaymentRequest{Address: address, Label: memoEntry.Text}
+		if text := strings.TrimSpace(amountEntry.Text); text != "" {
+			amount, err := ParseFormattedUint64(text)
+			if err != nil || amount == 0 {
+				dialog.ShowError(fmt.Errorf("invalid amount: must be a number of satoshis above 0"), g.window)
+				return
+			}
+			request.Amount = amount
+		}
+
+		uri, err := request.URI()
+		if err != nil {
+			dialog.ShowError(err, g.window)
+			return
+		}
+		g.showPaymentRequest(uri)
+	}, g.window)
+	d.Resize(fyne.NewSize(520, d.MinSize().Height))
+	d.Show()
+}
+
+// showPaymentRequest shows a payment URI as text and QR code
+func (g *MainGUI) showPaymentRequest(uri string) {
+	uriLabel := widget.NewLabel(uri)
+	uriLabel.TextStyle.Monospace = true
+	uriLabel.Wrapping = fyne.TextWrapBreak
+
+	notificationLabel := widget.NewLabel("")
+	notificationLabel.Alignment = fyne.TextAlignCenter
+	notificationLabel.Hide()
+
+	copyBtn := widget.NewButton("Copy to Clipboard", func() {
+		g.copyToClipboard(uri, notificationLabel)
+	})
+
+	content := container.NewVBox(
+		uriLabel,
+		copyBtn,
+		notificationLabel,
+		container.NewCenter(g.generateQRCode(uri)),
+	)
+	d := dialog.NewCustom("Payment Request", "Close", content, g.window)
+	d.Resize(fyne.NewSize(520, d.MinSize().Height))
+	d.Show()
+}
+
+// showCheckPendingDialog asks for a txid and checks it for unconfirmed outputs to this wallet
+// via the configured broadcast backend
+func (g *MainGUI) showCheckPendingDialog() {
+	txidEntry := widget.NewEntry()
+	txidEntry.SetPlaceHolder("Transaction ID")
+
+	dialog.ShowForm("Check Incoming Transaction", "Check", "Cancel",
+		[]*widget.FormItem{widget.NewFormItem("TxID", txidEntry)},
+		func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+
+			progress := dialog.NewCustomWithoutButtons(
+				"Checking Transaction", widget.NewProgressBarInfinite(), g.window,
+			)
+			progress.Show()
+
+			go func() {
+				ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+				defer cancel()
+
+				found, err := g.manager.CheckPendingTransaction(ctx, txidEntry.Text)
+				progress.Hide()
+				if err != nil {
+					logging.L.Err(err).Msg("failed to check pending transaction")
+					g.showError(fmt.Errorf("failed to check transaction: %w", err))
+					return
+				}
+
+				if len(found) == 0 {
+					dialog.ShowInformation("Check Incoming Transaction",
+						"No new outputs to this wallet were found in the transaction.", g.window)
+					return
+				}
+
+				var total uint64
+				for _, utxo := range found {
+					total += utxo.Amount
+				}
+
+				if g.transactionList != nil {
+					g.transactionList.Refresh()
+				}
+
+				if err := storage.SavePlain(g.manager.DataDir, g.manager); err != nil {
+					logging.L.Err(err).Msg("failed to save wallet")
+				}
+
+				dialog.ShowInformation("Pending Payment Found", fmt.Sprintf(
+					"Found %d output(s) worth %s.\n\nThey are shown as pending until the transaction confirms.",
+					len(found), FormatSatoshiUint64(total),
+				), g.window)
+			}()
+		}, g.window)
+}
+
 func (g *MainGUI) copyToClipboard(text string, notificationLabel *widget.Label) {
 	// Copy to clipboard
 	g.window.Clipboard().SetContent(text)
@@ -0,0 +1,116 @@
+package gui
+
+import (
+	"errors"
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/setavenger/blindbit-desktop/internal/controller"
+	"github.com/setavenger/blindbit-desktop/internal/storage"
+	"github.com/setavenger/blindbit-lib/logging"
+)
+
+// createReceiveLabelsSection lists the labeled addresses and whether each was paid.
+// A fresh label per counterparty shows who has paid without giving out different wallets.
+func (g *MainGUI) createReceiveLabelsSection() fyne.CanvasObject {
+	title := widget.NewLabel("Labeled Addresses")
+	title.TextStyle.Bold = true
+
+	intro := widget.NewLabel("Give each payer their own labeled address to see who has paid. " +
+		"All labeled addresses belong to this wallet and are found by the same scan.")
+	intro.Wrapping = fyne.TextWrapWord
+
+	rows := container.NewVBox()
+	refresh := func() {
+		rows.RemoveAll()
+		usage := g.manager.GetLabelUsage()
+		if len(usage) == 0 {
+			rows.Add(widget.NewLabel("No labeled addresses yet."))
+			return
+		}
+		for _, u := range usage {
+			rows.Add(g.receiveLabelRow(u))
+		}
+	}
+	refresh()
+
+	newBtn := widget.NewButton("New Labeled Address", func() {
+		g.showNewReceiveLabelDialog(refresh)
+	})
+	refreshBtn := widget.NewButton("Refresh", refresh)
+
+	return container.NewVBox(
+		title,
+		intro,
+		container.NewHBox(newBtn, refreshBtn),
+		rows,
+	)
+}
+
+func (g *MainGUI) receiveLabelRow(u controller.LabelUsage) fyne.CanvasObject {
+	status := widget.NewLabel("not used yet")
+	status.Importance = widget.LowImportance
+	if u.Used() {
+		status.SetText(fmt.Sprintf("paid %d× · %s", u.Payments, FormatSatoshiUint64(u.Received)))
+		status.Importance = widget.SuccessImportance
+	}
+
+	name := widget.NewLabel(u.Label.Name)
+	name.TextStyle.Bold = true
+
+	address := widget.NewLabel(u.Label.Address)
+	address.TextStyle.Monospace = true
+	address.Truncation = fyne.TextTruncateEllipsis
+
+	label := u.Label
+	copyBtn := widget.NewButton("Copy", func() {
+		g.copyIDToClipboard("Labeled address for "+label.Name, label.Address)
+	})
+
+	return container.NewBorder(nil, nil, container.NewHBox(name, status), copyBtn, address)
+}
+
+// showNewReceiveLabelDialog asks for the counterparty name and creates the labeled address.
+// The scanner is restarted in the background so it looks for the new label.
+func (g *MainGUI) showNewReceiveLabelDialog(onCreated func()) {
+	nameEntry := widget.NewEntry()
+	nameEntry.SetPlaceHolder("e.g. Alice")
+
+	dialog.ShowForm("New Labeled Address", "Create", "Cancel",
+		[]*widget.FormItem{widget.NewFormItem("Name", nameEntry)},
+		func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+
+			label, err := g.manager.NewReceiveLabel(nameEntry.Text)
+			if err != nil {
+				dialog.ShowError(err, g.window)
+				return
+			}
+			if err := storage.SavePlain(g.manager.DataDir, g.manager); err != nil {
+				logging.L.Err(err).Msg("failed to save wallet")
+			}
+			onCreated()
+
+			if g.manager.NeedsReconnect() {
+				go func() {
+					err := g.manager.ReconnectOracle(controller.DefaultShutdownTimeout)
+					if err != nil && !errors.Is(err, controller.ErrOracleNetworkMismatch) {
+						logging.L.Err(err).Msg("failed to restart scanner for new label")
+						runOnMain(func() {
+							dialog.ShowError(fmt.Errorf(
+								"label created, but the scanner could not be restarted, restart to find payments to it: %w", err,
+							), g.window)
+						})
+					}
+				}()
+			}
+
+			g.copyIDToClipboard("Labeled address for "+label.Name, label.Address)
+		}, g.window)
+}
@@ -0,0 +1,72 @@
+package gui
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// formatMnemonicGrid numbers the words of mnemonic, four per line
+func formatMnemonicGrid(mnemonic string) string {
+	words := strings.Fields(mnemonic)
+	var formattedLines []string
+	for i := 0; i < len(words); i += 4 {
+		var lineParts []string
+		for j := 0; j < 4 && i+j < len(words); j++ {
+			wordNum := i + j + 1
+			lineParts = append(lineParts, fmt.Sprintf("%2d. %-12s", wordNum, words[i+j]))
+		}
+		formattedLines = append(formattedLines, strings.Join(lineParts, "  "))
+	}
+	return strings.Join(formattedLines, "\n")
+}
+
+// showRecoveryPhrase reveals the wallet's mnemonic after a confirmation.
+// The wallet file is not encrypted yet, so there is no passphrase to ask for.
+func (g *MainGUI) showRecoveryPhrase() {
+	mnemonic := g.manager.Wallet.Mnemonic
+	if mnemonic == "" {
+		dialog.ShowInformation("Recovery Phrase", "This wallet has no recovery phrase stored.", g.window)
+		return
+	}
+
+	dialog.ShowConfirm(
+		"Show Recovery Phrase",
+		"Anyone who sees your recovery phrase can take your funds.\n"+
+			"Make sure nobody is watching your screen.\n\nShow it now?",
+		func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+
+			warning := widget.NewLabel(
+				"Write these words down in order and keep them offline. Never share them.",
+			)
+			warning.Importance = widget.DangerImportance
+			warning.Wrapping = fyne.TextWrapWord
+
+			mnemonicDisplay := widget.NewLabel(formatMnemonicGrid(mnemonic))
+			mnemonicDisplay.TextStyle.Monospace = true
+
+			copyBtn := widget.NewButton("Copy to Clipboard", func() {
+				g.window.Clipboard().SetContent(mnemonic)
+				dialog.ShowInformation("Copied",
+					"Recovery phrase copied. Clear your clipboard once you have stored it.", g.window)
+			})
+
+			paperBackupBtn := widget.NewButton("Paper Backup / QR Code", func() {
+				showPaperBackup(g.app, g.window, g.manager.DataDir, mnemonic, g.manager.GetNetwork())
+			})
+
+			content := container.NewVBox(
+				warning, mnemonicDisplay, container.NewHBox(copyBtn, paperBackupBtn),
+			)
+			dialog.ShowCustom("Recovery Phrase", "Close", content, g.window)
+		},
+		g.window,
+	)
+}
@@ -0,0 +1,64 @@
+package gui
+
+import (
+	"time"
+
+	"github.com/setavenger/blindbit-desktop/internal/configs"
+)
+
+// refreshInterval turns a configured number of seconds into an interval,
+// falling back to fallback when unset and never going below configs.MinRefreshSeconds
+func refreshInterval(seconds, fallback int) time.Duration {
+	if seconds <= 0 {
+		seconds = fallback
+	}
+	if seconds < configs.MinRefreshSeconds {
+		seconds = configs.MinRefreshSeconds
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// chainTipRefreshInterval is how often the chain tip, sync progress and dashboard are refreshed
+func (g *MainGUI) chainTipRefreshInterval() time.Duration {
+	return refreshInterval(g.manager.ChainTipRefreshSeconds, configs.DefaultChainTipRefreshSeconds)
+}
+
+// utxoRefreshInterval is how often the UTXO tab reloads the UTXOs
+func (g *MainGUI) utxoRefreshInterval() time.Duration {
+	return refreshInterval(g.manager.UTXORefreshSeconds, configs.DefaultUTXORefreshSeconds)
+}
+
+// refreshIntervalsChanged returns a channel which is closed once the settings change the refresh intervals
+func (g *MainGUI) refreshIntervalsChanged() <-chan struct{} {
+	g.intervalsMu.Lock()
+	defer g.intervalsMu.Unlock()
+	return g.intervalsChanged
+}
+
+// applyRefreshIntervals makes the running periodic updates pick up the configured intervals
+func (g *MainGUI) applyRefreshIntervals() {
+	g.intervalsMu.Lock()
+	defer g.intervalsMu.Unlock()
+	close(g.intervalsChanged)
+	g.intervalsChanged = make(chan struct{})
+}
+
+// runPeriodically calls fn every interval() until Cleanup.
+// interval is read again when the settings change the refresh intervals.
+func (g *MainGUI) runPeriodically(interval func() time.Duration, fn func()) {
+	ticker := time.NewTicker(interval())
+	defer ticker.Stop()
+
+	changed := g.refreshIntervalsChanged()
+	for {
+		select {
+		case <-ticker.C:
+			fn()
+		case <-changed:
+			changed = g.refreshIntervalsChanged()
+			ticker.Reset(interval())
+		case <-g.ctx.Done():
+			return
+		}
+	}
+}
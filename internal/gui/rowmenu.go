@@ -0,0 +1,65 @@
+package gui
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/setavenger/blindbit-lib/wallet"
+)
+
+// contextRow wraps a list row and shows a context menu on right click.
+// Left clicks still reach the list, so selecting a row works as before.
+type contextRow struct {
+	widget.BaseWidget
+	content fyne.CanvasObject
+	// menu is set on every row update, rows are recycled by the list
+	menu func() *fyne.Menu
+}
+
+func newContextRow(content fyne.CanvasObject) *contextRow {
+	row := &contextRow{content: content}
+	row.ExtendBaseWidget(row)
+	return row
+}
+
+func (r *contextRow) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(r.content)
+}
+
+func (r *contextRow) TappedSecondary(e *fyne.PointEvent) {
+	if r.menu == nil {
+		return
+	}
+	c := fyne.CurrentApp().Driver().CanvasForObject(r)
+	if c == nil {
+		return
+	}
+	widget.ShowPopUpMenuAtPosition(r.menu(), c, e.AbsolutePosition)
+}
+
+// utxoRowMenu offers the full outpoint and txid of a UTXO row
+func (g *MainGUI) utxoRowMenu(utxo *wallet.OwnedUTXO) *fyne.Menu {
+	txidHex := hex.EncodeToString(utxo.Txid[:])
+	outpoint := fmt.Sprintf("%s:%d", txidHex, utxo.Vout)
+	explorerItem := fyne.NewMenuItem("View in Explorer", func() { g.openOutputInExplorer(txidHex, utxo.Vout) })
+	explorerItem.Disabled = !g.explorerAvailable()
+	return fyne.NewMenu("",
+		fyne.NewMenuItem("Copy Outpoint", func() { g.copyIDToClipboard("Outpoint", outpoint) }),
+		fyne.NewMenuItem("Copy TXID", func() { g.copyIDToClipboard("TXID", txidHex) }),
+		explorerItem,
+	)
+}
+
+// txRowMenu offers the full txid of a transaction row
+func (g *MainGUI) txRowMenu(tx *wallet.TxItem) *fyne.Menu {
+	txidHex := hex.EncodeToString(tx.TxID[:])
+	explorerItem := fyne.NewMenuItem("View in Explorer", func() { g.openInExplorer(txidHex) })
+	explorerItem.Disabled = !g.explorerAvailable()
+	return fyne.NewMenu("",
+		fyne.NewMenuItem("Copy TXID", func() { g.copyIDToClipboard("TXID", txidHex) }),
+		explorerItem,
+	)
+}
@@ -0,0 +1,102 @@
+package gui
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/setavenger/blindbit-desktop/internal/controller"
+	"github.com/setavenger/blindbit-desktop/internal/storage"
+	"github.com/setavenger/blindbit-lib/logging"
+)
+
+// showScanImportDialog imports the UTXOs and scan height of a blindbit-scan daemon,
+// so migrating users don't have to rescan from the birth height
+func (g *MainGUI) showScanImportDialog() {
+	openDialog := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, g.window)
+			return
+		}
+		if reader == nil {
+			// cancelled
+			return
+		}
+		defer reader.Close()
+
+		data, err := io.ReadAll(reader)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("failed to read export: %v", err), g.window)
+			return
+		}
+		export, err := controller.ParseScanExport(data)
+		if err != nil {
+			dialog.ShowError(err, g.window)
+			return
+		}
+		g.confirmScanImport(export)
+	}, g.window)
+	openDialog.Show()
+}
+
+// confirmScanImport shows what the export contains and asks for the height the daemon scanned up to
+func (g *MainGUI) confirmScanImport(export *controller.ScanExport) {
+	var total uint64
+	for _, utxo := range export.UTXOs {
+		total += utxo.Amount
+	}
+
+	heightEntry := widget.NewEntry()
+	heightEntry.SetPlaceHolder("height blindbit-scan has scanned up to")
+	if export.Height > 0 {
+		heightEntry.SetText(strconv.FormatUint(export.Height, 10))
+	}
+
+	hint := widget.NewLabel("The wallet continues scanning after this height. " +
+		"Leave it empty to keep the current scan height of " + FormatHeightUint64(g.manager.ScanHeight()) + ".")
+	hint.Wrapping = fyne.TextWrapWord
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("UTXOs", widget.NewLabel(fmt.Sprintf("%d", len(export.UTXOs)))),
+		widget.NewFormItem("Value", widget.NewLabel(FormatSatoshiUint64(total))),
+		widget.NewFormItem("Scan Height", heightEntry),
+		widget.NewFormItem("", hint),
+	}
+
+	d := dialog.NewForm("Import from blindbit-scan", "Import", "Cancel", items, func(ok bool) {
+		if !ok {
+			return
+		}
+
+		var height uint64
+		if text := heightEntry.Text; text != "" {
+			parsed, err := ParseFormattedUint64(text)
+			if err != nil {
+				dialog.ShowError(fmt.Errorf("invalid scan height: %v", err), g.window)
+				return
+			}
+			height = parsed
+		}
+
+		result, err := g.manager.ImportScanExport(export, height)
+		if err != nil {
+			g.showError(err)
+			return
+		}
+		if err := storage.SavePlain(g.manager.DataDir, g.manager); err != nil {
+			logging.L.Err(err).Msg("failed to save wallet after import")
+		}
+		g.refreshAfterScan()
+
+		dialog.ShowInformation("Import Finished", fmt.Sprintf(
+			"Imported %d UTXO(s), %d were already known.\n\nScanning continues after height %s.",
+			result.Added, result.Skipped, FormatHeightUint64(result.Height),
+		), g.window)
+	}, g.window)
+	d.Resize(fyne.NewSize(520, d.MinSize().Height))
+	d.Show()
+}
@@ -1,16 +1,16 @@
 package gui
 
 import (
-	"context"
+	"errors"
 	"fmt"
 	"strconv"
-	"time"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/dialog"
 	"fyne.io/fyne/v2/widget"
 
+	"github.com/setavenger/blindbit-desktop/internal/controller"
 	"github.com/setavenger/blindbit-desktop/internal/storage"
 	"github.com/setavenger/blindbit-lib/logging"
 )
@@ -28,7 +28,13 @@ func (g *MainGUI) createScanningTab() fyne.CanvasObject {
 	currentScanLabel := widget.NewLabel("Current Scan Height: N/A")
 
 	// Chain tip height
-	chainTipLabel := widget.NewLabel("Chain Tip: N/A")
+	chainTipLabel := widget.NewLabel("Chain Tip: Connecting...")
+
+	// Estimated time until the scan reaches tip
+	etaLabel := widget.NewLabel("Time Remaining: —")
+
+	// Overall sync and, while one runs, rescan progress
+	syncLabel := widget.NewLabel("Synced: —")
 
 	// Rescan options
 	rescanTitle := widget.NewLabel("Rescan Options")
@@ -41,6 +47,11 @@ func (g *MainGUI) createScanningTab() fyne.CanvasObject {
 	)
 	rescanHeightLabel := widget.NewLabel("Rescan from height:")
 
+	// Optional end height, limits the rescan to a window
+	rescanEndEntry := widget.NewEntry()
+	rescanEndEntry.SetPlaceHolder("Enter height to rescan to (leave empty for chain tip)")
+	rescanEndLabel := widget.NewLabel("Rescan to height:")
+
 	// Control buttons
 	rescanBtn := widget.NewButton("Rescan", func() {
 		heightStr := rescanHeightEntry.Text
@@ -57,37 +68,61 @@ func (g *MainGUI) createScanningTab() fyne.CanvasObject {
 			height = int(g.manager.GetBirthHeight())
 		}
 
+		if endStr := rescanEndEntry.Text; endStr != "" {
+			endHeight, err := strconv.Atoi(endStr)
+			if err != nil {
+				dialog.ShowError(fmt.Errorf("invalid end height: %v", err), g.window)
+				return
+			}
+			g.startRangeRescan(uint32(height), uint32(endHeight))
+			return
+		}
+
 		g.startRescanning(height)
 	})
 
+	// Pause keeps what was scanned, resume continues right after it
+	pauseBtn := widget.NewButton("Pause Rescan", g.pauseRescan)
+	resumeBtn := widget.NewButton("Resume Rescan", g.resumeRescan)
+	rescanStateLabel := widget.NewLabel("")
+	g.rescanControls = &rescanControls{
+		manager: g.manager,
+		state:   rescanStateLabel,
+		pause:   pauseBtn,
+		resume:  resumeBtn,
+	}
+	g.rescanControls.update()
+
 	// Progress bar
 	progressBar := widget.NewProgressBar()
 	progressBar.Hide()
 
-	// Update initial values
-	g.refreshScanStatus(currentScanLabel, chainTipLabel)
+	// Update initial values, the chain tip comes from the oracle
+	go g.refreshScanStatus(currentScanLabel, chainTipLabel, etaLabel)
 
 	// Start periodic refresh of chain tip
-	go g.startPeriodicRefresh(chainTipLabel, currentScanLabel)
-
-	// Start real-time progress updates from scanner
-	go g.startRealTimeProgressUpdates(currentScanLabel)
+	go g.startPeriodicRefresh(chainTipLabel, etaLabel, syncLabel)
 
-	// Start stream end detection for final updates
-	go g.startStreamEndDetection(currentScanLabel)
+	// Scan height follows the scanner's progress and stream end signals
+	go g.startScanProgressUpdates(currentScanLabel, syncLabel)
 
 	// Layout sections
 	scanStatusSection := container.NewVBox(
 		scanStatusTitle,
 		currentScanLabel,
 		chainTipLabel,
+		etaLabel,
+		syncLabel,
 	)
 
 	rescanSection := container.NewVBox(
 		rescanTitle,
 		rescanHeightLabel,
 		rescanHeightEntry,
-		container.NewHBox(rescanBtn),
+		rescanEndLabel,
+		rescanEndEntry,
+		container.NewHBox(rescanBtn, pauseBtn, resumeBtn),
+		rescanStateLabel,
 	)
 
 	// Main content
@@ -105,8 +140,8 @@ func (g *MainGUI) createScanningTab() fyne.CanvasObject {
 
 func (g *MainGUI) startRescanning(fromHeight int) {
 	// Scanner should already be initialized in main.go
-	if g.manager.Scanner == nil {
-		dialog.ShowError(fmt.Errorf("scanner not initialized"), g.window)
+	if g.manager.GetScanner() == nil {
+		g.showError(controller.ErrScannerNotReady)
 		return
 	}
 
@@ -116,7 +151,127 @@ func (g *MainGUI) startRescanning(fromHeight int) {
 			"Rescanning started from height %s to current tip",
 			FormatHeightUint64(uint64(fromHeight)),
 		),
-		true,
+	)
+}
+
+// rescanControls are the pause and resume buttons and the label showing the rescan mode
+type rescanControls struct {
+	manager *controller.Manager
+	state   *widget.Label
+	pause   *widget.Button
+	resume  *widget.Button
+}
+
+// update shows whether a rescan runs, is paused or neither, nil before the scanning tab exists
+func (c *rescanControls) update() {
+	if c == nil {
+		return
+	}
+
+	paused := c.manager.GetPausedRescan()
+	switch {
+	case c.manager.RescanRunning():
+		c.state.SetText("Rescan running, pause to continue it later.")
+		c.pause.Enable()
+		c.resume.Disable()
+	case paused != nil:
+		text := fmt.Sprintf(
+			"Rescan of %s to %s paused after height %s.",
+			FormatHeight(paused.From), FormatHeight(paused.To), FormatHeight(paused.Height),
+		)
+		c.pause.Disable()
+		if c.manager.IsOffline() {
+			text += " Go online to resume it."
+			c.resume.Disable()
+		} else {
+			c.resume.Enable()
+		}
+		c.state.SetText(text)
+	default:
+		c.state.SetText("")
+		c.pause.Disable()
+		c.resume.Disable()
+	}
+}
+
+// pauseRescan stops the running rescan, what was scanned so far is kept
+func (g *MainGUI) pauseRescan() {
+	if err := g.manager.PauseRescan(); err != nil {
+		g.showError(err)
+		return
+	}
+	g.rescanControls.update()
+}
+
+// resumeRescan continues a paused rescan after the last height it scanned
+func (g *MainGUI) resumeRescan() {
+	paused := g.manager.GetPausedRescan()
+	if paused == nil {
+		return
+	}
+	if g.manager.IsOffline() {
+		g.showError(fmt.Errorf("cannot resume the rescan: %w", controller.ErrOffline))
+		return
+	}
+	if g.manager.GetScanner() == nil {
+		g.showError(controller.ErrScannerNotReady)
+		return
+	}
+
+	from := paused.Height + 1
+	g.performScan(
+		from, "Resuming Rescan",
+		fmt.Sprintf("Rescan resumed from height %s to current tip", FormatHeight(from)),
+	)
+}
+
+// startRangeRescan rescans a bounded window without touching the scan height
+func (g *MainGUI) startRangeRescan(fromHeight, toHeight uint32) {
+	if g.manager.GetScanner() == nil {
+		g.showError(controller.ErrScannerNotReady)
+		return
+	}
+	if fromHeight > toHeight {
+		dialog.ShowError(fmt.Errorf("start height is above end height"), g.window)
+		return
+	}
+
+	go func() {
+		utxosBefore := g.manager.UTXOCount()
+
+		err := g.manager.RescanRange(g.manager.Context(), fromHeight, toHeight)
+		if err != nil {
+			logging.L.Err(err).Msg("range rescan failed")
+			runOnMain(func() { g.showError(err) })
+			return
+		}
+
+		g.manager.SignalStreamEnd()
+
+		if err := storage.SavePlain(g.manager.DataDir, g.manager); err != nil {
+			logging.L.Err(err).Msg("failed to save wallet after range rescan")
+		}
+
+		found := g.manager.UTXOCount() - utxosBefore
+		runOnMain(func() {
+			dialog.ShowInformation(
+				"Rescan Finished",
+				fmt.Sprintf(
+					"Rescanned heights %s to %s, %d new UTXO(s) found.",
+					FormatHeight(fromHeight), FormatHeight(toHeight), found,
+				),
+				g.window,
+			)
+		})
+	}()
+
+	dialog.ShowInformation(
+		"Rescanning",
+		fmt.Sprintf(
+			"Rescanning started from height %s to %s",
+			FormatHeight(fromHeight), FormatHeight(toHeight),
+		),
+		g.window,
 	)
 }
 
@@ -124,8 +279,20 @@ func (g *MainGUI) startRescanning(fromHeight int) {
 func (g *MainGUI) performScan(
 	startHeight uint32,
 	operationName, dialogMessage string,
-	rescan bool,
 ) {
+	if err := g.manager.OracleNetworkError(); err != nil {
+		g.showError(fmt.Errorf("scanning disabled: %w", err))
+		return
+	}
+	if g.manager.IsOffline() {
+		g.showError(fmt.Errorf("scanning disabled: %w", controller.ErrOffline))
+		return
+	}
+	if g.manager.RescanRunning() {
+		g.showError(controller.ErrRescanRunning)
+		return
+	}
+
 	// Start scanning from specified height to current tip
 	go func() {
 		// Get current height
@@ -141,16 +308,38 @@ func (g *MainGUI) performScan(
 			Str("operation", operationName).
 			Msg("starting rescan operation")
 
-		// Start rescanning - channel handling is done by the manager
-		// err = g.manager.Scanner.Scan(context.Background(), startHeight, currentHeight)
-		err = g.manager.Scanner.Scan(
-			context.Background(), startHeight, currentHeight, rescan,
-		)
+		// the rate of the previous scan says nothing about this one
+		g.manager.ResetScanRate()
+		ctx, err := g.manager.BeginRescan(startHeight, currentHeight)
+		if err != nil {
+			logging.L.Err(err).Msg("failed to start rescan")
+			runOnMain(func() { g.showError(fmt.Errorf("failed to start rescan: %w", err)) })
+			return
+		}
+		runOnMain(g.rescanControls.update)
+
+		// Start rescanning - channel handling is done by the manager.
+		// Not in the scanner's rescan mode, which sends no progress updates:
+		// the scan height moves through the range and the rescan progress follows it.
+		scanner := g.manager.GetScanner()
+		if scanner == nil {
+			err = controller.ErrScannerNotReady
+		} else {
+			err = scanner.Scan(ctx, startHeight, currentHeight, false)
+		}
+		if g.manager.EndRescan() {
+			// the progress so far is kept in PausedRescan for a resume
+			g.manager.SignalStreamEnd()
+			if err := storage.SavePlain(g.manager.DataDir, g.manager); err != nil {
+				logging.L.Err(err).Msg("failed to save wallet after pausing rescan")
+			}
+			return
+		}
 		if err != nil {
 			logging.L.Err(err).Msg("rescanning failed")
 		} else {
 			// Update wallet's LastScanHeight to the final height
-			g.manager.Wallet.LastScanHeight = uint64(currentHeight)
+			g.manager.SetScanHeight(uint64(currentHeight))
 
 			// Send final update to GUI to ensure it shows the completed scan height
 			if g.manager.GUIScanProgressChan != nil {
@@ -180,64 +369,91 @@ func (g *MainGUI) performScan(
 			Uint32("current_height", currentHeight).
 			Str("operation", operationName).
 			Msg("rescanning finished")
+		runOnMain(g.rescanControls.update)
 	}()
 
 	dialog.ShowInformation(operationName, dialogMessage, g.window)
 }
 
+// refreshScanStatus asks the oracle for the chain tip, call it off the UI thread
 func (g *MainGUI) refreshScanStatus(
-	currentScanLabel, chainTipLabel *widget.Label,
+	currentScanLabel, chainTipLabel, etaLabel *widget.Label,
 ) {
-	// Update current scan height from wallet - always show the value
-	currentScanLabel.SetText(
-		"Current Scan Height: " + FormatHeightUint64(g.manager.Wallet.LastScanHeight),
-	)
-
-	// Update chain tip from oracle
-	if currentHeight, err := g.manager.GetCurrentHeight(); err == nil {
-		chainTipLabel.SetText("Chain Tip: " + FormatHeight(currentHeight))
-	} else {
-		chainTipLabel.SetText("Chain Tip: Unable to fetch")
+	currentHeight, err := g.manager.GetCurrentHeight()
+	if err != nil {
 		logging.L.Err(err).Msg("failed to get current height from oracle")
 	}
+
+	runOnMain(func() {
+		// Update current scan height from wallet - always show the value
+		currentScanLabel.SetText(
+			"Current Scan Height: " + FormatHeightUint64(g.manager.ScanHeight()),
+		)
+		setChainTipLabels(g.manager, chainTipLabel, etaLabel, currentHeight, err)
+	})
 }
 
-// startPeriodicRefresh starts a goroutine that periodically refreshes chain tip and scan status
-func (g *MainGUI) startPeriodicRefresh(
-	chainTipLabel, currentScanLabel *widget.Label,
+// setChainTipLabels shows the chain tip and time remaining or that the tip is unknown.
+// A zero tip means no answer from the oracle yet, it must not show up as synced.
+func setChainTipLabels(
+	manager *controller.Manager, chainTipLabel, etaLabel *widget.Label, tip uint32, err error,
 ) {
-	ticker := time.NewTicker(10 * time.Second) // Refresh every 10 seconds for better responsiveness
-	defer ticker.Stop()
+	if errors.Is(err, controller.ErrOffline) {
+		chainTipLabel.SetText("Chain Tip: Unknown while offline")
+		etaLabel.SetText("Time Remaining: offline")
+		return
+	}
+	if err != nil {
+		chainTipLabel.SetText("Chain Tip: Unable to fetch")
+		etaLabel.SetText("Time Remaining: N/A")
+		return
+	}
+	if tip == 0 {
+		chainTipLabel.SetText("Chain Tip: Connecting...")
+		etaLabel.SetText("Time Remaining: —")
+		return
+	}
+	chainTipLabel.SetText("Chain Tip: " + FormatHeight(tip))
+	if manager.IsOffline() {
+		etaLabel.SetText("Time Remaining: offline")
+		return
+	}
+	if manager.ScanningPausedOnBattery() {
+		etaLabel.SetText("Time Remaining: paused on battery")
+		return
+	}
+	etaLabel.SetText("Time Remaining: " + FormatScanETA(manager.ScanETA(tip)))
+}
 
-	for range ticker.C {
+// setSyncProgressLabel shows the overall sync and the progress of a running rescan
+// against the last known chain tip
+func setSyncProgressLabel(manager *controller.Manager, syncLabel *widget.Label) {
+	syncLabel.SetText(FormatSyncProgress(manager.SyncProgress(manager.CachedChainTip())))
+}
+
+// startPeriodicRefresh periodically refreshes chain tip, time remaining and sync progress until Cleanup.
+// The scan height label is owned by startScanProgressUpdates.
+func (g *MainGUI) startPeriodicRefresh(chainTipLabel, etaLabel, syncLabel *widget.Label) {
+	g.runPeriodically(g.chainTipRefreshInterval, func() {
 		// Update chain tip
-		if currentHeight, err := g.manager.GetCurrentHeight(); err == nil {
-			chainTipLabel.SetText("Chain Tip: " + FormatHeight(currentHeight))
-		} else {
-			chainTipLabel.SetText("Chain Tip: Unable to fetch")
+		currentHeight, err := g.manager.GetCurrentHeight()
+		if err != nil {
 			logging.L.Err(err).Msg("periodic refresh failed to get current height")
 		}
-
-		// Update current scan height - always show the value
-		currentScanLabel.SetText(
-			"Current Scan Height: " + FormatHeightUint64(g.manager.Wallet.LastScanHeight),
-		)
-	}
+		runOnMain(func() {
+			setChainTipLabels(g.manager, chainTipLabel, etaLabel, currentHeight, err)
+			setSyncProgressLabel(g.manager, syncLabel)
+			g.updateInitialSync()
+		})
+	})
 }
 
-// startRealTimeProgressUpdates listens to the scanner's progress channel
-// and updates the GUI in real-time
-func (g *MainGUI) startRealTimeProgressUpdates(currentScanLabel *widget.Label) {
-	if g.manager.GUIScanProgressChan == nil {
-		logging.L.Warn().Msg("GUI progress channel not initialized")
-		logging.L.Info().Msg("Initilising GUI progress channel")
-		g.manager.GUIScanProgressChan = make(chan uint32)
-		return
-	}
-	if g.manager.StreamEndChan == nil {
-		logging.L.Warn().Msg("stream end channel not initialized")
-		logging.L.Info().Msg("Initialising stream end channel")
-		g.manager.StreamEndChan = make(chan bool)
+// startScanProgressUpdates is the only consumer of the scanner's progress and stream end channels.
+// Progress updates show the scan height as it moves, a stream end reads the final
+// LastScanHeight and marks the scan as done until the next progress update arrives.
+func (g *MainGUI) startScanProgressUpdates(currentScanLabel, syncLabel *widget.Label) {
+	if g.manager.GUIScanProgressChan == nil || g.manager.StreamEndChan == nil {
+		logging.L.Warn().Msg("scan progress channels not initialized, real-time updates disabled")
 		return
 	}
 
@@ -245,51 +461,28 @@ func (g *MainGUI) startRealTimeProgressUpdates(currentScanLabel *widget.Label) {
 
 	for {
 		select {
+		case <-g.ctx.Done():
+			return
 		case height := <-g.manager.GUIScanProgressChan:
 			currentScanLabel.SetText(
-				"Current Scan Height: " + FormatHeightUint64(g.manager.Wallet.LastScanHeight),
+				"Current Scan Height: " + FormatHeightUint64(g.manager.ScanHeight()),
 			)
-			logging.L.Debug().
+			setSyncProgressLabel(g.manager, syncLabel)
+			g.updateInitialSync()
+			logging.L.Trace().
 				Uint32("height", height).
 				Msg("GUI updated with real-time scan progress")
 		case <-g.manager.StreamEndChan:
 			currentScanLabel.SetText(
-				"Current Scan Height: " + FormatHeightUint64(g.manager.Wallet.LastScanHeight),
+				"Current Scan Height: " + FormatHeightUint64(g.manager.ScanHeight()) + " (done)",
 			)
-			logging.L.Info().Msg("stream ended, GUI updated with final scan height")
-			return
-		case <-time.After(10 * time.Second):
-			currentScanLabel.SetText(
-				"Current Scan Height: " + FormatHeightUint64(g.manager.Wallet.LastScanHeight),
-			)
-			// logging.L.Trace().Msg("GUI updated with real-time scan progress")
+			setSyncProgressLabel(g.manager, syncLabel)
+			g.updateInitialSync()
+			g.rescanControls.update()
+			g.refreshAfterScan()
+			logging.L.Info().
+				Uint64("final_height", g.manager.ScanHeight()).
+				Msg("stream ended, GUI updated with final scan height")
 		}
 	}
 }
-
-// startStreamEndDetection listens for stream end signals and ensures final updates are shown
-func (g *MainGUI) startStreamEndDetection(currentScanLabel *widget.Label) {
-	// todo: remove this function
-	// redundant with startrealtime processing doing everything
-
-	// if g.manager.StreamEndChan == nil {
-	// 	logging.L.Warn().Msg("stream end channel not initialized")
-	// 	logging.L.Info().Msg("Initialising stream end channel")
-	// 	g.manager.StreamEndChan = make(chan bool)
-	// 	return
-	// }
-	//
-	// logging.L.Info().Msg("starting stream end detection for scanning view")
-	//
-	// for range g.manager.StreamEndChan {
-	// 	// Force update the GUI with the current scan height when stream ends
-	// 	currentScanLabel.SetText(
-	// 		fmt.Sprintf("Current Scan Height: %d", g.manager.Wallet.LastScanHeight),
-	// 	)
-	// 	logging.L.Info().
-	// 		Uint64("final_height", g.manager.Wallet.LastScanHeight).
-	// 		Msg("stream ended, GUI updated with final scan height")
-	// }
-	//
-	// logging.L.Info().Msg("stream end detection stopped")
-}
@@ -1,27 +1,31 @@
 package gui
 
 import (
-	"bytes"
 	"context"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/dialog"
 	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 
+	"github.com/btcsuite/btcd/wire"
 	"github.com/setavenger/blindbit-desktop/internal/configs"
 	"github.com/setavenger/blindbit-desktop/internal/controller"
 	"github.com/setavenger/blindbit-desktop/internal/storage"
 	"github.com/setavenger/blindbit-lib/logging"
 	"github.com/setavenger/blindbit-lib/types"
-	"github.com/setavenger/blindbit-lib/utils"
 	"github.com/setavenger/blindbit-lib/wallet"
 )
 
@@ -31,24 +35,36 @@ func (g *MainGUI) createSendTab() fyne.CanvasObject {
 	recipientEntry.SetPlaceHolder("Enter recipient address...")
 
 	amountEntry := widget.NewEntry()
-	amountEntry.SetPlaceHolder("Amount in satoshis (e.g., 100000)")
+	amountEntry.SetPlaceHolder("Sats or BTC (e.g., 100,000 or 100k sats or 0.001 BTC)")
+	// the amount as understood, or why it is not
+	amountHint := widget.NewLabel("")
+	amountHint.Hide()
 
 	feeRateEntry := widget.NewEntry()
 	feeRateEntry.SetPlaceHolder("Fee rate in sat/vB (e.g., 10)")
 
+	// Memo of a pasted payment URI, or why the URI was not understood
+	paymentURILabel := widget.NewLabel("")
+	paymentURILabel.Wrapping = fyne.TextWrapWord
+	paymentURILabel.Hide()
+
 	// Labels
 	recipientLabel := widget.NewLabel("Recipient Address:")
-	amountLabel := widget.NewLabel("Amount (satoshis):")
+	amountLabel := widget.NewLabel("Amount:")
 	feeRateLabel := widget.NewLabel("Fee Rate (sat/vB):")
 
 	var fastFee, middleFee, slowFee uint
 
-	feeEstimationEnabled := g.manager.FeeEstimationEnabled
+	offline := g.manager.IsOffline()
+	feeEstimationEnabled := g.manager.FeeEstimationEnabled && !offline
 
 	// Fee suggestion buttons with closures that reference the fee variables
 	initialFeeLabel := func(tier string) string {
-		if feeEstimationEnabled {
+		switch {
+		case feeEstimationEnabled:
 			return tier + ": loading..."
+		case offline:
+			return tier + ": offline"
 		}
 		return tier + ": disabled"
 	}
@@ -102,10 +118,107 @@ func (g *MainGUI) createSendTab() fyne.CanvasObject {
 		}()
 	}
 
+	// Live fee estimate, recomputed off the UI thread once typing pauses
+	feeEstimateLabel := widget.NewLabel(feeEstimatePrompt)
+	var (
+		estimateMu    sync.Mutex
+		estimateTimer *time.Timer
+		estimateSeq   int
+	)
+	updateFeeEstimate := func(string) {
+		amountText, feeRateText := amountEntry.Text, feeRateEntry.Text
+
+		estimateMu.Lock()
+		defer estimateMu.Unlock()
+		estimateSeq++
+		seq := estimateSeq
+		if estimateTimer != nil {
+			estimateTimer.Stop()
+		}
+		estimateTimer = time.AfterFunc(feeEstimateDebounce, func() {
+			text := g.feeEstimateText(amountText, feeRateText)
+			runOnMain(func() {
+				estimateMu.Lock()
+				current := seq == estimateSeq
+				estimateMu.Unlock()
+				// a newer edit has its own estimate underway
+				if current {
+					feeEstimateLabel.SetText(text)
+				}
+			})
+		})
+	}
+	// plain sats get thousands separators while typing, other input is explained below the field
+	var previousAmount, formattedAmount string
+	amountEntry.OnChanged = func(text string) {
+		regroup := previousAmount != "" && previousAmount == formattedAmount
+		previousAmount = text
+		if formatted, ok := formatAmountInput(text, regroup); ok {
+			formattedAmount = formatted
+			if formatted != text {
+				previousAmount = formatted
+				amountEntry.SetText(formatted)
+				amountEntry.CursorColumn = len(formatted)
+				amountEntry.Refresh()
+			}
+		}
+
+		switch amount, err := ParseAmount(amountEntry.Text); {
+		case strings.TrimSpace(amountEntry.Text) == "":
+			amountHint.Hide()
+		case err != nil:
+			amountHint.SetText(err.Error())
+			amountHint.Importance = widget.DangerImportance
+			amountHint.Show()
+		case onlyDigits(strings.ReplaceAll(amountEntry.Text, ",", "")):
+			// already shown in sats
+			amountHint.Hide()
+		default:
+			amountHint.SetText("= " + FormatSatoshiUint64(amount))
+			amountHint.Importance = widget.MediumImportance
+			amountHint.Show()
+		}
+		amountHint.Refresh()
+		updateFeeEstimate(amountEntry.Text)
+	}
+	feeRateEntry.OnChanged = updateFeeEstimate
+
+	// A pasted bitcoin: URI fills in address and amount
+	var uriAddress string
+	recipientEntry.OnChanged = func(text string) {
+		if !controller.IsPaymentURI(text) {
+			if text != uriAddress {
+				uriAddress = ""
+				paymentURILabel.Hide()
+			}
+			return
+		}
+
+		request, err := controller.ParsePaymentURI(text)
+		if err != nil {
+			paymentURILabel.SetText("Payment link not understood: " + err.Error())
+			paymentURILabel.Show()
+			return
+		}
+
+		uriAddress = request.Address
+		recipientEntry.SetText(request.Address)
+		if request.Amount > 0 {
+			amountEntry.SetText(strconv.FormatUint(request.Amount, 10))
+		}
+		if request.Label != "" {
+			paymentURILabel.SetText("Memo: " + request.Label)
+			paymentURILabel.Show()
+		} else {
+			paymentURILabel.Hide()
+		}
+	}
+
 	// Preview button
 	previewBtn := widget.NewButton("Send Transaction", func() {
 		g.previewTransaction(recipientEntry.Text, amountEntry.Text, feeRateEntry.Text)
 	})
+	g.sendBtn = previewBtn
 
 	// Send button (initially disabled)
 	// sendBtn := widget.NewButton("Send Transaction", func() {
@@ -117,9 +230,11 @@ func (g *MainGUI) createSendTab() fyne.CanvasObject {
 	formItems := []fyne.CanvasObject{
 		recipientLabel,
 		recipientEntry,
+		paymentURILabel,
 		widget.NewSeparator(),
 		amountLabel,
 		amountEntry,
+		amountHint,
 		widget.NewSeparator(),
 		feeRateLabel,
 		feeRateEntry,
@@ -140,6 +255,7 @@ func (g *MainGUI) createSendTab() fyne.CanvasObject {
 		))
 	}
 	formItems = append(formItems,
+		feeEstimateLabel,
 		widget.NewSeparator(),
 		container.NewHBox(
 			previewBtn,
@@ -150,23 +266,73 @@ func (g *MainGUI) createSendTab() fyne.CanvasObject {
 	return container.NewVBox(formItems...)
 }
 
+// feeEstimateDebounce is the pause in typing after which the fee estimate is recomputed
+const feeEstimateDebounce = 300 * time.Millisecond
+
+const feeEstimatePrompt = "Estimated fee: enter an amount and fee rate"
+
+// feeEstimateText estimates the fee for the amount and fee rate as typed, see Manager.EstimateFee
+func (g *MainGUI) feeEstimateText(amountText, feeRateText string) string {
+	amount, err := ParseAmount(amountText)
+	if err != nil || amount == 0 {
+		return feeEstimatePrompt
+	}
+	feeRate, err := strconv.ParseUint(feeRateText, 10, 32)
+	if err != nil || feeRate == 0 {
+		return feeEstimatePrompt
+	}
+
+	// the recipient's script is not known yet, it is counted as taproot
+	recipients := []wallet.Recipient{&wallet.RecipientImpl{Amount: amount}}
+	estimate, err := g.manager.EstimateFee(recipients, uint32(feeRate))
+	var insufficient *controller.InsufficientFundsError
+	switch {
+	case errors.As(err, &insufficient) && insufficient.MaxSendable > 0:
+		return fmt.Sprintf(
+			"Insufficient funds, at most %s can be sent at this fee rate",
+			FormatSatoshiUint64(insufficient.MaxSendable),
+		)
+	case errors.As(err, &insufficient):
+		return "Insufficient funds, the balance does not cover the fee at this fee rate"
+	case err != nil:
+		return "Estimated fee: unavailable (" + err.Error() + ")"
+	}
+
+	return fmt.Sprintf(
+		"Estimated fee: %s (%d input(s))   Total: %s",
+		FormatSatoshiUint64(estimate.Fee),
+		estimate.Inputs,
+		FormatSatoshiUint64(amount+estimate.Fee),
+	)
+}
+
 func (g *MainGUI) previewTransaction(recipient, amountStr, feeRateStr string) {
 	// Validate inputs
 	if recipient == "" {
 		dialog.ShowError(fmt.Errorf("recipient address is required"), g.window)
 		return
 	}
+	// the form replaces pasted URIs, one may still arrive unparsed
+	if controller.IsPaymentURI(recipient) {
+		request, err := controller.ParsePaymentURI(recipient)
+		if err != nil {
+			dialog.ShowError(err, g.window)
+			return
+		}
+		recipient = request.Address
+		if amountStr == "" && request.Amount > 0 {
+			amountStr = strconv.FormatUint(request.Amount, 10)
+		}
+	}
 
-	_, err := strconv.ParseFloat(amountStr, 64)
+	// sats, k sats or BTC, see ParseAmount
+	amount, err := ParseAmount(amountStr)
 	if err != nil {
 		dialog.ShowError(fmt.Errorf("invalid amount: %v", err), g.window)
 		return
 	}
-
-	// Parse amount as uint64 (satoshis)
-	amount, err := ParseFormattedUint64(amountStr)
-	if err != nil {
-		dialog.ShowError(fmt.Errorf("invalid amount: %v", err), g.window)
+	if amount == 0 {
+		dialog.ShowError(fmt.Errorf("invalid amount: must be above 0"), g.window)
 		return
 	}
 
@@ -182,6 +348,10 @@ func (g *MainGUI) previewTransaction(recipient, amountStr, feeRateStr string) {
 		dialog.ShowError(fmt.Errorf("invalid fee rate: %v", err), g.window)
 		return
 	}
+	if err := g.manager.CheckFeeRate(feeRate); err != nil {
+		g.showError(err)
+		return
+	}
 
 	// Convert BTC to satoshis
 	// amountSatoshis := uint64(amount * 100000000)
@@ -195,42 +365,140 @@ func (g *MainGUI) previewTransaction(recipient, amountStr, feeRateStr string) {
 		},
 	}
 
-	// Prepare transaction
+	reuse, err := g.manager.PreviousPayments(recipient)
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("invalid recipient: %v", err), g.window)
+		return
+	}
+
+	prepare := func() {
+		if reuse != nil {
+			g.confirmAddressReuse(reuse, func() {
+				g.prepareTransaction(recipients, uint32(feeRate))
+			})
+			return
+		}
+		g.prepareTransaction(recipients, uint32(feeRate))
+	}
+	if feeRate >= configs.HighFeeRate {
+		g.confirmHighFeeRate(feeRate, prepare)
+		return
+	}
+	prepare()
+}
+
+// confirmHighFeeRate asks before building a transaction with a fee rate of configs.HighFeeRate or more,
+// such rates are mostly typos
+func (g *MainGUI) confirmHighFeeRate(feeRate uint64, proceed func()) {
+	dialog.ShowConfirm("High Fee Rate",
+		fmt.Sprintf(
+			"The fee rate of %s sat/vB is unusually high and may cost far more than needed.\n\nContinue anyway?",
+			FormatUint64(feeRate),
+		),
+		func(ok bool) {
+			if ok {
+				proceed()
+			}
+		}, g.window)
+}
+
+// confirmAddressReuse warns that the recipient address was paid before.
+// Reused addresses link payments on chain, the user may still go ahead.
+func (g *MainGUI) confirmAddressReuse(reuse *controller.AddressReuse, proceed func()) {
+	when := "still pending"
+	if reuse.LastHeight > 0 {
+		when = "confirmed at height " + FormatHeightUint64(uint64(reuse.LastHeight))
+	}
+	times := "once"
+	if reuse.Payments > 1 {
+		times = fmt.Sprintf("%d times", reuse.Payments)
+	}
+
+	message := widget.NewLabel(fmt.Sprintf(
+		"You already paid this address %s, most recently in %s (%s).\n\n"+
+			"Sending to the same address again lets anyone link these payments. "+
+			"Ask the recipient for a fresh address or a silent payment address if you can.",
+		times, reuse.LastTxID, when,
+	))
+	message.Wrapping = fyne.TextWrapWord
+
+	d := dialog.NewCustomConfirm("Address Already Used", "Continue", "Cancel", message, func(ok bool) {
+		if ok {
+			proceed()
+		}
+	}, g.window)
+	d.Resize(fyne.NewSize(520, d.MinSize().Height))
+	d.Show()
+}
+
+// prepareTransaction selects coins for recipients and shows the result for review
+func (g *MainGUI) prepareTransaction(recipients []wallet.Recipient, feeRate uint32) {
 	ctx := context.Background()
-	txMetadata, err := g.manager.PrepareTransaction(ctx, recipients, uint32(feeRate))
+	txMetadata, strategy, err := g.manager.PrepareTransaction(ctx, recipients, feeRate)
+	var insufficient *controller.InsufficientFundsError
+	if errors.As(err, &insufficient) {
+		g.showInsufficientFunds(insufficient, feeRate)
+		return
+	}
 	if err != nil {
-		dialog.ShowError(fmt.Errorf("failed to prepare transaction: %v", err), g.window)
+		g.showError(fmt.Errorf("failed to prepare transaction: %w", err))
 		return
 	}
 
 	// Show transaction details
-	g.showTransactionDetails(txMetadata, recipients)
+	g.showTransactionDetails(txMetadata, recipients, strategy, feeRate)
+}
+
+// showInsufficientFunds explains why a send does not fit and what would
+func (g *MainGUI) showInsufficientFunds(e *controller.InsufficientFundsError, feeRate uint32) {
+	message := fmt.Sprintf(
+		"Spendable balance: %s\nRequested amount: %s\nEstimated fee at %d sat/vB: %s\n\n",
+		FormatSatoshiUint64(e.Spendable),
+		FormatSatoshiUint64(e.Requested),
+		feeRate,
+		FormatSatoshiUint64(e.EstimatedFee),
+	)
+	if e.MaxSendable > 0 {
+		message += fmt.Sprintf("At this fee rate you can send at most %s.", FormatSatoshiUint64(e.MaxSendable))
+	} else {
+		message += "The balance does not cover the fee at this fee rate."
+	}
+	dialog.ShowInformation("Insufficient Funds", message, g.window)
 }
 
 func (g *MainGUI) showTransactionDetails(
 	txMetadata *wallet.TxMetadata,
 	recipients []wallet.Recipient,
+	strategy controller.CoinSelectionStrategy,
+	requestedFeeRate uint32,
 ) {
-	// Calculate net amount (sum of recipient amounts)
-	var netAmount int64
 	var totalSent uint64
 	for _, recipient := range recipients {
 		totalSent += recipient.GetAmount()
-		if recipient.IsChange() {
-			logging.L.Debug().Msg("change detected")
-			continue
+	}
+
+	// Net amount is what leaves the wallet. Outputs back to the wallet, change and self-sends
+	// to the main or a labeled address, are found by scanning the outputs like the scanner would.
+	var netAmount int64
+	if txMetadata.Tx != nil {
+		owned, err := g.manager.OwnedOutputs(txMetadata.Tx)
+		if err != nil {
+			logging.L.Err(err).Msg("failed to find own outputs, counting all outputs as sent")
 		}
-		if recipient.GetAddress() == g.manager.GetSilentPaymentAddress() {
-			logging.L.Debug().Msg("self-transfer detected")
-			continue
+		for vout, txOut := range txMetadata.Tx.TxOut {
+			if owned[uint32(vout)] {
+				logging.L.Debug().Int("vout", vout).Msg("output pays back to the wallet")
+				continue
+			}
+			netAmount += txOut.Value
 		}
-		netAmount += int64(recipient.GetAmount())
 	}
 
 	// Calculate actual fee and fee rate
 	var fee uint64
 	var feeRate uint64
 	var feeRateFloat float64
+	var droppedChange uint64
 	if txMetadata.Tx != nil {
 		// Calculate total output value
 		var outputSum uint64
@@ -244,12 +512,9 @@ func (g *MainGUI) showTransactionDetails(
 			var foundUtxo bool
 
 			// Find the UTXO being spent
-			for _, utxo := range g.manager.Wallet.GetUTXOs() {
-				txidMatch := bytes.Equal(
-					utils.ReverseBytesCopy(utxo.Txid[:]),
-					txIn.PreviousOutPoint.Hash[:],
-				)
-				if txidMatch && utxo.Vout == txIn.PreviousOutPoint.Index {
+			spent := controller.OutpointKey(txIn.PreviousOutPoint)
+			for _, utxo := range g.manager.GetUTXOs() {
+				if utxo.SerialiseToOutpoint() == spent {
 					inputSum += utxo.Amount
 					foundUtxo = true
 					break
@@ -273,6 +538,8 @@ func (g *MainGUI) showTransactionDetails(
 		vbytes := controller.CalculateTxVBytes(txMetadata.Tx)
 		feeRateFloat = controller.CalculateFeeRate(fee, vbytes)
 		feeRate = uint64(feeRateFloat)
+
+		droppedChange = controller.DroppedChange(txMetadata.Tx, len(recipients), fee, requestedFeeRate)
 	}
 	logging.L.Info().
 		Int64("netAmount", netAmount).
@@ -282,12 +549,19 @@ func (g *MainGUI) showTransactionDetails(
 		Msg("transaction details")
 
 	// Build a clean two-column summary grid
-	labels := []string{"Net Amount:", "Fee:", "Fee Rate:", "Total:"}
+	var inputCount int
+	if txMetadata.Tx != nil {
+		inputCount = len(txMetadata.Tx.TxIn)
+	}
+
+	labels := []string{"Net Amount:", "Fee:", "Fee Rate:", "Total:", "Inputs:", "Coin Selection:"}
 	values := []string{
 		FormatSatoshi(netAmount),
 		FormatSatoshiUint64(fee),
 		fmt.Sprintf("%.2f sat/vB", feeRateFloat),
 		FormatSatoshiUint64(totalSent + fee),
+		FormatNumber(int64(inputCount)),
+		string(strategy),
 	}
 
 	var gridObjects []fyne.CanvasObject
@@ -305,13 +579,7 @@ func (g *MainGUI) showTransactionDetails(
 	var confirmBtn *widget.Button
 	if txMetadata.Tx != nil {
 		txID := controller.GetTxID(txMetadata.Tx)
-		alreadyBroadcast := false
-		for _, existingTx := range g.manager.TransactionHistory {
-			if bytes.Equal(existingTx.TxID[:], txID[:]) {
-				alreadyBroadcast = true
-				break
-			}
-		}
+		alreadyBroadcast := g.manager.HasTransaction(txID)
 
 		confirmBtn = widget.NewButton("Confirm & Broadcast", func() {
 			g.broadcastTransaction(txMetadata, recipients, confirmBtn)
@@ -320,6 +588,9 @@ func (g *MainGUI) showTransactionDetails(
 		if alreadyBroadcast {
 			confirmBtn.Disable()
 			confirmBtn.SetText("Already Broadcast")
+		} else if g.manager.IsOffline() {
+			confirmBtn.Disable()
+			confirmBtn.SetText("Offline")
 		}
 	} else {
 		confirmBtn = widget.NewButton("Confirm & Broadcast", func() {
@@ -330,17 +601,136 @@ func (g *MainGUI) showTransactionDetails(
 
 	grid := container.NewGridWithColumns(2, gridObjects...)
 
+	// inputs, outputs and raw hex for a close look before confirming
+	inspectBtn := widget.NewButton("Inspect", func() {
+		g.showDecodedTransaction(txMetadata.Tx)
+	})
+
+	// PSBT export for verification in other tools
+	copyPSBTBtn := widget.NewButton("Copy PSBT", func() {
+		encoded, err := g.manager.EncodePSBT(txMetadata.Tx)
+		if err != nil {
+			logging.L.Err(err).Msg("failed to encode psbt")
+			dialog.ShowError(fmt.Errorf("failed to create PSBT: %v", err), g.window)
+			return
+		}
+		g.window.Clipboard().SetContent(encoded)
+		dialog.ShowInformation("Copied", "PSBT copied to clipboard (base64)", g.window)
+	})
+	// broadcast later via showBroadcastPSBTDialog
+	savePSBTBtn := widget.NewButton("Save PSBT", func() {
+		g.savePSBT(txMetadata.Tx)
+	})
+	if txMetadata.Tx == nil {
+		inspectBtn.Disable()
+		copyPSBTBtn.Disable()
+		savePSBTBtn.Disable()
+	}
+
 	content := container.NewVBox(
 		title,
 		widget.NewSeparator(),
-		grid,
-		widget.NewSeparator(),
-		container.NewHBox(layout.NewSpacer(), confirmBtn, layout.NewSpacer()),
+	)
+	// the destination comes first so a wrong paste stands out before confirming
+	if destinations := g.newDestinationRows(recipients); destinations != nil {
+		content.Add(destinations)
+		content.Add(widget.NewSeparator())
+	}
+	content.Add(grid)
+	content.Add(widget.NewSeparator())
+
+	// a remainder too small for a change output silently raises the fee
+	if droppedChange > 0 {
+		changeWarning := widget.NewLabel(fmt.Sprintf(
+			"No change output: the remaining %s are below the minimum change amount of %s "+
+				"and were added to the fee, which is above the requested %d sat/vB. "+
+				"Adjust the amount to avoid overpaying.",
+			FormatSatoshiUint64(droppedChange),
+			FormatSatoshiUint64(g.manager.MinChangeAmount),
+			requestedFeeRate,
+		))
+		changeWarning.Wrapping = fyne.TextWrapWord
+		changeWarning.Importance = widget.WarningImportance
+		content.Add(changeWarning)
+		content.Add(widget.NewSeparator())
+	}
+
+	content.Add(
+		container.NewHBox(
+			layout.NewSpacer(), inspectBtn, copyPSBTBtn, savePSBTBtn, confirmBtn, layout.NewSpacer(),
+		),
 	)
 
 	dialog.ShowCustom("Transaction Preview", "Close", content, g.window)
 }
 
+// newDestinationRows lists the addresses and amounts recipients pay, nil if there are none.
+// Long silent payment addresses are shortened, the copy button takes the full address.
+func (g *MainGUI) newDestinationRows(recipients []wallet.Recipient) fyne.CanvasObject {
+	rows := container.NewVBox(
+		widget.NewLabelWithStyle("Sending To", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+	)
+	var count int
+	for _, recipient := range recipients {
+		if recipient.IsChange() {
+			continue
+		}
+		count++
+		address := recipient.GetAddress()
+
+		addressLabel := widget.NewLabelWithStyle(
+			FormatAddress(address), fyne.TextAlignLeading, fyne.TextStyle{Bold: true, Monospace: true},
+		)
+		addressLabel.Wrapping = fyne.TextWrapBreak
+		addressLabel.Importance = widget.HighImportance
+		amountLabel := widget.NewLabelWithStyle(
+			FormatSatoshiUint64(recipient.GetAmount()), fyne.TextAlignTrailing, fyne.TextStyle{Bold: true},
+		)
+		copyBtn := widget.NewButtonWithIcon("", theme.ContentCopyIcon(), func() {
+			g.copyIDToClipboard("Address", address)
+		})
+
+		rows.Add(container.NewBorder(nil, nil, nil, container.NewHBox(amountLabel, copyBtn), addressLabel))
+	}
+	if count == 0 {
+		return nil
+	}
+	return rows
+}
+
+// savePSBT writes the transaction as a binary PSBT file (BIP 174)
+func (g *MainGUI) savePSBT(tx *wire.MsgTx) {
+	packet, err := g.manager.BuildPSBT(tx)
+	if err != nil {
+		logging.L.Err(err).Msg("failed to build psbt")
+		dialog.ShowError(fmt.Errorf("failed to create PSBT: %v", err), g.window)
+		return
+	}
+
+	saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, g.window)
+			return
+		}
+		if writer == nil {
+			// cancelled
+			return
+		}
+		defer writer.Close()
+
+		if err := packet.Serialize(writer); err != nil {
+			logging.L.Err(err).Msg("failed to write psbt")
+			dialog.ShowError(fmt.Errorf("failed to save PSBT: %v", err), g.window)
+			return
+		}
+		logging.L.Info().Str("path", writer.URI().Path()).Msg("saved psbt")
+	}, g.window)
+
+	txID := controller.GetTxID(tx)
+	saveDialog.SetFileName(fmt.Sprintf("%x.psbt", txID[:8]))
+	saveDialog.Show()
+}
+
 func (g *MainGUI) broadcastTransaction(
 	txMetadata *wallet.TxMetadata,
 	recipients []wallet.Recipient,
@@ -353,11 +743,9 @@ func (g *MainGUI) broadcastTransaction(
 
 	// Check if transaction already exists in history (prevent multiple broadcasts)
 	txID := controller.GetTxID(txMetadata.Tx)
-	for _, existingTx := range g.manager.TransactionHistory {
-		if bytes.Equal(existingTx.TxID[:], txID[:]) {
-			dialog.ShowError(fmt.Errorf("transaction has already been broadcast"), g.window)
-			return
-		}
+	if g.manager.HasTransaction(txID) {
+		dialog.ShowError(fmt.Errorf("transaction has already been broadcast"), g.window)
+		return
 	}
 
 	// Serialize transaction to hex
@@ -368,19 +756,58 @@ func (g *MainGUI) broadcastTransaction(
 	}
 
 	// Broadcast transaction
-	err = g.manager.BroadcastTransaction(txHex, g.manager.GetNetwork())
+	// the builder's recipients include the change, kept for a retry of an unknown outcome
+	allRecipients := txMetadata.AllRecipients
+	if allRecipients == nil {
+		allRecipients = recipients
+	}
+	broadcastTxID, err := g.manager.BroadcastTransaction(txHex, g.manager.GetNetwork(), allRecipients)
 	if err != nil {
 		logging.L.Err(err).Str("tx_hex", txHex).Msg("failed to broadcast")
-		dialog.ShowError(fmt.Errorf("failed to broadcast transaction: %v", err), g.window)
+
+		var rejected *controller.BroadcastRejectedError
+		if !errors.As(err, &rejected) {
+			if confirmBtn != nil && errors.Is(err, controller.ErrBroadcastOutcomeUnknown) {
+				confirmBtn.SetText("Retry Broadcast")
+			}
+			g.showError(fmt.Errorf("failed to broadcast transaction: %w", err))
+			return
+		}
+
+		// keep the transaction in a failed state, inputs stay unspent
+		if recordErr := g.manager.RecordFailedBroadcast(txMetadata.Tx, rejected.Reason); recordErr != nil {
+			logging.L.Err(recordErr).Msg("failed to record failed broadcast")
+		}
+		go func() {
+			if err := storage.SavePlain(g.manager.DataDir, g.manager); err != nil {
+				logging.L.Err(err).Msg("failed to save wallet")
+			}
+		}()
+
+		if confirmBtn != nil {
+			confirmBtn.SetText("Broadcast Failed - Retry")
+		}
+
+		dialog.ShowError(fmt.Errorf(
+			"the transaction was rejected by %s:\n\n%s\n\nIt was not recorded as sent and your coins remain unspent",
+			rejected.Backend, rejected.Reason,
+		), g.window)
 		return
 	}
 
+	if expected := hex.EncodeToString(txID[:]); broadcastTxID != "" && broadcastTxID != expected {
+		logging.L.Warn().
+			Str("expected", expected).
+			Str("reported", broadcastTxID).
+			Msg("broadcast backend reported a different txid")
+	}
+
 	// todo:mark inputs as spent
 
 	// Record transaction to history
 	err = g.manager.RecordSentTransaction(txMetadata, recipients)
 	if err != nil {
-		dialog.ShowError(fmt.Errorf("failed to record transaction: %v", err), g.window)
+		g.showError(fmt.Errorf("failed to record transaction: %w", err))
 		return
 	}
 
@@ -395,16 +822,7 @@ func (g *MainGUI) broadcastTransaction(
 	}
 
 	// Show success message
-	dialog.ShowInformation("Success", "Transaction broadcast successfully!", g.window)
-
-	// save wallet in the background
-	go func() {
-		err := storage.SavePlain(g.manager.DataDir, g.manager)
-		if err != nil {
-			logging.L.Err(err).Msg("failed to save wallet")
-			return
-		}
-	}()
+	dialog.ShowInformation("Success", fmt.Sprintf("Transaction broadcast successfully!\n\nTxID: %x", txID), g.window)
 
 	// TODO: Clear form
 }
@@ -1,8 +1,13 @@
 package gui
 
 import (
+	"errors"
 	"fmt"
+	"net/url"
 	"os"
+	"runtime"
+	"strconv"
+	"strings"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
@@ -10,10 +15,18 @@ import (
 	"fyne.io/fyne/v2/widget"
 
 	"github.com/setavenger/blindbit-desktop/internal/configs"
+	"github.com/setavenger/blindbit-desktop/internal/controller"
+	"github.com/setavenger/blindbit-desktop/internal/electrum"
+	"github.com/setavenger/blindbit-desktop/internal/logbuffer"
+	"github.com/setavenger/blindbit-desktop/internal/power"
 	"github.com/setavenger/blindbit-desktop/internal/storage"
 	"github.com/setavenger/blindbit-lib/logging"
 )
 
+// DebugLogging is set when the app was started with --debug,
+// the stored log level is not applied while it is
+var DebugLogging bool
+
 func (g *MainGUI) createSettingsTab() fyne.CanvasObject {
 	// Oracle address
 	oracleLabel := widget.NewLabel("Oracle Address:")
@@ -26,16 +39,111 @@ func (g *MainGUI) createSettingsTab() fyne.CanvasObject {
 	useTLSCheck.SetChecked(g.manager.OracleUseTLS)
 	useTLSContainer := container.NewHBox(useTLSLabel, useTLSCheck)
 
+	// Checks the address as entered, before it is saved
+	var testOracleBtn *widget.Button
+	testOracleBtn = widget.NewButton("Test Connection", func() {
+		address, useTLS := strings.TrimSpace(oracleEntry.Text), useTLSCheck.Checked
+		testOracleBtn.Disable()
+		go func() {
+			result, err := controller.TestOracleConnection(address, useTLS)
+			runOnMain(func() {
+				testOracleBtn.Enable()
+				g.showOracleTestResult(address, result, err)
+			})
+		}()
+	})
+
 	// Birth height
 	birthHeightLabel := widget.NewLabel("Birth Height:")
 	birthHeightEntry := widget.NewEntry()
 	birthHeightEntry.SetText(FormatHeightUint64(g.manager.GetBirthHeight()))
+	birthHeightByDateBtn := widget.NewButton("Set by Date...", func() {
+		showBirthHeightByDateDialog(g.window, g.manager, func(height uint64) {
+			birthHeightEntry.SetText(FormatHeightUint64(height))
+		})
+	})
 
 	// Dust limit
 	dustLimitLabel := widget.NewLabel("Dust Limit (satoshis):")
 	dustLimitEntry := widget.NewEntry()
 	dustLimitEntry.SetText(FormatNumber(int64(g.manager.DustLimit)))
 
+	// Confirmation target
+	confirmationTargetLabel := widget.NewLabel("Confirmations until a transaction counts as confirmed:")
+	confirmationTargetEntry := widget.NewEntry()
+	confirmationTargetEntry.SetText(FormatNumber(int64(g.manager.ConfirmationTarget)))
+
+	// Confirmations before a UTXO is spent
+	minConfirmationsLabel := widget.NewLabel("Confirmations before a UTXO can be spent:")
+	minConfirmationsEntry := widget.NewEntry()
+	minConfirmationsEntry.SetText(FormatNumber(int64(g.manager.MinConfirmations)))
+	minConfirmationsHint := widget.NewLabel("Younger UTXOs are left out of coin selection and shown as immature.")
+
+	// Lowest fee rate accepted when sending
+	minFeeRateLabel := widget.NewLabel("Minimum Fee Rate (sat/vB):")
+	minFeeRateEntry := widget.NewEntry()
+	minFeeRateEntry.SetText(FormatNumber(int64(g.manager.MinFeeRate)))
+	minFeeRateHint := widget.NewLabel(fmt.Sprintf(
+		"Nodes don't relay transactions below their minimum relay fee. Rates of %s sat/vB and more ask for confirmation.",
+		FormatNumber(configs.HighFeeRate),
+	))
+
+	// Save frequency while scanning
+	saveEveryBlocksLabel := widget.NewLabel("Save Wallet Every N Blocks While Scanning:")
+	saveEveryBlocksEntry := widget.NewEntry()
+	saveEveryBlocksEntry.SetText(FormatNumber(int64(g.manager.SaveEveryBlocks)))
+	saveIntervalLabel := widget.NewLabel("Save Wallet Every N Seconds While Scanning:")
+	saveIntervalEntry := widget.NewEntry()
+	saveIntervalEntry.SetText(FormatNumber(int64(g.manager.SaveIntervalSeconds)))
+	saveFrequencyHint := widget.NewLabel(
+		"Frequent saves mean less to rescan after a crash, but more disk writes.\n" +
+			"Takes effect on the next start.",
+	)
+
+	// GUI polling, applied right away
+	chainTipRefreshLabel := widget.NewLabel("Refresh Chain Tip Every N Seconds:")
+	chainTipRefreshEntry := widget.NewEntry()
+	chainTipRefreshEntry.SetText(FormatNumber(int64(g.chainTipRefreshInterval().Seconds())))
+	utxoRefreshLabel := widget.NewLabel("Refresh UTXOs Every N Seconds:")
+	utxoRefreshEntry := widget.NewEntry()
+	utxoRefreshEntry.SetText(FormatNumber(int64(g.utxoRefreshInterval().Seconds())))
+	refreshHint := widget.NewLabel(fmt.Sprintf(
+		"Longer intervals mean less traffic to the oracle on metered connections. At least %d seconds.",
+		configs.MinRefreshSeconds,
+	))
+
+	// Chain tip polling, empty picks a rate by address
+	tipPollRateLabel := widget.NewLabel("Chain Tip Polls per Second:")
+	tipPollRateEntry := widget.NewEntry()
+	tipPollRateEntry.SetPlaceHolder("Automatic")
+	if g.manager.TipPollsPerSecond > 0 {
+		tipPollRateEntry.SetText(strconv.FormatFloat(g.manager.TipPollsPerSecond, 'f', -1, 64))
+	}
+	tipPollRateHint := widget.NewLabel(fmt.Sprintf(
+		"Limits how often the chain tip is polled, block scanning is not limited. "+
+			"Leave empty for %g per second to public oracles and %g per second to one on localhost.",
+		float64(configs.DefaultTipPollsPerSecond), float64(configs.DefaultLocalTipPollsPerSecond),
+	))
+
+	// CPU share, applied right away
+	cpuLimitLabel := widget.NewLabel("CPU Limit (% of cores):")
+	cpuLimitEntry := widget.NewEntry()
+	cpuLimitEntry.SetText(FormatNumber(int64(g.manager.CPULimitPercent)))
+	cpuLimitHint := widget.NewLabel(fmt.Sprintf(
+		"Currently %d of %d cores. Lower values keep the computer responsive during heavy scans, "+
+			"but scanning takes longer.",
+		g.manager.CPULimitProcs(), runtime.NumCPU(),
+	))
+
+	// Labels scanned for besides change
+	labelCountLabel := widget.NewLabel("Label Count:")
+	labelCountEntry := widget.NewEntry()
+	labelCountEntry.SetText(FormatNumber(int64(g.manager.LabelCount)))
+	labelCountHint := widget.NewLabel(
+		"Scans for payments to the labeled addresses 1 to N as well.\n" +
+			"Payments received before the count was raised are only found by a rescan.",
+	)
+
 	// Min change amount
 	minChangeLabel := widget.NewLabel("Min Change Amount (satoshis):")
 	minChangeEntry := widget.NewEntry()
@@ -53,16 +161,145 @@ func (g *MainGUI) createSettingsTab() fyne.CanvasObject {
 			"rates. This can be used to fingerprint you. Leave off for best privacy.",
 	)
 
+	// Block explorer, stored per network
+	explorerLabel := widget.NewLabel(fmt.Sprintf("Block Explorer (%s):", g.manager.GetNetwork()))
+	explorerEntry := widget.NewEntry()
+	explorerEntry.SetText(g.manager.ExplorerURL())
+	explorerEntry.SetPlaceHolder(configs.GetMempoolSpaceURL(g.manager.GetNetwork()))
+	explorerHint := widget.NewLabel(
+		"Base URL of a mempool or esplora instance, or a template with {txid} and {vout}.\n" +
+			"Explorer lookups reveal your transactions to its operator. Leave empty for mempool.space.",
+	)
+
+	// Window close behaviour
+	keepRunningCheck := widget.NewCheck("Keep running in tray on close", nil)
+	keepRunningCheck.SetChecked(g.manager.KeepRunningInTray)
+	keepRunningHint := widget.NewLabel(
+		"Closing the window hides it and scanning continues. Use Quit in the tray menu to exit.",
+	)
+
+	// Theme, applied right away and kept in the app preferences
+	themeLabel := widget.NewLabel("Theme:")
+	themeSelect := widget.NewSelect(themeOptions, nil)
+	themeSelect.SetSelected(g.app.Preferences().StringWithFallback(prefTheme, themeSystem))
+	themeSelect.OnChanged = func(option string) {
+		setTheme(g.app, option)
+	}
+
+	// UI scale for high-DPI screens and low vision, applied like the theme
+	scaleLabel := widget.NewLabel("UI Scale:")
+	scaleSelect := widget.NewSelect(scaleOptions, nil)
+	scaleSelect.SetSelected(currentScaleOption(g.app))
+	scaleSelect.OnChanged = func(option string) {
+		if err := setScale(g.app, option); err != nil {
+			dialog.ShowError(err, g.window)
+		}
+	}
+
+	// Scanning on battery, only offered where the power source can be read
+	pauseOnBatteryCheck := widget.NewCheck("Pause scanning while on battery", nil)
+	pauseOnBatteryCheck.SetChecked(g.manager.PauseScanningOnBattery)
+	pauseOnBatteryHint := widget.NewLabel("Saves energy on laptops, scanning resumes once plugged in.")
+	if !power.Supported() {
+		pauseOnBatteryCheck.Disable()
+		pauseOnBatteryHint.SetText("Not available, the power source of this computer can't be read.")
+	}
+
+	// Logging, applied on save
+	logLevelLabel := widget.NewLabel("Log Level:")
+	logLevelSelect := widget.NewSelect(configs.LogLevels, nil)
+	if g.manager.LogLevel == "" {
+		logLevelSelect.SetSelected(configs.DefaultLogLevel)
+	} else {
+		logLevelSelect.SetSelected(g.manager.LogLevel)
+	}
+	logToFileCheck := widget.NewCheck("Write logs to file", nil)
+	logToFileCheck.SetChecked(g.manager.LogToFile)
+	logPathLabel := widget.NewLabel(configs.LogFilePath(g.manager.DataDir))
+	logPathLabel.TextStyle.Monospace = true
+	logPathLabel.Wrapping = fyne.TextWrapBreak
+	openLogsBtn := widget.NewButton("Open Logs", g.openLogFile)
+
+	// Coin selection
+	coinSelectionLabel := widget.NewLabel("Coin Selection:")
+	var coinSelectionOptions []string
+	for _, strategy := range controller.CoinSelectionStrategies {
+		coinSelectionOptions = append(coinSelectionOptions, string(strategy))
+	}
+	coinSelectionSelect := widget.NewSelect(coinSelectionOptions, nil)
+	if g.manager.CoinSelectionStrategy == "" {
+		coinSelectionSelect.SetSelected(string(controller.CoinSelectionLargestFirst))
+	} else {
+		coinSelectionSelect.SetSelected(string(g.manager.CoinSelectionStrategy))
+	}
+	coinSelectionHint := widget.NewLabel(
+		"largest-first uses few inputs, smallest-first sweeps small UTXOs (consolidation),\n" +
+			"branch-and-bound avoids a change output where possible.",
+	)
+
+	// Broadcast backend
+	broadcastBackendLabel := widget.NewLabel("Broadcast Backend:")
+	electrumLabel := widget.NewLabel("Electrum Server (ssl://host:port or tcp://host:port):")
+	electrumEntry := widget.NewEntry()
+	electrumEntry.SetText(g.manager.ElectrumAddress)
+	electrumEntry.SetPlaceHolder(configs.DefaultElectrumAddressForNetwork(g.manager.GetNetwork()))
+	broadcastBackendSelect := widget.NewSelect(
+		[]string{
+			string(controller.BroadcastBackendMempoolSpace),
+			string(controller.BroadcastBackendElectrum),
+		},
+		func(value string) {
+			if value == string(controller.BroadcastBackendElectrum) {
+				electrumEntry.Enable()
+			} else {
+				electrumEntry.Disable()
+			}
+		},
+	)
+	if g.manager.BroadcastBackend == controller.BroadcastBackendElectrum {
+		broadcastBackendSelect.SetSelected(string(controller.BroadcastBackendElectrum))
+	} else {
+		broadcastBackendSelect.SetSelected(string(controller.BroadcastBackendMempoolSpace))
+	}
+
 	// Save button
-	saveBtn := widget.NewButton("Save Settings", func() {
-		g.saveSettings(
-			oracleEntry.Text,
-			birthHeightEntry.Text,
-			dustLimitEntry.Text,
-			minChangeEntry.Text,
-			useTLSCheck.Checked,
-			feeEstimationCheck.Checked,
-		)
+	var saveBtn *widget.Button
+	saveBtn = widget.NewButton("Save Settings", func() {
+		birthHeightText := birthHeightEntry.Text
+		saveBtn.Disable()
+
+		go func() {
+			birthHeight := g.checkBirthHeightInput(birthHeightText)
+			runOnMain(func() {
+				saveBtn.Enable()
+				g.saveSettings(settingsForm{
+					oracleAddress:      oracleEntry.Text,
+					useTLS:             useTLSCheck.Checked,
+					birthHeight:        birthHeight,
+					dustLimit:          dustLimitEntry.Text,
+					minChange:          minChangeEntry.Text,
+					labelCount:         labelCountEntry.Text,
+					confirmationTarget: confirmationTargetEntry.Text,
+					minConfirmations:   minConfirmationsEntry.Text,
+					minFeeRate:         minFeeRateEntry.Text,
+					saveEveryBlocks:    saveEveryBlocksEntry.Text,
+					saveInterval:       saveIntervalEntry.Text,
+					chainTipRefresh:    chainTipRefreshEntry.Text,
+					utxoRefresh:        utxoRefreshEntry.Text,
+					tipPollRate:        tipPollRateEntry.Text,
+					cpuLimit:           cpuLimitEntry.Text,
+					electrumAddress:    electrumEntry.Text,
+					explorerURL:        explorerEntry.Text,
+					broadcastBackend:   controller.BroadcastBackend(broadcastBackendSelect.Selected),
+					coinSelection:      controller.CoinSelectionStrategy(coinSelectionSelect.Selected),
+					logLevel:           logLevelSelect.Selected,
+					feeEstimation:      feeEstimationCheck.Checked,
+					keepRunningInTray:  keepRunningCheck.Checked,
+					pauseOnBattery:     pauseOnBatteryCheck.Checked,
+					logToFile:          logToFileCheck.Checked,
+				})
+			})
+		}()
 	})
 
 	// Reset button
@@ -72,21 +309,45 @@ func (g *MainGUI) createSettingsTab() fyne.CanvasObject {
 			birthHeightEntry,
 			dustLimitEntry,
 			minChangeEntry,
+			labelCountEntry,
+			confirmationTargetEntry,
+			minConfirmationsEntry,
+			minFeeRateEntry,
+			saveEveryBlocksEntry,
+			saveIntervalEntry,
+			chainTipRefreshEntry,
+			utxoRefreshEntry,
+			tipPollRateEntry,
+			cpuLimitEntry,
+			electrumEntry,
+			explorerEntry,
 			useTLSCheck,
 			feeEstimationCheck,
+			keepRunningCheck,
+			pauseOnBatteryCheck,
+			logToFileCheck,
+			broadcastBackendSelect,
+			coinSelectionSelect,
+			logLevelSelect,
+			themeSelect,
+			scaleSelect,
 		)
 	})
 
+	// Public keys and address, read only
+	accountInfoBtn := widget.NewButton("Account Info", g.showAccountInfo)
+	recoveryPhraseBtn := widget.NewButton("Show Recovery Phrase", g.showRecoveryPhrase)
+
 	// Form layout
 	form := container.NewVBox(
 		widget.NewLabel("Wallet Settings"),
 		widget.NewSeparator(),
 		oracleLabel,
-		oracleEntry,
+		container.NewBorder(nil, nil, nil, testOracleBtn, oracleEntry),
 		useTLSContainer,
 		widget.NewSeparator(),
 		birthHeightLabel,
-		birthHeightEntry,
+		container.NewBorder(nil, nil, nil, birthHeightByDateBtn, birthHeightEntry),
 		widget.NewSeparator(),
 		dustLimitLabel,
 		dustLimitEntry,
@@ -94,51 +355,297 @@ func (g *MainGUI) createSettingsTab() fyne.CanvasObject {
 		minChangeLabel,
 		minChangeEntry,
 		widget.NewSeparator(),
+		labelCountLabel,
+		labelCountEntry,
+		labelCountHint,
+		widget.NewSeparator(),
+		confirmationTargetLabel,
+		confirmationTargetEntry,
+		widget.NewSeparator(),
+		minConfirmationsLabel,
+		minConfirmationsEntry,
+		minConfirmationsHint,
+		widget.NewSeparator(),
+		minFeeRateLabel,
+		minFeeRateEntry,
+		minFeeRateHint,
+		widget.NewSeparator(),
+		saveEveryBlocksLabel,
+		saveEveryBlocksEntry,
+		saveIntervalLabel,
+		saveIntervalEntry,
+		saveFrequencyHint,
+		widget.NewSeparator(),
+		chainTipRefreshLabel,
+		chainTipRefreshEntry,
+		utxoRefreshLabel,
+		utxoRefreshEntry,
+		refreshHint,
+		widget.NewSeparator(),
+		tipPollRateLabel,
+		tipPollRateEntry,
+		tipPollRateHint,
+		widget.NewSeparator(),
+		cpuLimitLabel,
+		cpuLimitEntry,
+		cpuLimitHint,
+		widget.NewSeparator(),
+		coinSelectionLabel,
+		coinSelectionSelect,
+		coinSelectionHint,
+		widget.NewSeparator(),
 		feeEstimationLabel,
 		feeEstimationCheck,
 		feeEstimationHint,
 		widget.NewSeparator(),
+		broadcastBackendLabel,
+		broadcastBackendSelect,
+		electrumLabel,
+		electrumEntry,
+		widget.NewSeparator(),
+		explorerLabel,
+		explorerEntry,
+		explorerHint,
+		widget.NewSeparator(),
+		keepRunningCheck,
+		keepRunningHint,
+		widget.NewSeparator(),
+		pauseOnBatteryCheck,
+		pauseOnBatteryHint,
+		widget.NewSeparator(),
+		themeLabel,
+		themeSelect,
+		scaleLabel,
+		scaleSelect,
+		widget.NewSeparator(),
+		logLevelLabel,
+		logLevelSelect,
+		logToFileCheck,
+		container.NewBorder(nil, nil, nil, openLogsBtn, logPathLabel),
+		widget.NewSeparator(),
 		container.NewHBox(resetBtn, saveBtn),
+		widget.NewSeparator(),
+		widget.NewLabel("Account"),
+		container.NewHBox(accountInfoBtn, recoveryPhraseBtn),
 	)
 
 	return form
 }
 
-func (g *MainGUI) saveSettings(
-	oracleAddr, birthHeightStr, dustLimitStr, minChangeStr string,
-	useTLS bool,
-	feeEstimationEnabled bool,
-) {
-	// Parse birth height
-	if birthHeightStr != "" {
-		if height, err := ParseFormattedUint64(birthHeightStr); err == nil {
-			g.manager.SetBirthHeight(height, false)
-		} else {
-			dialog.ShowError(fmt.Errorf("invalid birth height: %v", err), g.window)
-			return
+// birthHeightInput is the birth height from the settings form after validation
+type birthHeightInput struct {
+	height  uint64
+	changed bool
+	warning string
+	err     error
+}
+
+// checkBirthHeightInput parses text and validates it if it differs from the current birth height.
+// Validation asks the oracle for the chain tip, call it off the UI thread.
+func (g *MainGUI) checkBirthHeightInput(text string) birthHeightInput {
+	if text == "" {
+		return birthHeightInput{}
+	}
+	height, err := ParseFormattedUint64(text)
+	if err != nil {
+		return birthHeightInput{err: err}
+	}
+	if height == g.manager.GetBirthHeight() {
+		return birthHeightInput{height: height}
+	}
+	height, warning, err := g.manager.ValidateBirthHeight(height)
+	return birthHeightInput{height: height, changed: true, warning: warning, err: err}
+}
+
+// settingsForm holds the settings form as entered, see parse
+type settingsForm struct {
+	oracleAddress string
+	useTLS        bool
+	// parsed and, if it changed, validated by checkBirthHeightInput
+	birthHeight birthHeightInput
+
+	dustLimit, minChange, labelCount                 string
+	confirmationTarget, minConfirmations, minFeeRate string
+	saveEveryBlocks, saveInterval                    string
+	chainTipRefresh, utxoRefresh                     string
+	tipPollRate, cpuLimit                            string
+	electrumAddress, explorerURL                     string
+
+	broadcastBackend controller.BroadcastBackend
+	coinSelection    controller.CoinSelectionStrategy
+	logLevel         string
+
+	feeEstimation     bool
+	keepRunningInTray bool
+	pauseOnBattery    bool
+	logToFile         bool
+}
+
+// walletSettings are the settings of a valid settingsForm
+type walletSettings struct {
+	form settingsForm
+
+	dustLimit          int
+	minChange          uint64
+	labelCount         int
+	confirmationTarget int
+	minConfirmations   int
+	minFeeRate         int
+	saveEveryBlocks    int
+	saveInterval       int
+	chainTipRefresh    int
+	utxoRefresh        int
+	// 0 picks a rate by oracle address
+	tipPollRate float64
+	cpuLimit    int
+	explorerURL string
+}
+
+// parse validates every field, the first invalid one is returned as error
+func (f settingsForm) parse() (*walletSettings, error) {
+	if f.birthHeight.err != nil {
+		return nil, fmt.Errorf("invalid birth height: %v", f.birthHeight.err)
+	}
+
+	s := &walletSettings{form: f}
+
+	dustLimit, err := ParseFormattedNumber(f.dustLimit)
+	if err != nil {
+		return nil, fmt.Errorf("invalid dust limit: %v", err)
+	}
+	s.dustLimit = int(dustLimit)
+
+	s.minChange, err = ParseFormattedUint64(f.minChange)
+	if err != nil {
+		return nil, fmt.Errorf("invalid min change amount: %v", err)
+	}
+
+	labelCount, err := ParseFormattedNumber(f.labelCount)
+	if err != nil || labelCount < 0 || labelCount > configs.MaxLabelCount {
+		return nil, fmt.Errorf("invalid label count: must be a number from 0 to %d", configs.MaxLabelCount)
+	}
+	s.labelCount = int(labelCount)
+
+	confirmationTarget, err := ParseFormattedNumber(f.confirmationTarget)
+	if err != nil || confirmationTarget < 1 {
+		return nil, fmt.Errorf("invalid confirmation target: must be a number of at least 1")
+	}
+	s.confirmationTarget = int(confirmationTarget)
+
+	minConfirmations, err := ParseFormattedNumber(f.minConfirmations)
+	if err != nil || minConfirmations < 1 {
+		return nil, fmt.Errorf("invalid confirmations before spending: must be a number of at least 1")
+	}
+	s.minConfirmations = int(minConfirmations)
+
+	// 0 would allow transactions no node relays
+	minFeeRate, err := ParseFormattedNumber(f.minFeeRate)
+	if err != nil || minFeeRate < 1 {
+		return nil, fmt.Errorf("invalid minimum fee rate: must be a number of at least 1")
+	}
+	s.minFeeRate = int(minFeeRate)
+
+	saveEveryBlocks, err := ParseFormattedNumber(f.saveEveryBlocks)
+	if err != nil || saveEveryBlocks < 1 {
+		return nil, fmt.Errorf("invalid save interval: blocks must be a number of at least 1")
+	}
+	s.saveEveryBlocks = int(saveEveryBlocks)
+	saveInterval, err := ParseFormattedNumber(f.saveInterval)
+	if err != nil || saveInterval < 1 {
+		return nil, fmt.Errorf("invalid save interval: seconds must be a number of at least 1")
+	}
+	s.saveInterval = int(saveInterval)
+
+	// short refresh intervals would hammer the oracle
+	chainTipRefresh, err := ParseFormattedNumber(f.chainTipRefresh)
+	if err != nil || chainTipRefresh < configs.MinRefreshSeconds {
+		return nil, fmt.Errorf(
+			"invalid chain tip refresh: seconds must be a number of at least %d", configs.MinRefreshSeconds,
+		)
+	}
+	s.chainTipRefresh = int(chainTipRefresh)
+	utxoRefresh, err := ParseFormattedNumber(f.utxoRefresh)
+	if err != nil || utxoRefresh < configs.MinRefreshSeconds {
+		return nil, fmt.Errorf(
+			"invalid UTXO refresh: seconds must be a number of at least %d", configs.MinRefreshSeconds,
+		)
+	}
+	s.utxoRefresh = int(utxoRefresh)
+
+	if tipPollRate := strings.TrimSpace(f.tipPollRate); tipPollRate != "" {
+		s.tipPollRate, err = strconv.ParseFloat(tipPollRate, 64)
+		if err != nil || s.tipPollRate <= 0 {
+			return nil, fmt.Errorf("invalid tip polling rate: must be a positive number or empty")
 		}
 	}
 
-	// Parse dust limit
-	if dustLimit, err := ParseFormattedNumber(dustLimitStr); err == nil {
-		g.manager.DustLimit = int(dustLimit)
-	} else {
-		dialog.ShowError(fmt.Errorf("invalid dust limit: %v", err), g.window)
-		return
+	cpuLimit, err := ParseFormattedNumber(f.cpuLimit)
+	if err != nil || cpuLimit < 1 || cpuLimit > 100 {
+		return nil, fmt.Errorf("invalid CPU limit: must be a percentage from 1 to 100")
 	}
+	s.cpuLimit = int(cpuLimit)
 
-	// Parse min change amount
-	if minChange, err := ParseFormattedUint64(minChangeStr); err == nil {
-		g.manager.MinChangeAmount = minChange
-	} else {
-		dialog.ShowError(fmt.Errorf("invalid min change amount: %v", err), g.window)
-		return
+	if f.broadcastBackend == controller.BroadcastBackendElectrum {
+		if _, _, err := electrum.ParseServerURL(f.electrumAddress); err != nil {
+			return nil, fmt.Errorf("invalid electrum server: %v", err)
+		}
 	}
 
-	// Set oracle address
-	g.manager.OracleAddress = oracleAddr
-	g.manager.OracleUseTLS = useTLS
-	g.manager.FeeEstimationEnabled = feeEstimationEnabled
+	s.explorerURL = strings.TrimSpace(f.explorerURL)
+	if err := configs.ValidateExplorerURL(s.explorerURL); err != nil {
+		return nil, fmt.Errorf("invalid block explorer: %v", err)
+	}
+
+	return s, nil
+}
+
+// applySettings puts valid settings in place.
+// Returns whether the label count was raised, payments to the new labels need a rescan.
+func (g *MainGUI) applySettings(s *walletSettings) (labelsAdded bool) {
+	if s.form.birthHeight.changed {
+		g.manager.SetBirthHeight(s.form.birthHeight.height, false)
+	}
+	g.manager.DustLimit = s.dustLimit
+	g.manager.MinChangeAmount = s.minChange
+	labelsAdded = s.labelCount > g.manager.LabelCount
+	g.manager.LabelCount = s.labelCount
+	g.manager.ConfirmationTarget = s.confirmationTarget
+	g.manager.MinConfirmations = s.minConfirmations
+	g.manager.MinFeeRate = s.minFeeRate
+
+	g.manager.OracleAddress = s.form.oracleAddress
+	g.manager.OracleUseTLS = s.form.useTLS
+	g.manager.FeeEstimationEnabled = s.form.feeEstimation
+	g.manager.BroadcastBackend = s.form.broadcastBackend
+	g.manager.ElectrumAddress = s.form.electrumAddress
+	g.manager.CoinSelectionStrategy = s.form.coinSelection
+	g.manager.KeepRunningInTray = s.form.keepRunningInTray
+	g.manager.PauseScanningOnBattery = s.form.pauseOnBattery
+	// reads the power source, pmset is an external command on macOS
+	go g.manager.CheckPowerSource()
+	g.manager.SetExplorerURL(s.explorerURL)
+	g.manager.SaveEveryBlocks = s.saveEveryBlocks
+	g.manager.SaveIntervalSeconds = s.saveInterval
+	g.manager.ChainTipRefreshSeconds = s.chainTipRefresh
+	g.manager.UTXORefreshSeconds = s.utxoRefresh
+	g.applyRefreshIntervals()
+	g.manager.TipPollsPerSecond = s.tipPollRate
+	g.manager.CPULimitPercent = s.cpuLimit
+	g.manager.ApplyCPULimit()
+	g.manager.LogLevel = s.form.logLevel
+	g.manager.LogToFile = s.form.logToFile
+	g.applyLogSettings()
+	return labelsAdded
+}
+
+// saveSettings applies and saves form, an invalid field leaves the settings untouched
+func (g *MainGUI) saveSettings(form settingsForm) {
+	settings, err := form.parse()
+	if err != nil {
+		dialog.ShowError(err, g.window)
+		return
+	}
+	labelsAdded := g.applySettings(settings)
 
 	// Save the manager
 	if err := storage.SavePlain(g.manager.DataDir, g.manager); err != nil {
@@ -148,17 +655,64 @@ func (g *MainGUI) saveSettings(
 	}
 
 	// Show success message
-	dialog.ShowInformation("Success", "Settings saved successfully!", g.window)
-	g.askForShutdown()
+	message := "Settings saved successfully!"
+	if form.birthHeight.warning != "" {
+		message += "\n\nWarning: " + form.birthHeight.warning
+	}
+	if !g.manager.NeedsReconnect() {
+		dialog.ShowInformation("Success", message, g.window)
+		return
+	}
+
+	// oracle and save settings are applied by restarting the scanner in place
+	go func() {
+		err := g.manager.ReconnectOracle(controller.DefaultShutdownTimeout)
+		runOnMain(func() {
+			switch {
+			case errors.Is(err, controller.ErrOracleNetworkMismatch):
+				dialog.ShowError(fmt.Errorf("settings saved, but scanning is disabled: %w", err), g.window)
+			case err != nil:
+				logging.L.Err(err).Msg("failed to reconnect to oracle")
+				dialog.ShowError(
+					fmt.Errorf("settings saved, but reconnecting failed, restart to apply them: %w", err),
+					g.window,
+				)
+			case labelsAdded:
+				g.confirmLabelRescan()
+			default:
+				dialog.ShowInformation("Success", message+"\n\nReconnected to the oracle.", g.window)
+			}
+		})
+	}()
 }
 
 func (g *MainGUI) resetToDefaults(
 	oracleEntry,
 	birthHeightEntry,
 	dustLimitEntry,
-	minChangeEntry *widget.Entry,
+	minChangeEntry,
+	labelCountEntry,
+	confirmationTargetEntry,
+	minConfirmationsEntry,
+	minFeeRateEntry,
+	saveEveryBlocksEntry,
+	saveIntervalEntry,
+	chainTipRefreshEntry,
+	utxoRefreshEntry,
+	tipPollRateEntry,
+	cpuLimitEntry,
+	electrumEntry,
+	explorerEntry *widget.Entry,
 	useTLSCheck,
-	feeEstimationCheck *widget.Check,
+	feeEstimationCheck,
+	keepRunningCheck,
+	pauseOnBatteryCheck,
+	logToFileCheck *widget.Check,
+	broadcastBackendSelect,
+	coinSelectionSelect,
+	logLevelSelect,
+	themeSelect,
+	scaleSelect *widget.Select,
 ) {
 	// Reset to default values
 	defaultOracleAddr := configs.DefaultOracleAddressForNetwork(g.manager.Wallet.Network)
@@ -174,31 +728,128 @@ func (g *MainGUI) resetToDefaults(
 	minChangeEntry.SetText(fmt.Sprintf("%d", configs.DefaultMinimumAmount))
 	g.manager.MinChangeAmount = configs.DefaultMinimumAmount
 
+	labelCountEntry.SetText(fmt.Sprintf("%d", configs.DefaultLabelCount))
+	g.manager.LabelCount = configs.DefaultLabelCount
+
+	confirmationTargetEntry.SetText(fmt.Sprintf("%d", configs.DefaultConfirmationTarget))
+	g.manager.ConfirmationTarget = configs.DefaultConfirmationTarget
+
+	minConfirmationsEntry.SetText(fmt.Sprintf("%d", configs.DefaultMinConfirmations))
+	g.manager.MinConfirmations = configs.DefaultMinConfirmations
+
+	minFeeRateEntry.SetText(fmt.Sprintf("%d", configs.DefaultMinFeeRate))
+	g.manager.MinFeeRate = configs.DefaultMinFeeRate
+
+	saveEveryBlocksEntry.SetText(fmt.Sprintf("%d", configs.DefaultSaveEveryBlocks))
+	g.manager.SaveEveryBlocks = configs.DefaultSaveEveryBlocks
+
+	saveIntervalEntry.SetText(fmt.Sprintf("%d", configs.DefaultSaveIntervalSeconds))
+	g.manager.SaveIntervalSeconds = configs.DefaultSaveIntervalSeconds
+
+	chainTipRefreshEntry.SetText(fmt.Sprintf("%d", configs.DefaultChainTipRefreshSeconds))
+	g.manager.ChainTipRefreshSeconds = configs.DefaultChainTipRefreshSeconds
+
+	utxoRefreshEntry.SetText(fmt.Sprintf("%d", configs.DefaultUTXORefreshSeconds))
+	g.manager.UTXORefreshSeconds = configs.DefaultUTXORefreshSeconds
+	g.applyRefreshIntervals()
+
+	tipPollRateEntry.SetText("")
+	g.manager.TipPollsPerSecond = 0
+
+	cpuLimitEntry.SetText(fmt.Sprintf("%d", configs.DefaultCPULimitPercent))
+	g.manager.CPULimitPercent = configs.DefaultCPULimitPercent
+	g.manager.ApplyCPULimit()
+
 	useTLSCheck.SetChecked(true)
 	g.manager.OracleUseTLS = true
 
 	feeEstimationCheck.SetChecked(true)
 	g.manager.FeeEstimationEnabled = true
 
+	keepRunningCheck.SetChecked(true)
+	g.manager.KeepRunningInTray = true
+
+	pauseOnBatteryCheck.SetChecked(false)
+	g.manager.PauseScanningOnBattery = false
+	go g.manager.CheckPowerSource()
+
+	broadcastBackendSelect.SetSelected(string(controller.BroadcastBackendMempoolSpace))
+	g.manager.BroadcastBackend = controller.BroadcastBackendMempoolSpace
+
+	coinSelectionSelect.SetSelected(string(controller.CoinSelectionLargestFirst))
+	g.manager.CoinSelectionStrategy = controller.CoinSelectionLargestFirst
+
+	defaultElectrumAddr := configs.DefaultElectrumAddressForNetwork(g.manager.Wallet.Network)
+	electrumEntry.SetText(defaultElectrumAddr)
+	g.manager.ElectrumAddress = defaultElectrumAddr
+
+	explorerEntry.SetText("")
+	g.manager.SetExplorerURL("")
+
+	logLevelSelect.SetSelected(configs.DefaultLogLevel)
+	g.manager.LogLevel = configs.DefaultLogLevel
+
+	logToFileCheck.SetChecked(true)
+	g.manager.LogToFile = true
+	g.applyLogSettings()
+
+	themeSelect.SetSelected(themeSystem)
+	scaleSelect.SetSelected("100%")
+
 	dialog.ShowInformation("Reset", "Settings reset to defaults", g.window)
 }
 
-func (g *MainGUI) askForShutdown() {
-	// Ask user to restart the application to apply changes fully
-	dialog.ShowCustomConfirm(
-		"Restart Required",
-		"Shutdown Now",
-		"Later",
-		widget.NewLabel("Some settings may require you to restart the program to take full effect. Shutdown?"),
-		func(confirmed bool) {
-			if confirmed {
-				os.Exit(0)
-			} else {
-				dialog.ShowInformation("Settings", "Settings saved. Restart later to apply all changes.", g.window)
-			}
-		},
-		g.window,
-	)
+func (g *MainGUI) showOracleTestResult(address string, result *controller.OracleTestResult, err error) {
+	if err != nil {
+		logging.L.Warn().Err(err).Str("address", address).Msg("oracle connection test failed")
+		dialog.ShowError(fmt.Errorf("connection to %s failed: %w", address, err), g.window)
+		return
+	}
+	if err := controller.CheckOracleNetwork(result.Network, g.manager.GetNetwork()); err != nil {
+		dialog.ShowError(fmt.Errorf("%s is reachable but cannot be used: %w", address, err), g.window)
+		return
+	}
+	dialog.ShowInformation("Oracle Connection OK", fmt.Sprintf(
+		"Address: %s\nNetwork: %s\nChain height: %s\nRound trip: %d ms",
+		address,
+		result.Network,
+		FormatHeightUint64(result.Height),
+		result.Latency.Milliseconds(),
+	), g.window)
+}
+
+// applyLogSettings switches log level and file logging without a restart
+func (g *MainGUI) applyLogSettings() {
+	level, err := configs.ParseLogLevel(g.manager.LogLevel)
+	if err != nil {
+		logging.L.Warn().Err(err).Msg("ignoring log level")
+	} else if !DebugLogging {
+		logging.SetLogLevel(level)
+	}
+
+	// re-enabling would open the file a second time
+	if logging.LogToFile == g.manager.LogToFile {
+		return
+	}
+	err = logging.SetFileLogging(g.manager.LogToFile, g.manager.DataDir, configs.LogFilename)
+	// switching rebuilds the logger without the log viewer's buffer
+	logbuffer.Attach()
+	if err != nil {
+		logging.L.Err(err).Msg("failed to switch file logging")
+	}
+}
+
+// openLogFile opens the log file with the system's default application
+func (g *MainGUI) openLogFile() {
+	path := configs.LogFilePath(g.manager.DataDir)
+	if _, err := os.Stat(path); err != nil {
+		dialog.ShowError(fmt.Errorf("no log file at %s, enable writing logs to file first", path), g.window)
+		return
+	}
+	if err := g.app.OpenURL(&url.URL{Scheme: "file", Path: path}); err != nil {
+		logging.L.Err(err).Str("path", path).Msg("failed to open log file")
+		dialog.ShowError(fmt.Errorf("failed to open log file: %v", err), g.window)
+	}
 }
 
 func (g *MainGUI) askForRestart() {
@@ -220,3 +871,24 @@ func (g *MainGUI) askForRestart() {
 		g.window,
 	)
 }
+
+// confirmLabelRescan offers a rescan from the birth height after the label count was raised,
+// payments to the new labels in blocks already scanned are missed otherwise
+func (g *MainGUI) confirmLabelRescan() {
+	birthHeight := g.manager.GetBirthHeight()
+	dialog.ShowConfirm(
+		"Rescan for New Labels?",
+		fmt.Sprintf(
+			"Settings saved and the scanner now looks for the new labels.\n\n"+
+				"Payments to them in blocks scanned before are only found by a rescan. "+
+				"Rescan from the birth height %s now?",
+			FormatHeightUint64(birthHeight),
+		),
+		func(ok bool) {
+			if ok {
+				g.startRescanning(int(birthHeight))
+			}
+		},
+		g.window,
+	)
+}
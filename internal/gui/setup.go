@@ -3,6 +3,8 @@ package gui
 import (
 	"errors"
 	"fmt"
+	"math/rand/v2"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -27,6 +29,8 @@ type SetupWizard struct {
 	onFinish           func(*controller.Manager)
 	currentBlockHeight uint64
 	currentNetwork     types.Network
+	// network is fixed from the command line, skips the network selection if set
+	network types.Network
 }
 
 func NewSetupWizard(
@@ -43,6 +47,11 @@ func NewSetupWizard(
 	}
 }
 
+// SetNetwork fixes the network for the new wallet, the network selection step is skipped
+func (s *SetupWizard) SetNetwork(network types.Network) {
+	s.network = network
+}
+
 func (s *SetupWizard) Show() {
 	s.showWelcomeDialog()
 }
@@ -121,17 +130,7 @@ This is your only way to recover your wallet if you lose access to this device.
 	titleText.Wrapping = fyne.TextWrapWord
 
 	// Format mnemonic with numbers for better visual distinction
-	words := strings.Fields(mnemonic)
-	var formattedLines []string
-	for i := 0; i < len(words); i += 4 {
-		var lineParts []string
-		for j := 0; j < 4 && i+j < len(words); j++ {
-			wordNum := i + j + 1
-			lineParts = append(lineParts, fmt.Sprintf("%2d. %-12s", wordNum, words[i+j]))
-		}
-		formattedLines = append(formattedLines, strings.Join(lineParts, "  "))
-	}
-	formattedMnemonic := strings.Join(formattedLines, "\n")
+	formattedMnemonic := formatMnemonicGrid(mnemonic)
 
 	// Display formatted mnemonic in a styled label with monospace font
 	mnemonicDisplay := widget.NewLabel(formattedMnemonic)
@@ -160,10 +159,15 @@ This is your only way to recover your wallet if you lose access to this device.
 	// Mnemonic section container
 	mnemonicSectionTitle := widget.NewLabel("Your Seed Phrase:")
 	mnemonicSectionTitle.TextStyle.Bold = true
+	// Printable word list and QR code for cold storage
+	paperBackupBtn := widget.NewButton("Paper Backup / QR Code", func() {
+		showPaperBackup(s.app, s.window, s.dataDir, mnemonic, network)
+	})
+
 	mnemonicSection := container.NewVBox(
 		mnemonicSectionTitle,
 		mnemonicDisplay,
-		copyBtn,
+		container.NewHBox(copyBtn, paperBackupBtn),
 		copyNotificationLabel,
 		widget.NewSeparator(),
 		blockHeightLabel,
@@ -176,10 +180,15 @@ This is your only way to recover your wallet if you lose access to this device.
 	confirmText.Wrapping = fyne.TextWrapWord
 
 	confirmBtn := widget.NewButton("I Have Written Down My Seed Phrase", func() {
-		s.showMnemonicConfirmation(mnemonic, network)
+		s.showMnemonicQuiz(mnemonic, network)
 	})
 
 	backBtn := widget.NewButton("Back", func() {
+		if s.network != "" {
+			// network selection is skipped, going back there would generate a new seed
+			s.showWelcomeDialog()
+			return
+		}
 		s.showNetworkSelection("")
 	})
 
@@ -197,6 +206,84 @@ This is your only way to recover your wallet if you lose access to this device.
 	s.window.Resize(fyne.NewSize(650, 600))
 }
 
+// quizQuestions is the number of word positions the seed quiz asks for
+const quizQuestions = 4
+
+// quizChoices is the number of options offered per position, the correct word included
+const quizChoices = 4
+
+// showMnemonicQuiz asks for the words at a few random positions instead of the whole phrase.
+// Typing the full phrase stays available as fallback.
+func (s *SetupWizard) showMnemonicQuiz(mnemonic string, network types.Network) {
+	words := strings.Fields(mnemonic)
+
+	quizText := widget.NewRichTextFromMarkdown(`
+# Verify Your Seed Phrase
+
+Select the correct word for each position using your written copy.
+`)
+
+	positions := rand.Perm(len(words))[:quizQuestions]
+	slices.Sort(positions)
+
+	answers := make([]*widget.RadioGroup, len(positions))
+	questions := container.NewVBox()
+	for i, pos := range positions {
+		answers[i] = widget.NewRadioGroup(quizOptions(words, pos), nil)
+		answers[i].Horizontal = true
+		questions.Add(widget.NewLabel(fmt.Sprintf("Word #%d", pos+1)))
+		questions.Add(answers[i])
+	}
+
+	checkBtn := widget.NewButton("Verify", func() {
+		for i, pos := range positions {
+			if answers[i].Selected != words[pos] {
+				dialog.ShowError(errors.New("one or more words are wrong. Please check your seed phrase and try again"), s.window)
+				s.showMnemonicQuiz(mnemonic, network)
+				return
+			}
+		}
+		s.createWalletFromMnemonic(mnemonic, network)
+	})
+	checkBtn.Importance = widget.HighImportance
+
+	fullBtn := widget.NewButton("Type Full Phrase Instead", func() {
+		s.showMnemonicConfirmation(mnemonic, network)
+	})
+
+	backBtn := widget.NewButton("Back", func() {
+		s.showWalletTypeDialog(network)
+	})
+
+	content := container.NewVBox(
+		quizText,
+		widget.NewSeparator(),
+		questions,
+		widget.NewSeparator(),
+		container.NewHBox(backBtn, fullBtn, checkBtn),
+	)
+
+	s.window.SetContent(content)
+	s.window.Resize(fyne.NewSize(600, 500))
+}
+
+// quizOptions returns the word at pos and decoys from the rest of the phrase, shuffled
+func quizOptions(words []string, pos int) []string {
+	options := []string{words[pos]}
+	for _, i := range rand.Perm(len(words)) {
+		if len(options) == quizChoices {
+			break
+		}
+		if !slices.Contains(options, words[i]) {
+			options = append(options, words[i])
+		}
+	}
+	rand.Shuffle(len(options), func(i, j int) {
+		options[i], options[j] = options[j], options[i]
+	})
+	return options
+}
+
 func (s *SetupWizard) showMnemonicConfirmation(mnemonic string, network types.Network) {
 	// Ask user to confirm they've written down the mnemonic
 	confirmText := widget.NewRichTextFromMarkdown(`
@@ -270,6 +357,15 @@ func (s *SetupWizard) showImportDialog() {
 }
 
 func (s *SetupWizard) showNetworkSelection(mnemonic string) {
+	if s.network != "" {
+		if mnemonic == "" {
+			s.showWalletTypeDialog(s.network)
+		} else {
+			s.createWalletFromMnemonic(mnemonic, s.network)
+		}
+		return
+	}
+
 	networkText := widget.NewRichTextFromMarkdown(`
 # Select Network
 
@@ -391,6 +487,7 @@ func (s *SetupWizard) createWalletFromMnemonic(
 	manager := controller.NewManager()
 	manager.Wallet = walletInstance
 	manager.DataDir = s.dataDir
+	manager.ElectrumAddress = configs.DefaultElectrumAddressForNetwork(network)
 
 	// Show configuration dialog
 	s.showConfigurationDialog(manager)
@@ -401,6 +498,11 @@ func (s *SetupWizard) showConfigurationDialog(manager *controller.Manager) {
 	birthHeightEntry := widget.NewEntry()
 	birthHeightEntry.SetPlaceHolder("Leave empty for current height")
 	birthHeightLabel := widget.NewLabel("Birth Height (optional):")
+	birthHeightByDateBtn := widget.NewButton("Set by Date...", func() {
+		showBirthHeightByDateDialog(s.window, manager, func(height uint64) {
+			birthHeightEntry.SetText(fmt.Sprintf("%d", height))
+		})
+	})
 
 	// Use the block height we already queried, or fetch if not available
 	if s.currentBlockHeight > 0 && s.currentNetwork == manager.Wallet.Network {
@@ -459,44 +561,65 @@ func (s *SetupWizard) showConfigurationDialog(manager *controller.Manager) {
 			"rates. This can be used to fingerprint you. Turn off for stronger privacy.",
 	)
 
-	saveBtn := widget.NewButton("Save & Continue", func() {
-		// Parse birth height
-		if birthHeightEntry.Text != "" {
-			if height, err := strconv.Atoi(birthHeightEntry.Text); err == nil {
-				// Set the wallet's BirthHeight and LastScanHeight for new wallets
-				manager.SetBirthHeight(uint64(height), true)
-			}
-		} else {
-			// If no birth height specified, set to 0 and let the scanner handle it
-			manager.SetBirthHeight(0, true)
-		}
-
-		// Parse dust limit
-		if dustLimit, err := strconv.Atoi(dustLimitEntry.Text); err == nil {
-			manager.DustLimit = dustLimit
-		}
+	var saveBtn *widget.Button
+	saveBtn = widget.NewButton("Save & Continue", func() {
+		birthHeightText := birthHeightEntry.Text
+		saveBtn.Disable()
 
-		// Parse min change amount
-		if minChange, err := strconv.ParseUint(minChangeEntry.Text, 10, 64); err == nil {
-			manager.MinChangeAmount = minChange
-		}
-
-		// Set oracle address
-		manager.OracleAddress = oracleEntry.Text
-		manager.OracleUseTLS = useTLSCheck.Checked
-		manager.FeeEstimationEnabled = feeEstimationCheck.Checked
-
-		// Save the manager
-		if err := storage.SavePlain(s.dataDir, manager); err != nil {
-			logging.L.Err(err).
-				Str("datadir", s.dataDir).
-				Msg("failed to save wallet")
-			dialog.ShowError(fmt.Errorf("failed to save wallet: %v", err), s.window)
-			return
-		}
+		// validating the birth height asks the oracle for the chain tip, keep it off the UI thread
+		go func() {
+			// If no birth height specified, set to 0 and let the scanner handle it
+			var birthHeight uint64
+			setBirthHeight := birthHeightText == ""
+			if birthHeightText != "" {
+				if height, err := strconv.ParseUint(birthHeightText, 10, 64); err == nil {
+					height, _, err = manager.ValidateBirthHeight(height)
+					if err != nil {
+						runOnMain(func() {
+							saveBtn.Enable()
+							dialog.ShowError(fmt.Errorf("invalid birth height: %v", err), s.window)
+						})
+						return
+					}
+					birthHeight, setBirthHeight = height, true
+				}
+			}
 
-		// Call the finish callback - the main GUI will replace the window content
-		s.onFinish(manager)
+			runOnMain(func() {
+				saveBtn.Enable()
+				if setBirthHeight {
+					// Set the wallet's BirthHeight and LastScanHeight for new wallets
+					manager.SetBirthHeight(birthHeight, true)
+				}
+
+				// Parse dust limit
+				if dustLimit, err := strconv.Atoi(dustLimitEntry.Text); err == nil {
+					manager.DustLimit = dustLimit
+				}
+
+				// Parse min change amount
+				if minChange, err := strconv.ParseUint(minChangeEntry.Text, 10, 64); err == nil {
+					manager.MinChangeAmount = minChange
+				}
+
+				// Set oracle address
+				manager.OracleAddress = oracleEntry.Text
+				manager.OracleUseTLS = useTLSCheck.Checked
+				manager.FeeEstimationEnabled = feeEstimationCheck.Checked
+
+				// Save the manager
+				if err := storage.SavePlain(s.dataDir, manager); err != nil {
+					logging.L.Err(err).
+						Str("datadir", s.dataDir).
+						Msg("failed to save wallet")
+					dialog.ShowError(fmt.Errorf("failed to save wallet: %v", err), s.window)
+					return
+				}
+
+				// Call the finish callback - the main GUI will replace the window content
+				s.onFinish(manager)
+			})
+		}()
 	})
 
 	backBtn := widget.NewButton("Back", func() {
@@ -507,7 +630,7 @@ func (s *SetupWizard) showConfigurationDialog(manager *controller.Manager) {
 		widget.NewLabel("Configure your wallet:"),
 		widget.NewSeparator(),
 		birthHeightLabel,
-		birthHeightEntry,
+		container.NewBorder(nil, nil, nil, birthHeightByDateBtn, birthHeightEntry),
 		oracleLabel,
 		oracleEntry,
 		container.NewHBox(useTLSLabel, useTLSCheck),
@@ -0,0 +1,168 @@
+package gui
+
+import (
+	"bytes"
+	"cmp"
+	"slices"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/setavenger/blindbit-lib/wallet"
+)
+
+// column keys for sorting the UTXO and transaction lists
+const (
+	sortByOutpoint = "outpoint"
+	sortByLabel    = "label"
+	sortByValue    = "value"
+	sortByHeight   = "height"
+	sortByState    = "state"
+	sortByTxID     = "txid"
+	sortByAmount   = "amount"
+	sortByStatus   = "status"
+)
+
+// tableSort is the sort order picked by clicking a column header.
+// It lives on the MainGUI so the choice is kept for the session.
+// An empty column keeps the default newest-first order.
+type tableSort struct {
+	column    string
+	ascending bool
+}
+
+// toggle sorts by column, a second click on the same column reverses the order
+func (s *tableSort) toggle(column string) {
+	if s.column == column {
+		s.ascending = !s.ascending
+		return
+	}
+	s.column = column
+	s.ascending = true
+}
+
+// headerColumn is one column of a sortable header, an empty key makes it a plain label
+type headerColumn struct {
+	title string
+	key   string
+}
+
+// newSortableHeader builds the header row, clicking a sortable column calls onChange
+func newSortableHeader(state *tableSort, columns []headerColumn, onChange func()) *fyne.Container {
+	buttons := make(map[string]*widget.Button)
+
+	updateTitles := func() {
+		for _, column := range columns {
+			button, ok := buttons[column.key]
+			if !ok {
+				continue
+			}
+			title := column.title
+			if state.column == column.key {
+				if state.ascending {
+					title += " ▲"
+				} else {
+					title += " ▼"
+				}
+			}
+			button.SetText(title)
+		}
+	}
+
+	header := container.NewGridWithColumns(len(columns))
+	for _, column := range columns {
+		if column.key == "" {
+			label := widget.NewLabel(column.title)
+			label.TextStyle.Bold = true
+			header.Add(label)
+			continue
+		}
+
+		button := widget.NewButton(column.title, func() {
+			state.toggle(column.key)
+			updateTitles()
+			onChange()
+		})
+		button.Importance = widget.LowImportance
+		button.Alignment = widget.ButtonAlignLeading
+		buttons[column.key] = button
+		header.Add(button)
+	}
+	updateTitles()
+
+	return header
+}
+
+// sortUTXOs orders utxos by the picked column, ties keep their previous order
+func sortUTXOs(utxos []*wallet.OwnedUTXO, state tableSort) {
+	var compare func(a, b *wallet.OwnedUTXO) int
+	switch state.column {
+	case sortByOutpoint:
+		compare = compareOutpoints
+	case sortByLabel:
+		compare = func(a, b *wallet.OwnedUTXO) int {
+			return cmp.Compare(utxoLabelIndex(a), utxoLabelIndex(b))
+		}
+	case sortByValue:
+		compare = func(a, b *wallet.OwnedUTXO) int { return cmp.Compare(a.Amount, b.Amount) }
+	case sortByHeight:
+		compare = func(a, b *wallet.OwnedUTXO) int { return cmp.Compare(a.Height, b.Height) }
+	case sortByState:
+		compare = func(a, b *wallet.OwnedUTXO) int { return cmp.Compare(a.State, b.State) }
+	default:
+		return
+	}
+
+	slices.SortStableFunc(utxos, func(a, b *wallet.OwnedUTXO) int {
+		if state.ascending {
+			return compare(a, b)
+		}
+		return compare(b, a)
+	})
+}
+
+func compareOutpoints(a, b *wallet.OwnedUTXO) int {
+	aOutpoint, bOutpoint := a.SerialiseToOutpoint(), b.SerialiseToOutpoint()
+	return bytes.Compare(aOutpoint[:], bOutpoint[:])
+}
+
+// utxoLabelIndex returns -1 for UTXOs without label so they sort first
+func utxoLabelIndex(utxo *wallet.OwnedUTXO) int64 {
+	if utxo.Label == nil {
+		return -1
+	}
+	return int64(utxo.Label.M)
+}
+
+// sortTxItems orders transactions by the picked column, ties keep their previous order
+func sortTxItems(txs []*wallet.TxItem, state tableSort) {
+	var compare func(a, b *wallet.TxItem) int
+	switch state.column {
+	case sortByTxID:
+		compare = func(a, b *wallet.TxItem) int { return bytes.Compare(a.TxID[:], b.TxID[:]) }
+	case sortByHeight:
+		compare = func(a, b *wallet.TxItem) int { return cmp.Compare(a.ConfirmHeight, b.ConfirmHeight) }
+	case sortByAmount:
+		compare = func(a, b *wallet.TxItem) int { return cmp.Compare(a.NetAmount(), b.NetAmount()) }
+	case sortByStatus:
+		compare = func(a, b *wallet.TxItem) int { return cmp.Compare(txStatusRank(a), txStatusRank(b)) }
+	default:
+		return
+	}
+
+	slices.SortStableFunc(txs, func(a, b *wallet.TxItem) int {
+		if state.ascending {
+			return compare(a, b)
+		}
+		return compare(b, a)
+	})
+}
+
+// txStatusRank puts pending before confirmed transactions
+func txStatusRank(tx *wallet.TxItem) int {
+	if tx.ConfirmHeight > 0 {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,103 @@
+package gui
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+)
+
+// preference keys for the appearance picked in the settings
+const (
+	// one of themeOptions
+	prefTheme = "appearance.theme"
+	// factor for text and widget sizes, 1 is the default size
+	prefScale = "appearance.scale"
+)
+
+// theme options in the order shown in the settings, system follows the OS
+const (
+	themeSystem = "System"
+	themeLight  = "Light"
+	themeDark   = "Dark"
+)
+
+var themeOptions = []string{themeSystem, themeLight, themeDark}
+
+// scaleOptions are the UI scales offered in the settings, in percent
+var scaleOptions = []string{"75%", "90%", "100%", "110%", "125%", "150%", "175%", "200%"}
+
+// bounds for a stored scale, anything outside is treated as a broken preference
+const (
+	minScale = 0.5
+	maxScale = 3.0
+)
+
+// appTheme is the default theme with an optionally fixed light or dark variant and scaled sizes
+type appTheme struct {
+	fyne.Theme
+	// variant replaces the system's if fixedVariant is set
+	variant      fyne.ThemeVariant
+	fixedVariant bool
+	scale        float32
+}
+
+func (t *appTheme) Color(name fyne.ThemeColorName, variant fyne.ThemeVariant) color.Color {
+	if t.fixedVariant {
+		variant = t.variant
+	}
+	return t.Theme.Color(name, variant)
+}
+
+func (t *appTheme) Size(name fyne.ThemeSizeName) float32 {
+	return t.Theme.Size(name) * t.scale
+}
+
+// ApplyTheme sets the theme and UI scale stored in the preferences,
+// the system theme at default size if none are stored
+func ApplyTheme(app fyne.App) {
+	prefs := app.Preferences()
+
+	t := &appTheme{Theme: theme.DefaultTheme(), scale: 1}
+	switch prefs.StringWithFallback(prefTheme, themeSystem) {
+	case themeLight:
+		t.variant, t.fixedVariant = theme.VariantLight, true
+	case themeDark:
+		t.variant, t.fixedVariant = theme.VariantDark, true
+	}
+	if scale := prefs.FloatWithFallback(prefScale, 1); scale >= minScale && scale <= maxScale {
+		t.scale = float32(scale)
+	}
+
+	app.Settings().SetTheme(t)
+}
+
+// setTheme switches to the theme option right away and remembers it for the next run
+func setTheme(app fyne.App, option string) {
+	app.Preferences().SetString(prefTheme, option)
+	ApplyTheme(app)
+}
+
+// setScale applies a UI scale option such as "125%" right away and remembers it for the next run
+func setScale(app fyne.App, option string) error {
+	percent, err := strconv.Atoi(strings.TrimSuffix(strings.TrimSpace(option), "%"))
+	if err != nil {
+		return fmt.Errorf("invalid scale %q: %w", option, err)
+	}
+	scale := float64(percent) / 100
+	if scale < minScale || scale > maxScale {
+		return fmt.Errorf("scale must be between %.0f%% and %.0f%%", minScale*100, maxScale*100)
+	}
+
+	app.Preferences().SetFloat(prefScale, scale)
+	ApplyTheme(app)
+	return nil
+}
+
+// currentScaleOption formats the stored UI scale like scaleOptions
+func currentScaleOption(app fyne.App) string {
+	return fmt.Sprintf("%.0f%%", app.Preferences().FloatWithFallback(prefScale, 1)*100)
+}
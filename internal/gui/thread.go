@@ -0,0 +1,8 @@
+package gui
+
+// runOnMain applies widget updates prepared by a background goroutine.
+// Fyne 2.5 synchronises widget changes and queues the redraw on the UI thread itself,
+// so fn runs right away. Blocking work such as oracle calls belongs before runOnMain, never inside.
+func runOnMain(fn func()) {
+	fn()
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/url"
 	"sort"
+	"time"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
@@ -13,6 +14,8 @@ import (
 	"fyne.io/fyne/v2/widget"
 
 	"github.com/setavenger/blindbit-desktop/internal/configs"
+	"github.com/setavenger/blindbit-desktop/internal/storage"
+	"github.com/setavenger/blindbit-lib/logging"
 	"github.com/setavenger/blindbit-lib/wallet"
 )
 
@@ -59,12 +62,12 @@ func sortedTransactionHistory(h wallet.TxHistory) []*wallet.TxItem {
 
 // newTxHistoryRowGrid returns the 4-column row template used by transaction lists.
 func newTxHistoryRowGrid() fyne.CanvasObject {
-	return container.NewGridWithColumns(4,
+	return newContextRow(container.NewGridWithColumns(4,
 		widget.NewLabel(""), // TXID
 		widget.NewLabel(""), // Block Height
 		widget.NewLabel(""), // Net Amount
 		widget.NewLabel(""), // Status
-	)
+	))
 }
 
 // formatTxRowLabels populates the four labels in a transaction list row using
@@ -73,8 +76,9 @@ func newTxHistoryRowGrid() fyne.CanvasObject {
 func formatTxRowLabels(
 	txidLabel, heightLabel, amountLabel, statusLabel *widget.Label,
 	tx *wallet.TxItem,
+	tip uint32, target int,
 ) {
-	row := FormatTxRow(tx)
+	row := FormatTxRow(tx, tip, target)
 	txidLabel.SetText(row.TXID)
 	heightLabel.SetText(row.Height)
 	amountLabel.SetText(row.NetAmount)
@@ -91,25 +95,26 @@ This shows all transactions you have successfully broadcast.
 Click on a transaction to view details.
 `)
 
-	// Create header labels with proper alignment and styling
-	createHeaderLabel := func(text string) *widget.Label {
-		label := widget.NewLabel(text)
-		label.TextStyle.Bold = true
-		label.Alignment = fyne.TextAlignLeading
-		return label
-	}
-
-	// Create headers in a grid matching the list's 4 columns
-	headers := container.NewGridWithColumns(4,
-		createHeaderLabel("TXID"),
-		createHeaderLabel("Block Height"),
-		createHeaderLabel("Net Amount"),
-		createHeaderLabel("Status"),
-	)
+	// Search box, filters the list live
+	searchEntry := widget.NewEntry()
+	searchEntry.SetPlaceHolder(txSearchPlaceholder)
 
 	var orderedHistory []*wallet.TxItem
 	rebuildOrder := func() {
-		orderedHistory = sortedTransactionHistory(g.manager.TransactionHistory)
+		orderedHistory = sortedTransactionHistory(g.manager.GetTransactionHistory())
+		sortTxItems(orderedHistory, g.txSort)
+
+		terms := searchTerms(searchEntry.Text)
+		if len(terms) == 0 {
+			return
+		}
+		var matched []*wallet.TxItem
+		for _, tx := range orderedHistory {
+			if txMatches(tx, g.manager.TxNote(tx.TxID), terms) {
+				matched = append(matched, tx)
+			}
+		}
+		orderedHistory = matched
 	}
 	rebuildOrder()
 
@@ -127,20 +132,42 @@ Click on a transaction to view details.
 				return
 			}
 			tx := orderedHistory[id]
-			c := obj.(*fyne.Container)
+			row := obj.(*contextRow)
+			row.menu = func() *fyne.Menu { return g.txRowMenu(tx) }
+			c := row.content.(*fyne.Container)
 
 			txidLabel := c.Objects[0].(*widget.Label)
 			heightLabel := c.Objects[1].(*widget.Label)
 			amountLabel := c.Objects[2].(*widget.Label)
 			statusLabel := c.Objects[3].(*widget.Label)
 
-			formatTxRowLabels(txidLabel, heightLabel, amountLabel, statusLabel, tx)
+			formatTxRowLabels(txidLabel, heightLabel, amountLabel, statusLabel, tx,
+				g.manager.CachedChainTip(), g.manager.ConfirmationTarget)
+			if note := g.manager.TxNote(tx.TxID); note != "" {
+				txidLabel.SetText(txidLabel.Text + " — " + note)
+			}
 		},
 	)
 
 	// Store reference to transaction list for refreshing after new transactions
 	g.transactionList = txList
 
+	searchEntry.OnChanged = func(string) {
+		txList.UnselectAll()
+		txList.Refresh()
+	}
+
+	// Headers in a grid matching the list's 4 columns, click to sort
+	headers := newSortableHeader(&g.txSort, []headerColumn{
+		{title: "TXID", key: sortByTxID},
+		{title: "Block Height", key: sortByHeight},
+		{title: "Net Amount", key: sortByAmount},
+		{title: "Status", key: sortByStatus},
+	}, func() {
+		txList.UnselectAll()
+		txList.Refresh()
+	})
+
 	// Set up click handler for transaction details
 	txList.OnSelected = func(id widget.ListItemID) {
 		rebuildOrder()
@@ -159,11 +186,16 @@ Click on a transaction to view details.
 	content := container.NewBorder(
 		container.NewVBox(
 			instructionsText,
+			searchEntry,
 			widget.NewSeparator(),
 			headers,
 			widget.NewSeparator(),
 		), // top
-		nil,             // bottom
+		container.NewHBox(
+			layout.NewSpacer(),
+			widget.NewButton("Reconcile History", g.reconcileHistory),
+			widget.NewButton("Failed Broadcasts", g.showFailedBroadcasts),
+		), // bottom
 		nil,             // left
 		nil,             // right
 		scrollContainer, // center - wrapped in scroll to match UTXOs pattern
@@ -172,13 +204,92 @@ Click on a transaction to view details.
 	return content
 }
 
+// reconcileHistory repairs the history from the UTXO set without a rescan
+func (g *MainGUI) reconcileHistory() {
+	report := g.manager.ReconcileHistory()
+
+	if err := storage.SavePlain(g.manager.DataDir, g.manager); err != nil {
+		logging.L.Err(err).Msg("failed to save wallet after reconciling history")
+		dialog.ShowError(fmt.Errorf("failed to save wallet: %v", err), g.window)
+		return
+	}
+
+	if g.transactionList != nil {
+		g.transactionList.Refresh()
+	}
+	dialog.ShowInformation("Reconcile History", report.String(), g.window)
+}
+
+// showFailedBroadcasts lists transactions that were rejected on broadcast
+// and those whose broadcast has no known outcome, the latter with a retry.
+// Those are never part of the history and their inputs stay unspent.
+func (g *MainGUI) showFailedBroadcasts() {
+	pendingBroadcasts := g.manager.GetPendingBroadcasts()
+	failedBroadcasts := g.manager.GetFailedBroadcasts()
+	if len(failedBroadcasts) == 0 && len(pendingBroadcasts) == 0 {
+		dialog.ShowInformation("Failed Broadcasts", "No rejected transactions.", g.window)
+		return
+	}
+
+	var d dialog.Dialog
+	rows := container.NewVBox()
+	for i := len(pendingBroadcasts) - 1; i >= 0; i-- {
+		pending := pendingBroadcasts[i]
+
+		txidLabel := widget.NewLabel(pending.TxID)
+		txidLabel.Wrapping = fyne.TextWrapBreak
+		statusLabel := widget.NewLabel("Outcome unknown, the transaction may have been sent. " +
+			"Retrying it is safe.")
+		statusLabel.Wrapping = fyne.TextWrapWord
+		statusLabel.Importance = widget.WarningImportance
+
+		retryBtn := widget.NewButton("Retry Broadcast", func() {
+			txMetadata, recipients, err := pending.TxMetadata()
+			if err != nil {
+				g.showError(err)
+				return
+			}
+			d.Hide()
+			g.broadcastTransaction(txMetadata, recipients, nil)
+		})
+		if g.manager.IsOffline() {
+			retryBtn.Disable()
+		}
+
+		rows.Add(txidLabel)
+		rows.Add(widget.NewLabel(time.Unix(pending.Timestamp, 0).Format("2006-01-02 15:04")))
+		rows.Add(statusLabel)
+		rows.Add(container.NewHBox(retryBtn))
+		rows.Add(widget.NewSeparator())
+	}
+	for i := len(failedBroadcasts) - 1; i >= 0; i-- {
+		failed := failedBroadcasts[i]
+
+		txidLabel := widget.NewLabel(failed.TxID)
+		txidLabel.Wrapping = fyne.TextWrapBreak
+		reasonLabel := widget.NewLabel("Rejected: " + failed.Reason)
+		reasonLabel.Wrapping = fyne.TextWrapWord
+		reasonLabel.Importance = widget.DangerImportance
+
+		rows.Add(txidLabel)
+		rows.Add(widget.NewLabel(time.Unix(failed.Timestamp, 0).Format("2006-01-02 15:04")))
+		rows.Add(reasonLabel)
+		rows.Add(widget.NewSeparator())
+	}
+
+	scroll := container.NewVScroll(rows)
+	scroll.SetMinSize(fyne.NewSize(520, 300))
+
+	d = dialog.NewCustom("Failed Broadcasts", "Close", scroll, g.window)
+	d.Show()
+}
+
 func (g *MainGUI) showTransactionHistoryDetails(tx *wallet.TxItem) {
 	txidHex := hex.EncodeToString(tx.TxID[:])
 
-	status := "Pending"
-	if tx.ConfirmHeight > 0 {
-		status = "Confirmed"
-	}
+	status := FormatConfirmationStatus(
+		tx.ConfirmHeight, g.manager.CachedChainTip(), g.manager.ConfirmationTarget,
+	)
 
 	// TXID: Full 64 hex chars, monospace, allow word wrap (no truncation)
 	txidLabel := widget.NewLabel("Transaction ID:")
@@ -192,6 +303,22 @@ func (g *MainGUI) showTransactionHistoryDetails(tx *wallet.TxItem) {
 	feeLine := widget.NewLabel("Fee: " + FormatSatoshi(int64(tx.Fees())))
 	statusLine := widget.NewLabel("Status: " + status)
 
+	noteEntry := widget.NewEntry()
+	noteEntry.SetPlaceHolder("Add a note, e.g. coffee")
+	noteEntry.SetText(g.manager.TxNote(tx.TxID))
+	saveNoteBtn := widget.NewButton("Save Note", func() {
+		g.manager.SetTxNote(tx.TxID, noteEntry.Text)
+		if err := storage.SavePlain(g.manager.DataDir, g.manager); err != nil {
+			logging.L.Err(err).Msg("failed to save wallet after editing transaction note")
+			dialog.ShowError(fmt.Errorf("failed to save note: %v", err), g.window)
+			return
+		}
+		if g.transactionList != nil {
+			g.transactionList.Refresh()
+		}
+	})
+	noteLine := container.NewBorder(nil, nil, widget.NewLabel("Note:"), saveNoteBtn, noteEntry)
+
 	// TODO: Take from TX History and
 	// show both wallet internal and external UTXOs
 	// // Find output UTXOs (belonging to wallet from this transaction)
@@ -222,6 +349,7 @@ func (g *MainGUI) showTransactionHistoryDetails(tx *wallet.TxItem) {
 		amountLine,
 		feeLine,
 		statusLine,
+		noteLine,
 	}
 
 	// Add output UTXOs section if any
@@ -242,12 +370,15 @@ func (g *MainGUI) showTransactionHistoryDetails(tx *wallet.TxItem) {
 
 	// Buttons
 	copyBtn := widget.NewButton("Copy TXID", func() {
-		g.copyTxidToClipboard(txidHex)
+		g.copyIDToClipboard("TXID", txidHex)
 	})
 
 	explorerBtn := widget.NewButton("View in Explorer", func() {
 		g.openInExplorer(txidHex)
 	})
+	if !g.explorerAvailable() {
+		explorerBtn.Disable()
+	}
 
 	innerContainer := container.NewHBox(copyBtn, explorerBtn)
 	buttonLineContainer := container.NewHBox(innerContainer)
@@ -265,18 +396,37 @@ func (g *MainGUI) showTransactionHistoryDetails(tx *wallet.TxItem) {
 	d.Show()
 }
 
-func (g *MainGUI) copyTxidToClipboard(text string) {
+// copyIDToClipboard copies a txid or outpoint, kind names it in the confirmation
+func (g *MainGUI) copyIDToClipboard(kind, text string) {
 	// Copy to clipboard
 	g.window.Clipboard().SetContent(text)
 
 	// Show confirmation
-	dialog.ShowInformation("Copied", "TXID copied to clipboard!", g.window)
+	dialog.ShowInformation("Copied", kind+" copied to clipboard!", g.window)
 }
 
 func (g *MainGUI) openInExplorer(txid string) {
-	// Open mempool.space explorer using Fyne's built-in OpenURL
-	var urlStr string
-	urlStr = configs.GetMempoolSpaceURL(g.manager.GetNetwork()) + "/tx/" + txid
+	g.openExplorerURL(configs.GetExplorerTxURL(g.manager.GetNetwork(), g.manager.ExplorerURL(), txid))
+}
+
+// openOutputInExplorer opens the transaction of a UTXO with its output highlighted
+func (g *MainGUI) openOutputInExplorer(txid string, vout uint32) {
+	g.openExplorerURL(configs.GetExplorerOutputURL(g.manager.GetNetwork(), g.manager.ExplorerURL(), txid, vout))
+}
+
+// explorerAvailable is false on networks without a public or custom explorer,
+// explorer actions are disabled there
+func (g *MainGUI) explorerAvailable() bool {
+	return configs.HasPublicExplorer(g.manager.GetNetwork(), g.manager.ExplorerURL())
+}
+
+func (g *MainGUI) openExplorerURL(urlStr string) {
+	if !g.explorerAvailable() {
+		dialog.ShowInformation("Explorer Unavailable",
+			"There is no public block explorer for "+string(g.manager.GetNetwork())+
+				". Set a custom explorer URL in Settings.", g.window)
+		return
+	}
 
 	u, err := url.Parse(urlStr)
 	if err != nil {
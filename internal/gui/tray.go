@@ -0,0 +1,243 @@
+package gui
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/theme"
+
+	"github.com/setavenger/blindbit-desktop/internal/configs"
+	"github.com/setavenger/blindbit-desktop/internal/controller"
+	"github.com/setavenger/blindbit-lib/logging"
+)
+
+const trayUpdateInterval = 10 * time.Second
+
+// Tray keeps the system tray menu and icon in sync with the wallet.
+// Fyne (v2.5) has no tray tooltip, so the status is shown as the first (disabled) menu item.
+type Tray struct {
+	desk    desktop.App
+	app     fyne.App
+	window  fyne.Window
+	manager *controller.Manager
+
+	menu       *fyne.Menu
+	statusItem *fyne.MenuItem
+	toggleItem *fyne.MenuItem
+
+	idleIcon    fyne.Resource
+	syncingIcon fyne.Resource
+
+	// see SetOnVisibilityChanged
+	onVisibilityChanged func(visible bool)
+
+	mu      sync.Mutex
+	visible bool
+	syncing bool
+}
+
+// NewTray sets up the tray menu. Returns nil if the platform has no system tray.
+// manager can be nil while the setup wizard runs, see SetManager.
+func NewTray(app fyne.App, window fyne.Window, manager *controller.Manager) *Tray {
+	desk, ok := app.(desktop.App)
+	if !ok {
+		logging.L.Debug().Msg("no system tray available")
+		return nil
+	}
+
+	t := &Tray{
+		desk:        desk,
+		app:         app,
+		window:      window,
+		manager:     manager,
+		idleIcon:    app.Metadata().Icon,
+		syncingIcon: theme.ViewRefreshIcon(),
+		visible:     true,
+	}
+	if t.idleIcon == nil {
+		t.idleIcon = app.Icon()
+	}
+
+	t.statusItem = fyne.NewMenuItem("Loading...", nil)
+	t.statusItem.Disabled = true
+	t.toggleItem = fyne.NewMenuItem("Hide", t.ToggleWindow)
+
+	// Quit really exits, closing the window only hides it when running in tray
+	quitItem := fyne.NewMenuItem("Quit", app.Quit)
+	quitItem.IsQuit = true
+
+	t.menu = fyne.NewMenu("BlindBit",
+		t.statusItem,
+		fyne.NewMenuItemSeparator(),
+		t.toggleItem,
+		fyne.NewMenuItemSeparator(),
+		quitItem,
+	)
+	desk.SetSystemTrayMenu(t.menu)
+	desk.SetSystemTrayIcon(t.idleIcon)
+
+	return t
+}
+
+// SetManager attaches the wallet once it is loaded or created
+func (t *Tray) SetManager(manager *controller.Manager) {
+	t.mu.Lock()
+	t.manager = manager
+	t.mu.Unlock()
+	// the status asks the oracle for the chain tip
+	go t.update()
+}
+
+// Start refreshes the tray status periodically until the app quits
+func (t *Tray) Start() {
+	go func() {
+		ticker := time.NewTicker(trayUpdateInterval)
+		defer ticker.Stop()
+
+		t.update()
+		for range ticker.C {
+			t.update()
+			// follows the chain tip refresh interval of the settings
+			ticker.Reset(t.updateInterval())
+		}
+	}()
+}
+
+// updateInterval is the chain tip refresh interval of the wallet, trayUpdateInterval until one is loaded
+func (t *Tray) updateInterval() time.Duration {
+	t.mu.Lock()
+	manager := t.manager
+	t.mu.Unlock()
+
+	if manager == nil {
+		return trayUpdateInterval
+	}
+	return refreshInterval(manager.ChainTipRefreshSeconds, configs.DefaultChainTipRefreshSeconds)
+}
+
+// SetOnVisibilityChanged sets fn to run before the window is shown or hidden via the tray,
+// e.g. to stop the GUI updates while nobody sees them
+func (t *Tray) SetOnVisibilityChanged(fn func(visible bool)) {
+	t.mu.Lock()
+	t.onVisibilityChanged = fn
+	t.mu.Unlock()
+}
+
+// ShowWindow shows the main window and records it as visible
+func (t *Tray) ShowWindow() {
+	t.setVisible(true)
+	t.window.Show()
+	t.window.RequestFocus()
+}
+
+// HideWindow hides the main window, scanning continues in the background
+func (t *Tray) HideWindow() {
+	t.setVisible(false)
+	t.window.Hide()
+}
+
+// ToggleWindow shows the window if hidden and hides it otherwise
+func (t *Tray) ToggleWindow() {
+	t.mu.Lock()
+	visible := t.visible
+	t.mu.Unlock()
+
+	if visible {
+		t.HideWindow()
+	} else {
+		t.ShowWindow()
+	}
+}
+
+func (t *Tray) setVisible(visible bool) {
+	t.mu.Lock()
+	t.visible = visible
+	onVisibilityChanged := t.onVisibilityChanged
+	t.mu.Unlock()
+
+	if onVisibilityChanged != nil {
+		onVisibilityChanged(visible)
+	}
+
+	if visible {
+		t.toggleItem.Label = "Hide"
+	} else {
+		t.toggleItem.Label = "Show"
+	}
+	t.menu.Refresh()
+}
+
+// update recomputes the status line and switches the icon between syncing and idle
+func (t *Tray) update() {
+	status, syncing := t.status()
+
+	t.statusItem.Label = status
+	t.menu.Refresh()
+
+	t.mu.Lock()
+	changed := syncing != t.syncing
+	t.syncing = syncing
+	t.mu.Unlock()
+
+	if changed {
+		t.applyIcon()
+	}
+}
+
+// SetIcons replaces the tray icons for the synced (idle) and syncing state
+func (t *Tray) SetIcons(idle, syncing fyne.Resource) {
+	t.mu.Lock()
+	t.idleIcon = idle
+	t.syncingIcon = syncing
+	t.mu.Unlock()
+
+	t.applyIcon()
+}
+
+func (t *Tray) applyIcon() {
+	t.mu.Lock()
+	icon := t.idleIcon
+	if t.syncing {
+		icon = t.syncingIcon
+	}
+	t.mu.Unlock()
+
+	t.desk.SetSystemTrayIcon(icon)
+}
+
+// status returns "Balance: X sats — Synced Y%" and whether the wallet is behind the chain tip
+func (t *Tray) status() (string, bool) {
+	t.mu.Lock()
+	manager := t.manager
+	t.mu.Unlock()
+
+	if manager == nil || manager.Wallet == nil {
+		return "No wallet loaded", false
+	}
+
+	balance := FormatSatoshiUint64(manager.GetBalance())
+
+	tip, err := manager.GetCurrentHeight()
+	if err != nil || tip == 0 {
+		return fmt.Sprintf("Balance: %s — Sync status unknown", balance), false
+	}
+
+	progress, _ := manager.SyncProgress(tip)
+	if manager.IsOffline() {
+		return fmt.Sprintf("Balance: %s — Offline at %.1f%%", balance, progress.Overall), false
+	}
+	if manager.ScanningPausedOnBattery() && progress.Overall < 100 {
+		return fmt.Sprintf("Balance: %s — Paused on battery at %.1f%%", balance, progress.Overall), false
+	}
+	if progress.Rescanning {
+		return fmt.Sprintf("Balance: %s — Rescanning %.1f%%", balance, progress.Rescan), true
+	}
+	if progress.Overall >= 100 {
+		return fmt.Sprintf("Balance: %s — Synced 100%%", balance), false
+	}
+
+	return fmt.Sprintf("Balance: %s — Synced %.1f%%", balance, progress.Overall), true
+}
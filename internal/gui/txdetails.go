@@ -0,0 +1,84 @@
+package gui
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// showDecodedTransaction lists the inputs, outputs and scripts of tx and its raw hex,
+// for checking a transaction does what it should before it is broadcast
+func (g *MainGUI) showDecodedTransaction(tx *wire.MsgTx) {
+	decoded, err := g.manager.DecodeTransaction(tx)
+	if err != nil {
+		g.showError(fmt.Errorf("failed to decode transaction: %w", err))
+		return
+	}
+
+	wrapped := func(text string) *widget.Label {
+		label := widget.NewLabel(text)
+		label.Wrapping = fyne.TextWrapBreak
+		return label
+	}
+	heading := func(text string) *widget.Label {
+		return widget.NewLabelWithStyle(text, fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	}
+
+	rows := container.NewVBox(
+		widget.NewForm(
+			widget.NewFormItem("TxID", wrapped(decoded.TxID)),
+			widget.NewFormItem("Size", widget.NewLabel(fmt.Sprintf("%d vB", decoded.VBytes))),
+		),
+		widget.NewSeparator(),
+		heading(fmt.Sprintf("Inputs (%d)", len(decoded.Inputs))),
+	)
+	for i, in := range decoded.Inputs {
+		amount := "not a wallet coin"
+		if in.Own {
+			amount = FormatSatoshiUint64(in.Amount)
+		}
+		rows.Add(widget.NewForm(
+			widget.NewFormItem(fmt.Sprintf("#%d", i), wrapped(in.Outpoint)),
+			widget.NewFormItem("Amount", widget.NewLabel(amount)),
+		))
+	}
+
+	rows.Add(widget.NewSeparator())
+	rows.Add(heading(fmt.Sprintf("Outputs (%d)", len(decoded.Outputs))))
+	for _, out := range decoded.Outputs {
+		address := out.Address
+		if address == "" {
+			address = "non-standard script"
+		}
+		if out.Own {
+			address += " (this wallet)"
+		}
+		rows.Add(widget.NewForm(
+			widget.NewFormItem(fmt.Sprintf("#%d", out.Vout), wrapped(address)),
+			widget.NewFormItem("Amount", widget.NewLabel(FormatSatoshiUint64(out.Amount))),
+			widget.NewFormItem("Script", wrapped(out.Script)),
+		))
+	}
+
+	rows.Add(widget.NewSeparator())
+	rows.Add(heading("Raw Transaction"))
+	rows.Add(wrapped(decoded.Hex))
+
+	copyHexBtn := widget.NewButton("Copy Hex", func() {
+		g.window.Clipboard().SetContent(decoded.Hex)
+		dialog.ShowInformation("Copied", "Raw transaction copied to clipboard (hex)", g.window)
+	})
+
+	scroll := container.NewVScroll(rows)
+	scroll.SetMinSize(fyne.NewSize(640, 420))
+	content := container.NewBorder(
+		nil, container.NewHBox(layout.NewSpacer(), copyHexBtn), nil, nil, scroll,
+	)
+
+	dialog.ShowCustom("Decoded Transaction", "Close", content, g.window)
+}
@@ -4,12 +4,14 @@ import (
 	"encoding/hex"
 	"fmt"
 	"sort"
-	"time"
+	"sync"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
 	"fyne.io/fyne/v2/widget"
 
+	"github.com/setavenger/blindbit-desktop/internal/storage"
 	"github.com/setavenger/blindbit-lib/logging"
 	"github.com/setavenger/blindbit-lib/wallet"
 )
@@ -27,43 +29,44 @@ func (g *MainGUI) createUTXOsTab() fyne.CanvasObject {
 	unspentOnlyCheck := widget.NewCheck("Show only unspent UTXOs", nil)
 	unspentOnlyCheck.SetChecked(true) // Default to showing only unspent
 
-	// Create table headers with proper alignment and styling
-	createHeaderLabel := func(text string) *widget.Label {
-		label := widget.NewLabel(text)
-		label.TextStyle.Bold = true
-		label.Alignment = fyne.TextAlignLeading
-		return label
-	}
+	// Search box, filters live together with the unspent-only checkbox
+	searchEntry := widget.NewEntry()
+	searchEntry.SetPlaceHolder(utxoSearchPlaceholder)
 
-	headers := container.NewGridWithColumns(5,
-		createHeaderLabel("Outpoint"),
-		createHeaderLabel("Label"),
-		createHeaderLabel("Value"),
-		createHeaderLabel("Height"),
-		createHeaderLabel("State"),
-	)
+	// filtered is taken whenever the list asks for its length,
+	// row updates and clicks read from the same snapshot
+	var filtered []*wallet.OwnedUTXO
 
 	// UTXO list with proper columns
 	utxoList := widget.NewList(
 		func() int {
-			utxos := g.getFilteredUTXOs(unspentOnlyCheck.Checked)
-			return len(utxos)
+			filtered = g.utxoView.get(utxoViewKey{
+				unspentOnly: unspentOnlyCheck.Checked,
+				query:       searchEntry.Text,
+				sort:        g.utxoSort,
+				count:       g.manager.UTXOCount(),
+			}, g.getFilteredUTXOs)
+			return len(filtered)
 		},
 		func() fyne.CanvasObject {
-			// Create a container with 5 labels for each row
-			return container.NewGridWithColumns(5,
+			// Create a container with 6 labels for each row
+			noteLabel := widget.NewLabel("")
+			noteLabel.Truncation = fyne.TextTruncateEllipsis
+			return newContextRow(container.NewGridWithColumns(6,
 				widget.NewLabel(""), // Outpoint
 				widget.NewLabel(""), // Label
 				widget.NewLabel(""), // Value
 				widget.NewLabel(""), // Height
 				widget.NewLabel(""), // State
-			)
+				noteLabel,           // Note
+			))
 		},
 		func(id widget.ListItemID, obj fyne.CanvasObject) {
-			utxos := g.getFilteredUTXOs(unspentOnlyCheck.Checked)
-			if id < len(utxos) {
-				utxo := utxos[id]
-				container := obj.(*fyne.Container)
+			if id < len(filtered) {
+				utxo := filtered[id]
+				row := obj.(*contextRow)
+				row.menu = func() *fyne.Menu { return g.utxoRowMenu(utxo) }
+				container := row.content.(*fyne.Container)
 
 				// Get the labels from the container
 				outpointLabel := container.Objects[0].(*widget.Label)
@@ -71,29 +74,52 @@ func (g *MainGUI) createUTXOsTab() fyne.CanvasObject {
 				valueLabel := container.Objects[2].(*widget.Label)
 				heightLabel := container.Objects[3].(*widget.Label)
 				stateLabel := container.Objects[4].(*widget.Label)
+				noteLabel := container.Objects[5].(*widget.Label)
 
 				// Set the data
 				txidHex := hex.EncodeToString(utxo.Txid[:])
 				outpointLabel.SetText(fmt.Sprintf("%.8s...:%d", txidHex, utxo.Vout))
 
-				if utxo.Label != nil {
-					labelLabel.SetText(fmt.Sprintf("%d", utxo.Label.M))
-				} else {
-					labelLabel.SetText("-")
-				}
+				labelLabel.SetText(g.formatUTXOLabel(utxo))
 
 				valueLabel.SetText(FormatSatoshiUint64(utxo.Amount))
 				heightLabel.SetText(FormatHeight(utxo.Height))
-				stateLabel.SetText(utxo.State.String())
+				stateLabel.SetText(g.formatUTXOState(utxo))
+				noteLabel.SetText(g.manager.UTXONote(utxo.SerialiseToOutpoint()))
 			}
 		},
 	)
 
+	// Click a header to sort by that column
+	headers := newSortableHeader(&g.utxoSort, []headerColumn{
+		{title: "Outpoint", key: sortByOutpoint},
+		{title: "Label", key: sortByLabel},
+		{title: "Value", key: sortByValue},
+		{title: "Height", key: sortByHeight},
+		{title: "State", key: sortByState},
+		{title: "Note"},
+	}, utxoList.Refresh)
+
+	// Click a UTXO to edit its note, right click to copy its outpoint or txid
+	utxoList.OnSelected = func(id widget.ListItemID) {
+		utxoList.Unselect(id)
+		if id < len(filtered) {
+			g.showUTXONoteDialog(filtered[id], utxoList)
+		}
+	}
+
 	// Refresh button
 	refreshBtn := widget.NewButton("Refresh UTXOs", func() {
 		g.refreshUTXOs(utxoList)
 	})
 
+	// Consolidate button
+	consolidateBtn := widget.NewButton("Consolidate", g.showConsolidateDialog)
+
+	// Keep references so finished scans can refresh right away
+	g.utxoList = utxoList
+	g.utxoBalanceLabel = balanceLabel
+
 	// Update initial values
 	g.updateBalance(balanceLabel)
 
@@ -105,6 +131,9 @@ func (g *MainGUI) createUTXOsTab() fyne.CanvasObject {
 		utxoList.Refresh()
 		g.updateBalance(balanceLabel)
 	}
+	searchEntry.OnChanged = func(string) {
+		utxoList.Refresh()
+	}
 
 	// Create a scrollable container for the list
 	scrollContainer := container.NewScroll(utxoList)
@@ -118,7 +147,8 @@ func (g *MainGUI) createUTXOsTab() fyne.CanvasObject {
 			widget.NewSeparator(),
 			balanceLabel,
 			widget.NewSeparator(),
-			container.NewHBox(unspentOnlyCheck, refreshBtn),
+			container.NewHBox(unspentOnlyCheck, refreshBtn, consolidateBtn),
+			searchEntry,
 			widget.NewSeparator(),
 			headers,
 			widget.NewSeparator(),
@@ -142,40 +172,179 @@ func (g *MainGUI) updateBalance(balanceLabel *widget.Label) {
 	balanceLabel.SetText("Balance: " + FormatSatoshiUint64(total))
 }
 
-// getFilteredUTXOs returns UTXOs based on the filter setting, sorted by height (descending)
-func (g *MainGUI) getFilteredUTXOs(unspentOnly bool) []*wallet.OwnedUTXO {
+// utxoViewKey is everything the rows of the UTXO list depend on.
+// count catches new UTXOs, state changes and note edits need utxoView.invalidate.
+type utxoViewKey struct {
+	unspentOnly bool
+	query       string
+	sort        tableSort
+	count       int
+}
+
+// utxoView caches the filtered and sorted UTXOs of the list.
+// The list asks for its length on every scroll, resize and refresh,
+// filtering and sorting thousands of UTXOs each time made scrolling stutter.
+type utxoView struct {
+	mu    sync.Mutex
+	key   utxoViewKey
+	valid bool
+	utxos []*wallet.OwnedUTXO
+}
+
+// get returns the cached rows for key, build is only called when key or the wallet changed
+func (v *utxoView) get(
+	key utxoViewKey,
+	build func(unspentOnly bool, query string) []*wallet.OwnedUTXO,
+) []*wallet.OwnedUTXO {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.valid && v.key == key {
+		return v.utxos
+	}
+	v.utxos = build(key.unspentOnly, key.query)
+	v.key, v.valid = key, true
+	return v.utxos
+}
+
+// invalidate makes the next get rebuild the rows
+func (v *utxoView) invalidate() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.valid = false
+}
+
+// getFilteredUTXOs returns UTXOs based on the filter settings and search query,
+// sorted by height (descending)
+func (g *MainGUI) getFilteredUTXOs(unspentOnly bool, query string) []*wallet.OwnedUTXO {
 	var utxos []*wallet.OwnedUTXO
 	if unspentOnly {
-		utxos = g.manager.Wallet.GetUTXOs(wallet.StateUnspent)
+		utxos = g.manager.GetUTXOs(wallet.StateUnspent)
 	} else {
-		utxos = g.manager.Wallet.GetUTXOs()
+		utxos = g.manager.GetUTXOs()
+	}
+
+	if terms := searchTerms(query); len(terms) > 0 {
+		var matched []*wallet.OwnedUTXO
+		for _, utxo := range utxos {
+			if utxoMatches(utxo, g.manager.UTXONote(utxo.SerialiseToOutpoint()), terms) {
+				matched = append(matched, utxo)
+			}
+		}
+		utxos = matched
 	}
 
 	// Sort by height in descending order (newest first)
 	sort.Slice(utxos, func(i, j int) bool {
 		return utxos[i].Height > utxos[j].Height
 	})
+	sortUTXOs(utxos, g.utxoSort)
 
 	return utxos
 }
 
-// startPeriodicUTXOUpdates sets up periodic refresh of UTXO data
+// startPeriodicUTXOUpdates sets up periodic refresh of UTXO data until Cleanup
 func (g *MainGUI) startPeriodicUTXOUpdates(
 	balanceLabel *widget.Label,
 	utxoList *widget.List,
 ) {
-	ticker := time.NewTicker(60 * time.Second) // Update every 60 seconds (reduced frequency)
-	defer ticker.Stop()
-
-	for range ticker.C {
+	g.runPeriodically(g.utxoRefreshInterval, func() {
 		// Update UI components
 		g.updateBalance(balanceLabel)
+		g.utxoView.invalidate()
 		utxoList.Refresh()
-	}
+	})
 }
 
 func (g *MainGUI) refreshUTXOs(utxoList *widget.List) {
 	// Refresh the UTXO list
 	logging.L.Info().Msg("Refreshing UTXO list")
+	g.utxoView.invalidate()
 	utxoList.Refresh()
 }
+
+// refreshAfterScan shows the results of a finished scan without waiting for the periodic update.
+// Widget updates are safe from any goroutine, fyne queues the redraw on the UI thread.
+func (g *MainGUI) refreshAfterScan() {
+	if g.utxoList != nil {
+		g.refreshUTXOs(g.utxoList)
+	}
+	if g.utxoBalanceLabel != nil {
+		g.updateBalance(g.utxoBalanceLabel)
+	}
+	if g.transactionList != nil {
+		g.transactionList.Refresh()
+	}
+}
+
+// showUTXONoteDialog shows the UTXO and lets the user edit its note
+func (g *MainGUI) showUTXONoteDialog(utxo *wallet.OwnedUTXO, utxoList *widget.List) {
+	outpoint := utxo.SerialiseToOutpoint()
+
+	outpointLabel := widget.NewLabel(fmt.Sprintf("%s:%d", hex.EncodeToString(utxo.Txid[:]), utxo.Vout))
+	outpointLabel.TextStyle.Monospace = true
+	outpointLabel.Wrapping = fyne.TextWrapBreak
+
+	explorerBtn := widget.NewButton("View in Explorer", func() {
+		g.openOutputInExplorer(hex.EncodeToString(utxo.Txid[:]), utxo.Vout)
+	})
+	if !g.explorerAvailable() {
+		explorerBtn.Disable()
+	}
+
+	noteEntry := widget.NewEntry()
+	noteEntry.SetPlaceHolder("e.g. salary")
+	noteEntry.SetText(g.manager.UTXONote(outpoint))
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Outpoint", outpointLabel),
+		widget.NewFormItem("", container.NewHBox(explorerBtn)),
+		widget.NewFormItem("Value", widget.NewLabel(FormatSatoshiUint64(utxo.Amount))),
+		widget.NewFormItem("Label", widget.NewLabel(g.formatUTXOLabel(utxo))),
+		widget.NewFormItem("Height", widget.NewLabel(FormatHeight(utxo.Height))),
+		widget.NewFormItem("State", widget.NewLabel(g.formatUTXOState(utxo))),
+		widget.NewFormItem("Confirmations", widget.NewLabel(FormatConfirmationStatus(
+			int(utxo.Height), g.manager.CachedChainTip(), g.manager.ConfirmationTarget,
+		))),
+		widget.NewFormItem("Note", noteEntry),
+	}
+
+	d := dialog.NewForm("UTXO Details", "Save Note", "Close", items, func(save bool) {
+		if !save {
+			return
+		}
+		g.manager.SetUTXONote(outpoint, noteEntry.Text)
+		if err := storage.SavePlain(g.manager.DataDir, g.manager); err != nil {
+			logging.L.Err(err).Msg("failed to save wallet after editing UTXO note")
+			dialog.ShowError(fmt.Errorf("failed to save note: %v", err), g.window)
+			return
+		}
+		// notes are searchable
+		g.utxoView.invalidate()
+		utxoList.Refresh()
+	}, g.window)
+	d.Resize(fyne.NewSize(600, d.MinSize().Height))
+	d.Show()
+}
+
+// formatUTXOState marks unspent UTXOs which are too young to be spent
+func (g *MainGUI) formatUTXOState(utxo *wallet.OwnedUTXO) string {
+	if g.manager.IsImmature(utxo) {
+		return "immature"
+	}
+	return utxo.State.String()
+}
+
+// formatUTXOLabel names the label a UTXO was received on, "-" for the main address
+func (g *MainGUI) formatUTXOLabel(utxo *wallet.OwnedUTXO) string {
+	switch {
+	case utxo.Label == nil:
+		return "-"
+	case utxo.Label.M == 0:
+		return "change"
+	}
+	if name := g.manager.LabelName(utxo.Label.M); name != "" {
+		return fmt.Sprintf("%d (%s)", utxo.Label.M, name)
+	}
+	return fmt.Sprintf("%d", utxo.Label.M)
+}
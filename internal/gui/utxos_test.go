@@ -0,0 +1,85 @@
+package gui
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/setavenger/blindbit-lib/wallet"
+)
+
+// newUTXOTestGUI returns a GUI whose wallet holds n UTXOs, every third one spent
+func newUTXOTestGUI(tb testing.TB, n int) *MainGUI {
+	m := newTestManager(tb)
+	for i := 0; i < n; i++ {
+		utxo := &wallet.OwnedUTXO{
+			Vout:   uint32(i % 4),
+			Amount: uint64(1_000 + i*7%50_000),
+			Height: uint32(800_000 + i%1_000),
+			State:  wallet.StateUnspent,
+		}
+		binary.BigEndian.PutUint32(utxo.Txid[:], uint32(i))
+		if i%3 == 0 {
+			utxo.State = wallet.StateSpent
+		}
+		m.Wallet.UTXOs = append(m.Wallet.UTXOs, utxo)
+	}
+	return &MainGUI{manager: m, utxoSort: tableSort{column: sortByValue}}
+}
+
+// rows asks for the rows like the UTXO list does on every scroll and refresh
+func (g *MainGUI) rows(query string) []*wallet.OwnedUTXO {
+	return g.utxoView.get(utxoViewKey{
+		unspentOnly: true,
+		query:       query,
+		sort:        g.utxoSort,
+		count:       g.manager.UTXOCount(),
+	}, g.getFilteredUTXOs)
+}
+
+func TestUTXOViewRebuildsOnlyOnChange(t *testing.T) {
+	g := newUTXOTestGUI(t, 100)
+
+	builds := 0
+	build := func(unspentOnly bool, query string) []*wallet.OwnedUTXO {
+		builds++
+		return g.getFilteredUTXOs(unspentOnly, query)
+	}
+	key := utxoViewKey{unspentOnly: true, sort: g.utxoSort, count: g.manager.UTXOCount()}
+
+	for i := 0; i < 50; i++ {
+		g.utxoView.get(key, build)
+	}
+	if builds != 1 {
+		t.Fatalf("scrolling rebuilt the rows %d times", builds)
+	}
+
+	key.query = "8000"
+	g.utxoView.get(key, build)
+	g.utxoView.invalidate()
+	g.utxoView.get(key, build)
+	if builds != 3 {
+		t.Fatalf("expected a rebuild for the new query and after invalidate, got %d builds", builds)
+	}
+}
+
+// BenchmarkUTXOListScroll is the list asking for its rows while scrolling through 5,000 UTXOs
+func BenchmarkUTXOListScroll(b *testing.B) {
+	g := newUTXOTestGUI(b, 5_000)
+	g.rows("")
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g.rows("")
+	}
+}
+
+// BenchmarkUTXOListRebuild filters and sorts 5,000 UTXOs, done once per change of data or filter
+func BenchmarkUTXOListRebuild(b *testing.B) {
+	g := newUTXOTestGUI(b, 5_000)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g.utxoView.invalidate()
+		g.rows("8005")
+	}
+}
@@ -0,0 +1,105 @@
+package gui
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// preference keys for the window state, stored via fyne preferences
+const (
+	prefWindowWidth  = "window.width"
+	prefWindowHeight = "window.height"
+	prefActiveTab    = "window.active_tab"
+	// scroll offsets of the long lists
+	prefScrollTransactions = "window.scroll.transactions"
+	prefScrollUTXOs        = "window.scroll.utxos"
+)
+
+// DefaultWindowSize is used on first launch or if the stored size is unusable
+var DefaultWindowSize = fyne.NewSize(1000, 750)
+
+// minWindowSize guards against restoring a collapsed window
+var minWindowSize = fyne.NewSize(400, 300)
+
+// RestoreWindowSize resizes window to the size stored on the last run and centers it.
+// Fyne (v2.5) exposes no window position, so the window is always centered.
+// This also keeps it on a visible screen if a monitor was disconnected since.
+func RestoreWindowSize(app fyne.App, window fyne.Window) {
+	prefs := app.Preferences()
+
+	size := fyne.NewSize(
+		float32(prefs.FloatWithFallback(prefWindowWidth, float64(DefaultWindowSize.Width))),
+		float32(prefs.FloatWithFallback(prefWindowHeight, float64(DefaultWindowSize.Height))),
+	)
+	if size.Width < minWindowSize.Width || size.Height < minWindowSize.Height {
+		size = DefaultWindowSize
+	}
+
+	window.Resize(size)
+	window.CenterOnScreen()
+}
+
+// SaveWindowSize stores the current window size for the next run
+func SaveWindowSize(app fyne.App, window fyne.Window) {
+	size := window.Canvas().Size()
+	if size.Width < minWindowSize.Width || size.Height < minWindowSize.Height {
+		// window was never laid out or is minimised, keep the previous value
+		return
+	}
+
+	prefs := app.Preferences()
+	prefs.SetFloat(prefWindowWidth, float64(size.Width))
+	prefs.SetFloat(prefWindowHeight, float64(size.Height))
+}
+
+// restoreActiveTab selects the tab which was active on the last run
+// and remembers future selections
+func (g *MainGUI) restoreActiveTab() {
+	prefs := g.app.Preferences()
+
+	index := prefs.IntWithFallback(prefActiveTab, 0)
+	if index > 0 && index < len(g.tabs.Items) {
+		g.tabs.SelectIndex(index)
+	}
+
+	g.tabs.OnSelected = func(*container.TabItem) {
+		prefs.SetInt(prefActiveTab, g.tabs.SelectedIndex())
+		// lists of tabs shown for the first time are laid out now
+		g.RestoreScrollPositions()
+	}
+}
+
+// scrollPositions pairs the long lists with the preference keeping their offset
+func (g *MainGUI) scrollPositions() map[string]*widget.List {
+	return map[string]*widget.List{
+		prefScrollTransactions: g.transactionList,
+		prefScrollUTXOs:        g.utxoList,
+	}
+}
+
+// saveScrollPositions stores the scroll offsets of the lists, see RestoreScrollPositions
+func (g *MainGUI) saveScrollPositions() {
+	prefs := g.app.Preferences()
+	for key, list := range g.scrollPositions() {
+		if list == nil || !g.scrollRestored[key] {
+			// never shown, the stored offset still applies
+			continue
+		}
+		prefs.SetFloat(key, float64(list.GetScrollOffset()))
+	}
+}
+
+// RestoreScrollPositions scrolls the lists back to where they were when the previous GUI was cleaned up.
+// A list can only scroll once it is laid out, call it after the content is set on the window.
+// Lists on tabs not shown yet are restored when their tab is selected.
+func (g *MainGUI) RestoreScrollPositions() {
+	prefs := g.app.Preferences()
+	for key, list := range g.scrollPositions() {
+		if list == nil || g.scrollRestored[key] || list.Size().Height == 0 {
+			continue
+		}
+		list.ScrollToOffset(float32(prefs.Float(key)))
+		g.scrollRestored[key] = true
+	}
+}
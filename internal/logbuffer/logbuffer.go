@@ -0,0 +1,95 @@
+// Package logbuffer keeps the most recent log lines in memory for the log viewer
+package logbuffer
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/rs/zerolog"
+	"github.com/setavenger/blindbit-lib/logging"
+)
+
+// Capacity is the number of log lines kept, older lines are dropped
+const Capacity = 2000
+
+// Entry is a single formatted log line
+type Entry struct {
+	Level zerolog.Level
+	Text  string
+}
+
+// ring is a fixed size buffer of log entries, fed by zerolog
+type ring struct {
+	mu      sync.Mutex
+	entries []Entry
+	next    int
+	full    bool
+}
+
+var recent = &ring{entries: make([]Entry, Capacity)}
+
+// Write takes one JSON log line from zerolog and stores it formatted like the console output
+func (r *ring) Write(p []byte) (int, error) {
+	var fields map[string]any
+	level := zerolog.NoLevel
+	if err := json.Unmarshal(p, &fields); err == nil {
+		if name, ok := fields[zerolog.LevelFieldName].(string); ok {
+			if parsed, err := zerolog.ParseLevel(name); err == nil {
+				level = parsed
+			}
+		}
+	}
+
+	var text bytes.Buffer
+	console := zerolog.ConsoleWriter{Out: &text, NoColor: true, TimeFormat: "15:04:05"}
+	if _, err := console.Write(p); err != nil {
+		text.Reset()
+		text.Write(p)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries[r.next] = Entry{Level: level, Text: strings.TrimRight(text.String(), "\n")}
+	r.next = (r.next + 1) % len(r.entries)
+	if r.next == 0 {
+		r.full = true
+	}
+	return len(p), nil
+}
+
+// Entries returns the buffered lines at or above minLevel, oldest first
+func Entries(minLevel zerolog.Level) []Entry {
+	recent.mu.Lock()
+	defer recent.mu.Unlock()
+
+	ordered := recent.entries[:recent.next]
+	if recent.full {
+		ordered = append(slices.Clone(recent.entries[recent.next:]), recent.entries[:recent.next]...)
+	}
+
+	var entries []Entry
+	for _, entry := range ordered {
+		if entry.Level >= minLevel {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// Attach rebuilds logging.L with the buffer as additional output, keeping console and file output.
+// blindbit-lib rebuilds the logger when file logging is switched, call Attach again after that.
+func Attach() {
+	var out io.Writer = zerolog.ConsoleWriter{
+		Out:        os.Stdout,
+		TimeFormat: "2006-01-02T15:04:05.000000Z07:00",
+	}
+	if logging.LogToFile && logging.FileWriter != nil {
+		out = io.MultiWriter(out, logging.FileWriter)
+	}
+	logging.L = zerolog.New(io.MultiWriter(out, recent)).With().Caller().Timestamp().Logger()
+}
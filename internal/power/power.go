@@ -0,0 +1,19 @@
+// Package power tells whether the computer runs on battery
+package power
+
+import "errors"
+
+// ErrUnsupported is returned where the power source can't be read,
+// on computers without a battery and on platforms without support
+var ErrUnsupported = errors.New("power source is not available")
+
+// OnBattery reports whether the computer runs on battery instead of AC power
+func OnBattery() (bool, error) {
+	return onBattery()
+}
+
+// Supported reports whether OnBattery works on this computer
+func Supported() bool {
+	_, err := onBattery()
+	return err == nil
+}
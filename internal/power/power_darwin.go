@@ -0,0 +1,19 @@
+package power
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// onBattery asks pmset for the power source, e.g. "Now drawing from 'Battery Power'"
+func onBattery() (bool, error) {
+	out, err := exec.Command("pmset", "-g", "batt").Output()
+	if err != nil {
+		return false, ErrUnsupported
+	}
+	text := string(out)
+	if !strings.Contains(text, "InternalBattery") {
+		return false, ErrUnsupported
+	}
+	return strings.Contains(text, "'Battery Power'"), nil
+}
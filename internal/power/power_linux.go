@@ -0,0 +1,55 @@
+package power
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const powerSupplyDir = "/sys/class/power_supply"
+
+// onBattery reads the power supplies from sysfs.
+// With an AC adapter present its online flag decides, otherwise a discharging battery.
+func onBattery() (bool, error) {
+	supplies, err := os.ReadDir(powerSupplyDir)
+	if err != nil {
+		return false, ErrUnsupported
+	}
+
+	var hasBattery, hasMains, mainsOnline, discharging bool
+	for _, supply := range supplies {
+		dir := filepath.Join(powerSupplyDir, supply.Name())
+		switch readAttr(dir, "type") {
+		case "Mains":
+			hasMains = true
+			if readAttr(dir, "online") == "1" {
+				mainsOnline = true
+			}
+		case "Battery":
+			// peripherals such as mice report batteries too
+			if readAttr(dir, "scope") == "Device" {
+				continue
+			}
+			hasBattery = true
+			if readAttr(dir, "status") == "Discharging" {
+				discharging = true
+			}
+		}
+	}
+
+	if !hasBattery {
+		return false, ErrUnsupported
+	}
+	if hasMains {
+		return !mainsOnline, nil
+	}
+	return discharging, nil
+}
+
+func readAttr(dir, name string) string {
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
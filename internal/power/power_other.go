@@ -0,0 +1,7 @@
+//go:build !linux && !darwin && !windows
+
+package power
+
+func onBattery() (bool, error) {
+	return false, ErrUnsupported
+}
@@ -0,0 +1,41 @@
+package power
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+var procGetSystemPowerStatus = syscall.NewLazyDLL("kernel32.dll").NewProc("GetSystemPowerStatus")
+
+// systemPowerStatus is SYSTEM_POWER_STATUS of the Windows API
+type systemPowerStatus struct {
+	ACLineStatus        byte
+	BatteryFlag         byte
+	BatteryLifePercent  byte
+	SystemStatusFlag    byte
+	BatteryLifeTime     uint32
+	BatteryFullLifeTime uint32
+}
+
+const (
+	acLineOffline  = 0
+	acLineOnline   = 1
+	batteryMissing = 128
+)
+
+// onBattery reads the AC line status via GetSystemPowerStatus
+func onBattery() (bool, error) {
+	var status systemPowerStatus
+	ok, _, _ := procGetSystemPowerStatus.Call(uintptr(unsafe.Pointer(&status)))
+	if ok == 0 || status.BatteryFlag&batteryMissing != 0 {
+		return false, ErrUnsupported
+	}
+	switch status.ACLineStatus {
+	case acLineOffline:
+		return true, nil
+	case acLineOnline:
+		return false, nil
+	}
+	// unknown status
+	return false, ErrUnsupported
+}
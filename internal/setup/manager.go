@@ -23,7 +23,7 @@ func NewManagerWithDataDir(dataDir string) (*controller.Manager, bool, error) {
 
 	// Ensure data directory exists
 	if err := os.MkdirAll(dataDir, 0755); err != nil {
-		fmt.Printf("Error creating data directory: %v\n", err)
+		logging.L.Err(err).Str("datadir", dataDir).Msg("failed to create data directory")
 		return nil, false, fmt.Errorf("failed to create data directory: %w", err)
 	}
 
@@ -0,0 +1,185 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/setavenger/blindbit-lib/logging"
+)
+
+// ErrTargetNotEmpty is returned by MoveDataDir when the target already holds files
+var ErrTargetNotEmpty = errors.New("target directory is not empty")
+
+// MoveDataDir copies everything in src to dst, verifies the copy and only then removes src.
+// The copy goes to a staging directory next to dst which is renamed once verified,
+// so an interrupted move leaves src intact and dst absent. A stale staging directory
+// of an earlier attempt is replaced. Stop all writes to src before calling it.
+func MoveDataDir(src, dst string) error {
+	src, err := filepath.Abs(src)
+	if err != nil {
+		return err
+	}
+	dst, err = filepath.Abs(dst)
+	if err != nil {
+		return err
+	}
+	if src == dst {
+		return errors.New("target is the current data directory")
+	}
+	if strings.HasPrefix(dst, src+string(filepath.Separator)) {
+		return errors.New("target is inside the current data directory")
+	}
+
+	entries, err := os.ReadDir(dst)
+	switch {
+	case err == nil && len(entries) > 0:
+		return fmt.Errorf("%w: %s", ErrTargetNotEmpty, dst)
+	case err != nil && !os.IsNotExist(err):
+		return fmt.Errorf("failed to check target directory: %w", err)
+	}
+
+	staging := filepath.Join(filepath.Dir(dst), "."+filepath.Base(dst)+".moving")
+	if err := os.RemoveAll(staging); err != nil {
+		return fmt.Errorf("failed to remove staging directory of an earlier move: %w", err)
+	}
+	if err := os.MkdirAll(staging, 0700); err != nil {
+		return fmt.Errorf("failed to create staging directory: %w", err)
+	}
+
+	copied, err := copyTree(src, staging)
+	if err == nil {
+		err = verifyTree(src, staging, copied)
+	}
+	if err == nil {
+		// an empty target directory is replaced by the staging directory
+		if removeErr := os.Remove(dst); removeErr != nil && !os.IsNotExist(removeErr) {
+			err = removeErr
+		}
+	}
+	if err == nil {
+		err = os.Rename(staging, dst)
+	}
+	if err != nil {
+		if removeErr := os.RemoveAll(staging); removeErr != nil {
+			logging.L.Warn().Err(removeErr).Str("path", staging).Msg("failed to clean up staging directory")
+		}
+		return fmt.Errorf("failed to move data directory, %s is unchanged: %w", src, err)
+	}
+
+	logging.L.Info().Str("from", src).Str("to", dst).Int("files", len(copied)).Msg("moved data directory")
+
+	// only what was copied is removed, src itself only if nothing else is left
+	for i := len(copied) - 1; i >= 0; i-- {
+		if err := os.Remove(filepath.Join(src, copied[i])); err != nil {
+			logging.L.Warn().Err(err).Str("path", copied[i]).Msg("failed to remove moved file")
+		}
+	}
+	if err := os.Remove(src); err != nil {
+		logging.L.Warn().Err(err).Str("path", src).Msg("old data directory was not removed")
+	}
+	return nil
+}
+
+// copyTree copies files and directories of src into dst and returns their paths relative to src,
+// parents before children
+func copyTree(src, dst string) ([]string, error) {
+	var copied []string
+	err := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil || rel == "." {
+			return err
+		}
+
+		target := filepath.Join(dst, rel)
+		switch {
+		case d.IsDir():
+			if err := os.Mkdir(target, 0700); err != nil {
+				return err
+			}
+		case d.Type().IsRegular():
+			if err := copyFile(path, target); err != nil {
+				return err
+			}
+		default:
+			logging.L.Warn().Str("path", path).Msg("skipping special file in data directory")
+			return nil
+		}
+		copied = append(copied, rel)
+		return nil
+	})
+	return copied, err
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(out, in); err == nil {
+		err = out.Sync()
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// verifyTree compares the checksums of the copied files
+func verifyTree(src, dst string, paths []string) error {
+	for _, rel := range paths {
+		info, err := os.Stat(filepath.Join(src, rel))
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			continue
+		}
+
+		want, err := fileChecksum(filepath.Join(src, rel))
+		if err != nil {
+			return err
+		}
+		got, err := fileChecksum(filepath.Join(dst, rel))
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(want, got) {
+			return fmt.Errorf("copy of %s does not match the original", rel)
+		}
+	}
+	return nil
+}
+
+func fileChecksum(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
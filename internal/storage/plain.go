@@ -2,18 +2,37 @@
 package storage
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
+	"time"
 
 	"github.com/setavenger/blindbit-desktop/internal/controller"
 	"github.com/setavenger/blindbit-lib/logging"
 	"github.com/setavenger/blindbit-lib/wallet"
 )
 
-const walletDataFilename = "wallet.dat"
+const (
+	walletDataFilename = "wallet.dat"
+	// walletBackupFilename holds the previous good wallet file, see LoadPlain
+	walletBackupFilename = "wallet.dat.bak"
+)
+
+// ErrWalletCorrupt is returned by LoadPlain when a wallet is present but neither the file nor its backup can be loaded
+// and there is no usable backup either
+var ErrWalletCorrupt = errors.New("wallet file is corrupt")
+
+// saveMu serialises saves, the channel handlers and the GUI save concurrently
+// and a slower save of older state must not replace a newer file
+var saveMu sync.Mutex
 
 func SavePlain(datadir string, m *controller.Manager) error {
+	saveMu.Lock()
+	defer saveMu.Unlock()
+
 	logging.L.Trace().Str("datadir", datadir).Msg("saving wallet")
 	binaryData, err := m.Serialise()
 	if err != nil {
@@ -26,7 +45,14 @@ func SavePlain(datadir string, m *controller.Manager) error {
 	// Write to file
 	walletPath := filepath.Join(datadir, walletDataFilename)
 
-	if err := os.WriteFile(walletPath, binaryData, 0600); err != nil {
+	// a failed backup must not stop the save itself
+	if err := backupWalletFile(walletPath, filepath.Join(datadir, walletBackupFilename)); err != nil {
+		logging.L.Warn().Err(err).
+			Str("path", walletPath).
+			Msg("failed to back up previous wallet file")
+	}
+
+	if err := writeFileAtomic(walletPath, binaryData, 0600); err != nil {
 		logging.L.Err(err).
 			Str("datadir", datadir).
 			Str("path", walletPath).
@@ -34,32 +60,120 @@ func SavePlain(datadir string, m *controller.Manager) error {
 		return fmt.Errorf("failed to write wallet file: %w", err)
 	}
 
-	logging.L.Info().Str("path", walletPath).Msg("successfully wrote wallet file")
+	// saves run every few seconds while scanning
+	logging.L.Debug().Str("path", walletPath).Msg("successfully wrote wallet file")
 	return nil
 }
 
+// LoadPlain loads the wallet file from datadir.
+// If it cannot be read or parsed the backup of the previous save is loaded instead
+// and the manager is marked with RestoredFromBackup.
+// Without both files the error wraps os.ErrNotExist, if a wallet is present but unusable
+// it wraps ErrWalletCorrupt together with both failures.
 func LoadPlain(datadir string) (m *controller.Manager, err error) {
 	logging.L.Trace().Str("datadir", datadir).Msg("loading wallet")
-	data, err := os.ReadFile(filepath.Join(datadir, walletDataFilename))
+	walletPath := filepath.Join(datadir, walletDataFilename)
+	m, err = loadPlainFile(walletPath)
+	if err == nil {
+		logging.L.Info().Str("datadir", datadir).Msg("successfully loaded wallet")
+		return m, nil
+	}
+
+	backupPath := filepath.Join(datadir, walletBackupFilename)
+	m, backupErr := loadPlainFile(backupPath)
+	if backupErr != nil {
+		logging.L.Err(backupErr).
+			Str("path", backupPath).
+			Msg("failed to load wallet backup")
+		if errors.Is(err, os.ErrNotExist) && errors.Is(backupErr, os.ErrNotExist) {
+			// there is no wallet
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %w, backup: %w", ErrWalletCorrupt, err, backupErr)
+	}
+
+	logging.L.Warn().Err(err).
+		Str("path", walletPath).
+		Str("backup", backupPath).
+		Msg("wallet file unreadable, loaded the backup of the previous save")
+	m.RestoredFromBackup = true
+	return m, nil
+}
+
+func loadPlainFile(path string) (*controller.Manager, error) {
+	data, err := os.ReadFile(path)
 	if err != nil {
 		logging.L.Err(err).
-			Str("datadir", datadir).
-			Str("path", filepath.Join(datadir, walletDataFilename)).
+			Str("path", path).
 			Msg("failed to load wallet file")
 		return nil, err
 	}
 
-	m = new(controller.Manager)
+	m := new(controller.Manager)
 	m.Wallet = wallet.InitWallet()
 	err = m.DeSerialise(data)
 	if err != nil {
 		logging.L.Err(err).
-			Str("datadir", datadir).
-			Str("path", filepath.Join(datadir, walletDataFilename)).
+			Str("path", path).
 			Msg("failed to deserialise wallet data")
-		return nil, err
+		return nil, fmt.Errorf("failed to parse %s: %w", filepath.Base(path), err)
+	}
+	return m, nil
+}
+
+// SetAsideCorruptWallet renames an unreadable wallet file so a new wallet can be set up in datadir.
+// The file is kept in case it can be repaired by hand, the new path is returned.
+func SetAsideCorruptWallet(datadir string) (string, error) {
+	walletPath := filepath.Join(datadir, walletDataFilename)
+	corruptPath := fmt.Sprintf("%s.corrupt-%d", walletPath, time.Now().Unix())
+	if err := os.Rename(walletPath, corruptPath); err != nil {
+		return "", fmt.Errorf("failed to move corrupt wallet file: %w", err)
+	}
+	return corruptPath, nil
+}
+
+// backupWalletFile copies the current wallet file to backupPath before it gets replaced.
+// Only a file that parses is copied, so a damaged file never overwrites a good backup.
+func backupWalletFile(walletPath, backupPath string) error {
+	data, err := os.ReadFile(walletPath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !json.Valid(data) {
+		return fmt.Errorf("current wallet file is not valid JSON, keeping the old backup")
+	}
+	return writeFileAtomic(backupPath, data, 0600)
+}
+
+// writeFileAtomic writes to a temp file in the same directory and renames it over path,
+// so a crash mid-write never leaves a truncated wallet file behind
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+
+	// no-op once the rename succeeded
+	defer os.Remove(tmpPath)
+
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	if err = os.Chmod(tmpPath, perm); err != nil {
+		return err
 	}
 
-	logging.L.Info().Str("datadir", datadir).Msg("successfully loaded wallet")
-	return m, err
+	return os.Rename(tmpPath, path)
 }
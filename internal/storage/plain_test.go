@@ -0,0 +1,113 @@
+package storage
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/setavenger/blindbit-desktop/internal/controller"
+	"github.com/setavenger/blindbit-lib/wallet"
+)
+
+func testManager(scanHeight uint64) *controller.Manager {
+	m := controller.NewManager()
+	m.Wallet = wallet.InitWallet()
+	m.Wallet.LastScanHeight = scanHeight
+	return m
+}
+
+// truncateWalletFile leaves the first half of the wallet file, like a write cut off by a crash
+func truncateWalletFile(t *testing.T, datadir string) {
+	t.Helper()
+	walletPath := filepath.Join(datadir, walletDataFilename)
+	info, err := os.Stat(walletPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Truncate(walletPath, info.Size()/2); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadPlainPartialWriteFallsBackToBackup(t *testing.T) {
+	datadir := t.TempDir()
+	if err := SavePlain(datadir, testManager(100)); err != nil {
+		t.Fatal(err)
+	}
+	// the second save keeps the first one as backup
+	if err := SavePlain(datadir, testManager(200)); err != nil {
+		t.Fatal(err)
+	}
+	truncateWalletFile(t, datadir)
+
+	m, err := LoadPlain(datadir)
+	if err != nil {
+		t.Fatalf("expected the backup to be loaded, got %v", err)
+	}
+	if !m.RestoredFromBackup {
+		t.Fatal("manager is not marked as restored from backup")
+	}
+	if got := m.Wallet.LastScanHeight; got != 100 {
+		t.Fatalf("scan height = %d, want 100 from the backup", got)
+	}
+
+	// a save over the damaged file must not replace the good backup with it
+	if err = SavePlain(datadir, testManager(300)); err != nil {
+		t.Fatal(err)
+	}
+	truncateWalletFile(t, datadir)
+	if m, err = LoadPlain(datadir); err != nil || m.Wallet.LastScanHeight != 100 {
+		t.Fatalf("expected the backup of height 100, got %v", err)
+	}
+}
+
+func TestLoadPlainPartialWriteWithoutBackup(t *testing.T) {
+	datadir := t.TempDir()
+	if err := SavePlain(datadir, testManager(100)); err != nil {
+		t.Fatal(err)
+	}
+	truncateWalletFile(t, datadir)
+
+	if _, err := LoadPlain(datadir); !errors.Is(err, ErrWalletCorrupt) {
+		t.Fatalf("expected ErrWalletCorrupt, got %v", err)
+	}
+}
+
+func TestLoadPlainUnreadableWithoutBackup(t *testing.T) {
+	datadir := t.TempDir()
+	// reading a directory fails, unlike a missing file the wallet is there but unusable
+	if err := os.Mkdir(filepath.Join(datadir, walletDataFilename), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := LoadPlain(datadir); !errors.Is(err, ErrWalletCorrupt) {
+		t.Fatalf("expected ErrWalletCorrupt, got %v", err)
+	}
+}
+
+func TestLoadPlainWithoutWallet(t *testing.T) {
+	_, err := LoadPlain(t.TempDir())
+	if !errors.Is(err, os.ErrNotExist) || errors.Is(err, ErrWalletCorrupt) {
+		t.Fatalf("expected a missing wallet, got %v", err)
+	}
+}
+
+func TestSavePlainLeavesNoTempFiles(t *testing.T) {
+	datadir := t.TempDir()
+	for i := 0; i < 3; i++ {
+		if err := SavePlain(datadir, testManager(uint64(i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries, err := os.ReadDir(datadir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		if name := entry.Name(); name != walletDataFilename && name != walletBackupFilename {
+			t.Errorf("unexpected file %s in datadir", name)
+		}
+	}
+}